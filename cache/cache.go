@@ -0,0 +1,522 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// cache contains a generic, in-memory cache with LRU or LFU eviction
+// and optional per-entry expiration.
+package cache
+
+import (
+	"container/heap"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/MKuranowski/go-extra-lib/clock"
+	"github.com/MKuranowski/go-extra-lib/iter"
+)
+
+// Policy decides which entries are evicted first once a [Cache] is over its capacity.
+type Policy uint8
+
+const (
+	// LRU evicts the least-recently used entries first.
+	LRU Policy = iota
+
+	// LFU evicts the least-frequently used entries first.
+	// Ties are broken by evicting the least-recently used entry.
+	LFU
+)
+
+// Reason explains why an entry was removed from a [Cache].
+type Reason uint8
+
+const (
+	// Evicted means the entry was removed to keep the cache within its Capacity.
+	Evicted Reason = iota
+
+	// Expired means the entry has outlived its time-to-live.
+	Expired
+
+	// Removed means the entry was explicitly removed by Remove or Clear.
+	Removed
+
+	// Replaced means a new value was set for the same key.
+	Replaced
+)
+
+func (r Reason) String() string {
+	switch r {
+	case Evicted:
+		return "Evicted"
+	case Expired:
+		return "Expired"
+	case Removed:
+		return "Removed"
+	case Replaced:
+		return "Replaced"
+	default:
+		return fmt.Sprintf("Reason(%d)", r)
+	}
+}
+
+// Stats contains counters describing the usage of a [Cache].
+type Stats struct {
+	// Hits is the number of lookups which found a live entry.
+	Hits uint64
+
+	// Misses is the number of lookups which haven't found a live entry.
+	Misses uint64
+
+	// Loads is the number of times a load function was called by GetOrLoad.
+	Loads uint64
+
+	// LoadErrors is the number of times a load function has returned an error.
+	LoadErrors uint64
+
+	// Evictions is the number of entries removed due to the capacity limit.
+	Evictions uint64
+
+	// Expirations is the number of entries removed due to their time-to-live running out.
+	Expirations uint64
+}
+
+// HitRatio returns Hits / (Hits + Misses), or 0 if there were no lookups.
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	cost    int
+	expires time.Time // zero value means the entry never expires
+	freq    uint64
+	tick    uint64 // value of Cache.tick when the entry was last accessed
+	index   int    // position in the entryHeap
+}
+
+type entryHeap[K comparable, V any] struct {
+	entries []*entry[K, V]
+	policy  Policy
+}
+
+func (h *entryHeap[K, V]) Len() int { return len(h.entries) }
+
+func (h *entryHeap[K, V]) Less(i, j int) bool {
+	a, b := h.entries[i], h.entries[j]
+	if h.policy == LFU && a.freq != b.freq {
+		return a.freq < b.freq
+	}
+	return a.tick < b.tick
+}
+
+func (h *entryHeap[K, V]) Swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	h.entries[i].index = i
+	h.entries[j].index = j
+}
+
+func (h *entryHeap[K, V]) Push(x any) {
+	e := x.(*entry[K, V])
+	e.index = len(h.entries)
+	h.entries = append(h.entries, e)
+}
+
+func (h *entryHeap[K, V]) Pop() any {
+	n := len(h.entries) - 1
+	e := h.entries[n]
+	h.entries[n] = nil
+	h.entries = h.entries[:n]
+	e.index = -1
+	return e
+}
+
+type removal[K comparable, V any] struct {
+	key    K
+	value  V
+	reason Reason
+}
+
+type loadCall[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+// Cache is a generic, in-memory key-value cache, safe for concurrent use.
+//
+// Once the total cost of all entries exceeds Capacity, entries are evicted
+// according to the Policy. Entries may also have a time-to-live, after which
+// they are no longer returned by the cache.
+//
+// `&Cache[K, V]{Capacity: ...}` is ready to use. A Cache must not be copied after first use,
+// and its configuration fields must not be modified after first use.
+//
+// Unless noted otherwise, operations have logarithmic complexity in terms of
+// the number of entries.
+type Cache[K comparable, V any] struct {
+	// Policy decides which entries are evicted first. Defaults to [LRU].
+	Policy Policy
+
+	// Capacity is the maximum total cost of all entries in the cache.
+	// Zero (the default) or a negative number means that the cache is unbounded.
+	Capacity int
+
+	// Cost returns the cost of a single entry. If nil, every entry costs 1,
+	// and Capacity is simply the maximum number of entries.
+	Cost func(key K, value V) int
+
+	// TTL is the default time-to-live of entries added by Set and GetOrLoad.
+	// Zero (the default) or a negative duration means that entries never expire.
+	TTL time.Duration
+
+	// Clock is used to provide time for expiring entries.
+	// If nil, [clock.System] will be used.
+	//
+	// Clock.Now() is only called when an entry with a time-to-live is added,
+	// when such an entry is looked up, and by RemoveExpired.
+	Clock clock.Interface
+
+	// OnRemove, if not nil, is called whenever an entry leaves the cache.
+	//
+	// OnRemove is called after the internal lock has been released,
+	// so it may safely call back into the cache.
+	OnRemove func(key K, value V, reason Reason)
+
+	mu      sync.Mutex
+	entries map[K]*entry[K, V]
+	order   entryHeap[K, V]
+	loads   map[K]*loadCall[V]
+	cost    int
+	tick    uint64
+	stats   Stats
+}
+
+func (c *Cache[K, V]) ensureInit() {
+	if c.entries == nil {
+		c.entries = make(map[K]*entry[K, V])
+		c.loads = make(map[K]*loadCall[V])
+		c.order.policy = c.Policy
+	}
+	if c.Clock == nil {
+		c.Clock = clock.System
+	}
+}
+
+func (c *Cache[K, V]) entryCost(key K, value V) int {
+	if c.Cost == nil {
+		return 1
+	}
+	return c.Cost(key, value)
+}
+
+func (c *Cache[K, V]) isExpired(e *entry[K, V]) bool {
+	return !e.expires.IsZero() && !c.Clock.Now().Before(e.expires)
+}
+
+func (c *Cache[K, V]) touch(e *entry[K, V]) {
+	c.tick++
+	e.tick = c.tick
+	e.freq++
+	heap.Fix(&c.order, e.index)
+}
+
+func (c *Cache[K, V]) removeEntry(e *entry[K, V], reason Reason, removed *[]removal[K, V]) {
+	heap.Remove(&c.order, e.index)
+	delete(c.entries, e.key)
+	c.cost -= e.cost
+
+	switch reason {
+	case Evicted:
+		c.stats.Evictions++
+	case Expired:
+		c.stats.Expirations++
+	}
+
+	if c.OnRemove != nil {
+		*removed = append(*removed, removal[K, V]{e.key, e.value, reason})
+	}
+}
+
+func (c *Cache[K, V]) notify(removed []removal[K, V]) {
+	for _, r := range removed {
+		c.OnRemove(r.key, r.value, r.reason)
+	}
+}
+
+// lookup returns a live entry with the provided key, updating the statistics.
+func (c *Cache[K, V]) lookup(key K, removed *[]removal[K, V]) (*entry[K, V], bool) {
+	e, ok := c.entries[key]
+	if ok && c.isExpired(e) {
+		c.removeEntry(e, Expired, removed)
+		ok = false
+	}
+
+	if ok {
+		c.stats.Hits++
+		c.touch(e)
+	} else {
+		c.stats.Misses++
+	}
+	return e, ok
+}
+
+func (c *Cache[K, V]) set(key K, value V, ttl time.Duration, removed *[]removal[K, V]) {
+	if old, ok := c.entries[key]; ok {
+		c.removeEntry(old, Replaced, removed)
+	}
+
+	e := &entry[K, V]{key: key, value: value, cost: c.entryCost(key, value)}
+
+	// Entries which would never fit are immediately evicted
+	if c.Capacity > 0 && e.cost > c.Capacity {
+		c.stats.Evictions++
+		if c.OnRemove != nil {
+			*removed = append(*removed, removal[K, V]{key, value, Evicted})
+		}
+		return
+	}
+
+	// Make room for the new entry
+	for c.Capacity > 0 && c.cost+e.cost > c.Capacity {
+		c.removeEntry(c.order.entries[0], Evicted, removed)
+	}
+
+	if ttl > 0 {
+		e.expires = c.Clock.Now().Add(ttl)
+	}
+
+	c.entries[key] = e
+	c.cost += e.cost
+	heap.Push(&c.order, e)
+	c.touch(e)
+}
+
+// Get returns the value associated with the provided key,
+// if it's present in the cache and has not expired.
+func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
+	var removed []removal[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ensureInit()
+
+	e, ok := c.lookup(key, &removed)
+	if ok {
+		value = e.value
+	}
+	return
+}
+
+// Has returns true if the provided key is present in the cache and has not expired.
+//
+// Unlike Get, Has does not count as an access of the entry and does not affect
+// the statistics.
+func (c *Cache[K, V]) Has(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ensureInit()
+
+	e, ok := c.entries[key]
+	return ok && !c.isExpired(e)
+}
+
+// Set associates the provided value with a key, using the default TTL.
+//
+// If the new entry doesn't fit in the cache, other entries are evicted according to the Policy.
+// An entry whose cost alone exceeds Capacity is not stored at all.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.TTL)
+}
+
+// SetWithTTL associates the provided value with a key, overriding the default TTL.
+// A zero or negative ttl means that the entry never expires.
+//
+// If the new entry doesn't fit in the cache, other entries are evicted according to the Policy.
+// An entry whose cost alone exceeds Capacity is not stored at all.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	var removed []removal[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ensureInit()
+
+	c.set(key, value, ttl, &removed)
+}
+
+// Remove ensures that the provided key is not present in the cache.
+// Returns true if an entry was actually removed.
+func (c *Cache[K, V]) Remove(key K) bool {
+	var removed []removal[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ensureInit()
+
+	e, ok := c.entries[key]
+	if ok {
+		c.removeEntry(e, Removed, &removed)
+	}
+	return ok
+}
+
+// RemoveExpired removes all entries which have outlived their time-to-live.
+//
+// Expired entries are always removed lazily when they are looked up,
+// this function is useful to free up memory in long-running programs.
+//
+// Complexity: linear in terms of the number of entries.
+func (c *Cache[K, V]) RemoveExpired() {
+	var removed []removal[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ensureInit()
+
+	now := c.Clock.Now()
+	for _, e := range c.entries {
+		if !e.expires.IsZero() && !now.Before(e.expires) {
+			c.removeEntry(e, Expired, &removed)
+		}
+	}
+}
+
+// Clear removes all entries from the cache. Statistics are not reset.
+//
+// Complexity: linear in terms of the number of entries.
+func (c *Cache[K, V]) Clear() {
+	var removed []removal[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ensureInit()
+
+	for c.order.Len() > 0 {
+		c.removeEntry(c.order.entries[c.order.Len()-1], Removed, &removed)
+	}
+}
+
+// Len returns the number of entries in the cache, including expired entries
+// which were not yet removed.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// TotalCost returns the sum of costs of all entries in the cache,
+// including expired entries which were not yet removed.
+func (c *Cache[K, V]) TotalCost() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cost
+}
+
+// Stats returns a snapshot of usage counters of the cache.
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// ResetStats sets all usage counters to zero.
+func (c *Cache[K, V]) ResetStats() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = Stats{}
+}
+
+// GetOrLoad returns the value associated with the provided key; and if there's
+// no such value, calls load(key) and stores its result in the cache with the default TTL.
+//
+// Concurrent calls to GetOrLoad with the same key are deduplicated - only one
+// call to load is made, and all callers receive its result.
+//
+// If load returns an error, nothing is stored in the cache, and the error
+// is returned to all callers waiting for that load.
+//
+// load is called without holding the internal lock, so it may safely call back into the cache.
+func (c *Cache[K, V]) GetOrLoad(key K, load func(K) (V, error)) (value V, err error) {
+	var removed []removal[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.mu.Lock()
+	c.ensureInit()
+
+	// Try to get the value from the cache
+	if e, ok := c.lookup(key, &removed); ok {
+		value = e.value
+		c.mu.Unlock()
+		return
+	}
+
+	// Wait for an in-flight load
+	if call, ok := c.loads[key]; ok {
+		c.mu.Unlock()
+		call.wg.Wait()
+		return call.value, call.err
+	}
+
+	// Perform the load ourselves
+	call := &loadCall[V]{}
+	call.wg.Add(1)
+	c.loads[key] = call
+	c.stats.Loads++
+	c.mu.Unlock()
+
+	// Ensure waiters are released, even if load panics
+	defer func() {
+		c.mu.Lock()
+		delete(c.loads, key)
+		if call.err != nil {
+			c.stats.LoadErrors++
+		}
+		c.mu.Unlock()
+		call.wg.Done()
+	}()
+
+	call.err = fmt.Errorf("cache: load of %v panicked", key)
+	call.value, call.err = load(key)
+	if call.err == nil {
+		c.mu.Lock()
+		c.set(key, call.value, c.TTL, &removed)
+		c.mu.Unlock()
+	}
+	return call.value, call.err
+}
+
+// Iter returns an iterator over a snapshot of all live entries in the cache,
+// in an arbitrary order.
+//
+// Iterating does not count as accessing the entries and does not affect the statistics.
+//
+// Complexity: linear in terms of the number of entries.
+func (c *Cache[K, V]) Iter() iter.Iterator[iter.Pair[K, V]] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ensureInit()
+
+	var now time.Time
+	snapshot := make([]iter.Pair[K, V], 0, len(c.entries))
+	for _, e := range c.entries {
+		if !e.expires.IsZero() {
+			if now.IsZero() {
+				now = c.Clock.Now()
+			}
+			if !now.Before(e.expires) {
+				continue
+			}
+		}
+		snapshot = append(snapshot, iter.Pair[K, V]{First: e.key, Second: e.value})
+	}
+	return iter.OverSlice(snapshot)
+}
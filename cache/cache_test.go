@@ -0,0 +1,252 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package cache_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MKuranowski/go-extra-lib/cache"
+	"github.com/MKuranowski/go-extra-lib/clock"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/assert"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+	"golang.org/x/exp/slices"
+)
+
+func TestCacheGetSet(t *testing.T) {
+	c := &cache.Cache[string, int]{}
+
+	_, ok := c.Get("a")
+	check.FalseMsg(t, ok, "Get(a): empty cache")
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	v, ok := c.Get("a")
+	check.TrueMsg(t, ok, "Get(a): after set")
+	check.EqMsg(t, v, 1, "Get(a): after set")
+	check.EqMsg(t, c.Len(), 2, "Len(): after set")
+
+	c.Set("a", 3)
+	v, _ = c.Get("a")
+	check.EqMsg(t, v, 3, "Get(a): after overwrite")
+	check.EqMsg(t, c.Len(), 2, "Len(): after overwrite")
+
+	check.TrueMsg(t, c.Remove("a"), "Remove(a)")
+	check.FalseMsg(t, c.Remove("a"), "Remove(a): second time")
+	check.FalseMsg(t, c.Has("a"), "Has(a): after remove")
+	check.EqMsg(t, c.Len(), 1, "Len(): after remove")
+
+	check.EqMsg(t, c.Stats(), cache.Stats{Hits: 2, Misses: 1}, "Stats()")
+}
+
+func TestCacheLRU(t *testing.T) {
+	c := &cache.Cache[int, int]{Policy: cache.LRU, Capacity: 3}
+
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Set(3, 3)
+	c.Get(1)
+	c.Set(4, 4) // evicts 2
+
+	check.TrueMsg(t, c.Has(1), "Has(1)")
+	check.FalseMsg(t, c.Has(2), "Has(2)")
+	check.TrueMsg(t, c.Has(3), "Has(3)")
+	check.TrueMsg(t, c.Has(4), "Has(4)")
+	check.EqMsg(t, c.Stats().Evictions, 1, "Stats().Evictions")
+}
+
+func TestCacheLFU(t *testing.T) {
+	c := &cache.Cache[int, int]{Policy: cache.LFU, Capacity: 3}
+
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Set(3, 3)
+	c.Get(1)
+	c.Get(1)
+	c.Get(2)
+	c.Get(3)
+	c.Get(3)
+	c.Set(4, 4) // evicts 2 - the least frequently used
+
+	check.TrueMsg(t, c.Has(1), "Has(1)")
+	check.FalseMsg(t, c.Has(2), "Has(2)")
+	check.TrueMsg(t, c.Has(3), "Has(3)")
+	check.TrueMsg(t, c.Has(4), "Has(4)")
+}
+
+func TestCacheCost(t *testing.T) {
+	c := &cache.Cache[string, string]{
+		Capacity: 10,
+		Cost:     func(_ string, v string) int { return len(v) },
+	}
+
+	c.Set("a", "hello")
+	c.Set("b", "foo")
+	check.EqMsg(t, c.TotalCost(), 8, "TotalCost(): before eviction")
+
+	c.Set("c", "spam")
+	check.EqMsg(t, c.TotalCost(), 7, "TotalCost(): after eviction")
+	check.FalseMsg(t, c.Has("a"), "Has(a)")
+	check.TrueMsg(t, c.Has("b"), "Has(b)")
+	check.TrueMsg(t, c.Has("c"), "Has(c)")
+}
+
+func TestCacheTTL(t *testing.T) {
+	start := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := &cache.Cache[string, int]{
+		TTL: 2 * time.Minute,
+		Clock: &clock.Specific{Times: []time.Time{
+			start,                      // Set(a)
+			start.Add(1 * time.Minute), // Get(a)
+			start.Add(3 * time.Minute), // Get(a)
+		}},
+	}
+
+	var removedKey string
+	var removedReason cache.Reason
+	c.OnRemove = func(k string, _ int, r cache.Reason) { removedKey, removedReason = k, r }
+
+	c.Set("a", 1)
+	c.SetWithTTL("b", 2, 0) // never expires, does not use the clock
+
+	_, ok := c.Get("a")
+	check.TrueMsg(t, ok, "Get(a): before expiry")
+
+	_, ok = c.Get("a")
+	check.FalseMsg(t, ok, "Get(a): after expiry")
+	check.EqMsg(t, removedKey, "a", "OnRemove key")
+	check.EqMsg(t, removedReason, cache.Expired, "OnRemove reason")
+
+	_, ok = c.Get("b")
+	check.TrueMsg(t, ok, "Get(b)")
+
+	check.EqMsg(t, c.Stats().Expirations, 1, "Stats().Expirations")
+}
+
+func TestCacheRemoveExpired(t *testing.T) {
+	c := &cache.Cache[int, int]{Clock: &clock.EvenlySpaced{Delta: time.Minute}}
+
+	c.SetWithTTL(1, 1, 90*time.Second) // t=0, expires at t=1.5
+	c.SetWithTTL(2, 2, 10*time.Minute) // t=1, expires at t=11
+	c.Set(3, 3)
+	c.RemoveExpired() // t=2
+
+	check.EqMsg(t, c.Len(), 2, "Len()")
+	check.FalseMsg(t, c.Has(1), "Has(1)")
+	check.TrueMsg(t, c.Has(2), "Has(2)") // t=3
+	check.TrueMsg(t, c.Has(3), "Has(3)")
+}
+
+func TestCacheOnRemove(t *testing.T) {
+	var reasons []cache.Reason
+	c := &cache.Cache[int, int]{
+		Capacity: 2,
+		OnRemove: func(_, _ int, r cache.Reason) { reasons = append(reasons, r) },
+	}
+
+	c.Set(1, 1)
+	c.Set(1, 2)
+	c.Set(2, 2)
+	c.Set(3, 3)
+	c.Remove(2)
+	c.Clear()
+
+	check.DeepEqMsg(
+		t,
+		reasons,
+		[]cache.Reason{cache.Replaced, cache.Evicted, cache.Removed, cache.Removed},
+		"reasons",
+	)
+	check.EqMsg(t, c.Len(), 0, "Len(): after Clear")
+}
+
+func TestCacheGetOrLoad(t *testing.T) {
+	c := &cache.Cache[int, int]{}
+	loads := 0
+	load := func(k int) (int, error) {
+		loads++
+		return k * 2, nil
+	}
+
+	v, err := c.GetOrLoad(21, load)
+	assert.NoErr(t, err)
+	check.EqMsg(t, v, 42, "GetOrLoad(21): first call")
+
+	v, err = c.GetOrLoad(21, load)
+	assert.NoErr(t, err)
+	check.EqMsg(t, v, 42, "GetOrLoad(21): second call")
+
+	check.EqMsg(t, loads, 1, "number of loads")
+	check.EqMsg(t, c.Stats(), cache.Stats{Hits: 1, Misses: 1, Loads: 1}, "Stats()")
+}
+
+func TestCacheGetOrLoadError(t *testing.T) {
+	c := &cache.Cache[int, int]{}
+	errLoad := errors.New("load failed")
+
+	_, err := c.GetOrLoad(1, func(int) (int, error) { return 0, errLoad })
+	check.SpecificErr(t, err, errLoad)
+	check.FalseMsg(t, c.Has(1), "Has(1)")
+	check.EqMsg(t, c.Stats().LoadErrors, 1, "Stats().LoadErrors")
+}
+
+func TestCacheGetOrLoadDeduplicates(t *testing.T) {
+	c := &cache.Cache[string, int]{}
+	release := make(chan struct{})
+	var loads int32
+
+	load := func(string) (int, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return 42, nil
+	}
+
+	const workers = 8
+	results := make([]int, workers)
+	wg := sync.WaitGroup{}
+	started := sync.WaitGroup{}
+	wg.Add(workers)
+	started.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func(i int) {
+			defer wg.Done()
+			started.Done()
+			results[i], _ = c.GetOrLoad("key", load)
+		}(i)
+	}
+
+	started.Wait()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	check.EqMsg(t, atomic.LoadInt32(&loads), 1, "number of loads")
+	for i, v := range results {
+		check.EqMsg(t, v, 42, fmt.Sprintf("result of worker %d", i))
+	}
+}
+
+func TestCacheIter(t *testing.T) {
+	c := &cache.Cache[int, string]{}
+	c.Set(1, "a")
+	c.Set(2, "b")
+	c.Set(3, "c")
+
+	got := iter.IntoSlice(c.Iter())
+	slices.SortFunc(got, func(a, b iter.Pair[int, string]) bool { return a.First < b.First })
+
+	check.DeepEqMsg(
+		t,
+		got,
+		[]iter.Pair[int, string]{{First: 1, Second: "a"}, {First: 2, Second: "b"}, {First: 3, Second: "c"}},
+		"Iter()",
+	)
+	check.EqMsg(t, c.Stats(), cache.Stats{}, "Stats()")
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// bimap contains an implementation of a bidirectional map,
+// a one-to-one mapping which can be queried both by keys and by values.
+package bimap
+
+import (
+	"errors"
+
+	"github.com/MKuranowski/go-extra-lib/iter"
+)
+
+// CollisionPolicy decides what happens when [BiMap.Put] is called with a key or a value
+// which is already mapped to something else.
+type CollisionPolicy uint8
+
+const (
+	// Error causes Put to leave the BiMap unchanged and to return
+	// ErrKeyExists or ErrValueExists.
+	Error CollisionPolicy = iota
+
+	// Overwrite causes Put to remove any existing mappings of the key and of the value,
+	// before inserting the new mapping.
+	Overwrite
+)
+
+var (
+	// ErrKeyExists is returned by Put if the key is already mapped to a different value.
+	ErrKeyExists = errors.New("bimap: key is already mapped to a different value")
+
+	// ErrValueExists is returned by Put if the value is already mapped to a different key.
+	ErrValueExists = errors.New("bimap: value is already mapped to a different key")
+)
+
+// BiMap is a one-to-one mapping between keys and values,
+// which supports fast lookups in both directions.
+//
+// The representation uses two maps, map[K]V and map[V]K, which are kept in sync.
+//
+// The zero value (`&BiMap[K, V]{}`) is an empty BiMap, which uses the [Error] collision policy.
+//
+// Given operation complexity assumes that element access, insertion and removal
+// of a map is on average constant.
+type BiMap[K, V comparable] struct {
+	// OnCollision decides what happens when Put is called with a key or a value
+	// which is already mapped to something else. Defaults to [Error].
+	OnCollision CollisionPolicy
+
+	forward  map[K]V
+	backward map[V]K
+}
+
+// New returns an empty BiMap with the provided collision policy.
+func New[K, V comparable](onCollision CollisionPolicy) *BiMap[K, V] {
+	return &BiMap[K, V]{
+		OnCollision: onCollision,
+		forward:     make(map[K]V),
+		backward:    make(map[V]K),
+	}
+}
+
+// FromMap returns a BiMap with all mappings from m, and with the Error collision policy.
+//
+// Returns ErrValueExists if multiple keys of m are mapped to the same value.
+//
+// Average complexity: linear
+func FromMap[K, V comparable](m map[K]V) (*BiMap[K, V], error) {
+	b := &BiMap[K, V]{forward: make(map[K]V, len(m)), backward: make(map[V]K, len(m))}
+	for k, v := range m {
+		if err := b.Put(k, v); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (b *BiMap[K, V]) ensureInit() {
+	if b.forward == nil {
+		b.forward = make(map[K]V)
+		b.backward = make(map[V]K)
+	}
+}
+
+// Put ensures that k is mapped to v (and v to k).
+//
+// If k is already mapped to a different value, or v is already mapped to a different key,
+// the behavior depends on OnCollision. With [Error] an error is returned and the BiMap
+// is not modified. With [Overwrite], the old mappings are removed.
+//
+// Average complexity: constant
+func (b *BiMap[K, V]) Put(k K, v V) error {
+	b.ensureInit()
+
+	oldV, hasK := b.forward[k]
+	oldK, hasV := b.backward[v]
+
+	// Mapping already exists - nothing to do
+	if hasK && hasV && oldV == v && oldK == k {
+		return nil
+	}
+
+	if b.OnCollision == Error {
+		if hasK {
+			return ErrKeyExists
+		} else if hasV {
+			return ErrValueExists
+		}
+	}
+
+	if hasK {
+		delete(b.backward, oldV)
+	}
+	if hasV {
+		delete(b.forward, oldK)
+	}
+
+	b.forward[k] = v
+	b.backward[v] = k
+	return nil
+}
+
+// GetByKey returns the value mapped to the provided key.
+//
+// Average complexity: constant
+func (b *BiMap[K, V]) GetByKey(k K) (v V, ok bool) {
+	v, ok = b.forward[k]
+	return
+}
+
+// GetByValue returns the key mapped to the provided value.
+//
+// Average complexity: constant
+func (b *BiMap[K, V]) GetByValue(v V) (k K, ok bool) {
+	k, ok = b.backward[v]
+	return
+}
+
+// HasKey returns true if the provided key is mapped to any value.
+//
+// Average complexity: constant
+func (b *BiMap[K, V]) HasKey(k K) bool {
+	_, has := b.forward[k]
+	return has
+}
+
+// HasValue returns true if the provided value is mapped to any key.
+//
+// Average complexity: constant
+func (b *BiMap[K, V]) HasValue(v V) bool {
+	_, has := b.backward[v]
+	return has
+}
+
+// RemoveByKey ensures that the provided key is not mapped to anything.
+// Returns the value which was mapped to the key.
+//
+// Average complexity: constant
+func (b *BiMap[K, V]) RemoveByKey(k K) (v V, ok bool) {
+	v, ok = b.forward[k]
+	if ok {
+		delete(b.forward, k)
+		delete(b.backward, v)
+	}
+	return
+}
+
+// RemoveByValue ensures that the provided value is not mapped to anything.
+// Returns the key which was mapped to the value.
+//
+// Average complexity: constant
+func (b *BiMap[K, V]) RemoveByValue(v V) (k K, ok bool) {
+	k, ok = b.backward[v]
+	if ok {
+		delete(b.forward, k)
+		delete(b.backward, v)
+	}
+	return
+}
+
+// Len returns the number of mappings in the BiMap.
+func (b *BiMap[K, V]) Len() int { return len(b.forward) }
+
+// Clear removes all mappings from the BiMap.
+//
+// Average complexity: linear
+func (b *BiMap[K, V]) Clear() {
+	for k, v := range b.forward {
+		delete(b.forward, k)
+		delete(b.backward, v)
+	}
+}
+
+// Clone returns a copy of the BiMap, with the same collision policy.
+//
+// Average complexity: linear
+func (b *BiMap[K, V]) Clone() *BiMap[K, V] {
+	n := &BiMap[K, V]{
+		OnCollision: b.OnCollision,
+		forward:     make(map[K]V, len(b.forward)),
+		backward:    make(map[V]K, len(b.backward)),
+	}
+	for k, v := range b.forward {
+		n.forward[k] = v
+		n.backward[v] = k
+	}
+	return n
+}
+
+// Inverse returns a view of the BiMap with keys and values swapped.
+//
+// The view shares the underlying data - any modification made through the view
+// is visible in the original BiMap, and vice versa.
+// The view starts with the same collision policy, but it can be changed independently.
+//
+// Complexity: constant
+func (b *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	b.ensureInit()
+	return &BiMap[V, K]{
+		OnCollision: b.OnCollision,
+		forward:     b.backward,
+		backward:    b.forward,
+	}
+}
+
+// Iter returns an [iter.Iterator] over key-value pairs of the BiMap.
+//
+// Pairs are generated in an arbitrary order.
+func (b *BiMap[K, V]) Iter() iter.Iterator[iter.Pair[K, V]] { return iter.OverMap(b.forward) }
+
+// IterKeys returns an [iter.Iterator] over keys of the BiMap.
+//
+// Keys are generated in an arbitrary order.
+func (b *BiMap[K, V]) IterKeys() iter.Iterator[K] { return iter.OverMapKeys(b.forward) }
+
+// IterValues returns an [iter.Iterator] over values of the BiMap.
+//
+// Values are generated in an arbitrary order.
+func (b *BiMap[K, V]) IterValues() iter.Iterator[V] { return iter.OverMapKeys(b.backward) }
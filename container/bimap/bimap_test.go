@@ -0,0 +1,143 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package bimap_test
+
+import (
+	"testing"
+
+	. "github.com/MKuranowski/go-extra-lib/container/bimap"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/assert"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+	"golang.org/x/exp/slices"
+)
+
+func TestBiMapPutGet(t *testing.T) {
+	b := &BiMap[string, int]{}
+
+	check.EqMsg(t, b.Len(), 0, "b.Len(): empty bimap")
+
+	assert.NoErr(t, b.Put("one", 1))
+	assert.NoErr(t, b.Put("two", 2))
+	assert.NoErr(t, b.Put("two", 2)) // same mapping is not a collision
+
+	check.EqMsg(t, b.Len(), 2, "b.Len(): after put")
+
+	v, ok := b.GetByKey("one")
+	check.TrueMsg(t, ok, "b.GetByKey(one): ok")
+	check.EqMsg(t, v, 1, "b.GetByKey(one)")
+
+	k, ok := b.GetByValue(2)
+	check.TrueMsg(t, ok, "b.GetByValue(2): ok")
+	check.EqMsg(t, k, "two", "b.GetByValue(2)")
+
+	_, ok = b.GetByKey("three")
+	check.FalseMsg(t, ok, "b.GetByKey(three): ok")
+	check.FalseMsg(t, b.HasValue(3), "b.HasValue(3)")
+}
+
+func TestBiMapPutError(t *testing.T) {
+	b := New[string, int](Error)
+	assert.NoErr(t, b.Put("one", 1))
+
+	check.SpecificErr(t, b.Put("one", 2), ErrKeyExists)
+	check.SpecificErr(t, b.Put("uno", 1), ErrValueExists)
+
+	check.EqMsg(t, b.Len(), 1, "b.Len()")
+	v, _ := b.GetByKey("one")
+	check.EqMsg(t, v, 1, "b.GetByKey(one)")
+}
+
+func TestBiMapPutOverwrite(t *testing.T) {
+	b := New[string, int](Overwrite)
+	assert.NoErr(t, b.Put("one", 1))
+	assert.NoErr(t, b.Put("two", 2))
+
+	assert.NoErr(t, b.Put("one", 2))
+
+	check.EqMsg(t, b.Len(), 1, "b.Len()")
+	v, _ := b.GetByKey("one")
+	check.EqMsg(t, v, 2, "b.GetByKey(one)")
+	check.FalseMsg(t, b.HasKey("two"), "b.HasKey(two)")
+	check.FalseMsg(t, b.HasValue(1), "b.HasValue(1)")
+}
+
+func TestBiMapRemove(t *testing.T) {
+	b, err := FromMap(map[string]int{"one": 1, "two": 2, "three": 3})
+	assert.NoErr(t, err)
+
+	v, ok := b.RemoveByKey("one")
+	check.TrueMsg(t, ok, "b.RemoveByKey(one): ok")
+	check.EqMsg(t, v, 1, "b.RemoveByKey(one)")
+	check.FalseMsg(t, b.HasValue(1), "b.HasValue(1)")
+
+	k, ok := b.RemoveByValue(2)
+	check.TrueMsg(t, ok, "b.RemoveByValue(2): ok")
+	check.EqMsg(t, k, "two", "b.RemoveByValue(2)")
+	check.FalseMsg(t, b.HasKey("two"), "b.HasKey(two)")
+
+	_, ok = b.RemoveByKey("one")
+	check.FalseMsg(t, ok, "b.RemoveByKey(one): second time")
+
+	check.EqMsg(t, b.Len(), 1, "b.Len()")
+
+	b.Clear()
+	check.EqMsg(t, b.Len(), 0, "b.Len(): after clear")
+	check.FalseMsg(t, b.HasValue(3), "b.HasValue(3): after clear")
+}
+
+func TestBiMapFromMapCollision(t *testing.T) {
+	_, err := FromMap(map[string]int{"one": 1, "uno": 1})
+	check.SpecificErr(t, err, ErrValueExists)
+}
+
+func TestBiMapInverse(t *testing.T) {
+	b := &BiMap[string, int]{}
+	inv := b.Inverse()
+
+	assert.NoErr(t, b.Put("one", 1))
+	assert.NoErr(t, inv.Put(2, "two"))
+
+	k, _ := inv.GetByKey(1)
+	check.EqMsg(t, k, "one", "inv.GetByKey(1)")
+
+	v, _ := b.GetByKey("two")
+	check.EqMsg(t, v, 2, "b.GetByKey(two)")
+
+	check.EqMsg(t, b.Len(), 2, "b.Len()")
+	check.EqMsg(t, inv.Len(), 2, "inv.Len()")
+}
+
+func TestBiMapClone(t *testing.T) {
+	b1 := &BiMap[string, int]{}
+	assert.NoErr(t, b1.Put("one", 1))
+
+	b2 := b1.Clone()
+	assert.NoErr(t, b2.Put("two", 2))
+
+	check.EqMsg(t, b1.Len(), 1, "b1.Len()")
+	check.EqMsg(t, b2.Len(), 2, "b2.Len()")
+}
+
+func TestBiMapIter(t *testing.T) {
+	b, err := FromMap(map[string]int{"one": 1, "two": 2, "three": 3})
+	assert.NoErr(t, err)
+
+	pairs := iter.IntoSlice(b.Iter())
+	slices.SortFunc(pairs, func(a, b iter.Pair[string, int]) bool { return a.Second < b.Second })
+	check.DeepEqMsg(
+		t,
+		pairs,
+		[]iter.Pair[string, int]{{First: "one", Second: 1}, {First: "two", Second: 2}, {First: "three", Second: 3}},
+		"b.Iter()",
+	)
+
+	values := iter.IntoSlice(b.IterValues())
+	slices.Sort(values)
+	check.DeepEqMsg(t, values, []int{1, 2, 3}, "b.IterValues()")
+
+	keys := iter.IntoSlice(b.IterKeys())
+	slices.Sort(keys)
+	check.DeepEqMsg(t, keys, []string{"one", "three", "two"}, "b.IterKeys()")
+}
@@ -2,6 +2,27 @@
 // SPDX-License-Identifier: MIT
 
 // bitset contains an efficient implementation of a set of unsigned numbers.
+//
+// # Encoding
+//
+// All sets in this package support the following encodings:
+//
+//   - binary ([encoding.BinaryMarshaler]) - a single tag byte followed by either
+//     the raw bits of the set (tag 0; bit i is stored in byte i/8 at position i%8),
+//     or a list of runs (tag 1; every run is encoded as a pair of uvarints - the gap from
+//     the end of the previous run and the length of the run). The shorter form is chosen
+//     automatically. The binary form is also used by [encoding/gob].
+//   - text ([encoding.TextMarshaler]) - comma-separated, inclusive ranges of elements,
+//     e.g. "1-3,5,8-9".
+//   - JSON ([json.Marshaler]) - an array of elements in ascending order.
+//
+// Only elements less than 1<<26 can be encoded - a larger element makes the marshal methods
+// return [ErrTooLarge], and the unmarshal methods return [ErrInvalidData].
+// This prevents small inputs from causing huge allocations when decoding.
+//
+// Encoded data is interchangeable between [BitSet], [Dense] and [Small].
+// Internally, all sets are converted into little-endian bitmaps (a []byte in the same
+// layout as the raw binary encoding, without trailing zero bytes).
 package bitset
 
 import (
@@ -129,8 +150,14 @@ func (i *bitsetIterator) Next() bool {
 func (i bitsetIterator) Get() int { return i.n }
 func (bitsetIterator) Err() error { return nil }
 
+// Iter returns an iterator over the elements in the set.
+//
+// Any changes made during iteration are not reflected in the iterator;
+// iteration is actually performed on a copy of the set.
 func (s *BitSet) Iter() iter.Iterator[int] {
-	return &bitsetIterator{s: *s}
+	i := &bitsetIterator{}
+	i.s.n.Set(&s.n)
+	return i
 }
 
 // Small is a set of integers between 0 and 63 (inclusive),
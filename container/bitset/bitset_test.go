@@ -262,6 +262,10 @@ func TestBitSetIter(t *testing.T) {
 	)
 
 	check.DeepEqMsg(t, iter.IntoSlice((&BitSet{}).Iter()), []int{}, "Of().Iter()")
+
+	s := Of(1, 3, 11, 128, 1024)
+	iter.IntoSlice(s.Iter())
+	check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), []int{1, 3, 11, 128, 1024}, "s.Iter(): second iteration")
 }
 
 // Small
@@ -0,0 +1,385 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package bitset
+
+import (
+	"math/bits"
+
+	"github.com/MKuranowski/go-extra-lib/iter"
+)
+
+// Dense is a set of non-negative integers, backed by a slice of uint64 words.
+//
+// The zero value (`&Dense{}`) is a Dense containing no elements.
+//
+// Compared to [BitSet], Dense has cheaper element access and counting,
+// and supports efficient searching for set and clear bits ([Dense.NextSet], [Dense.NextClear],
+// [Dense.PrevSet]), as well as [Dense.Rank] and [Dense.Select] queries.
+// Like with BitSet, the memory usage is proportional to the largest element,
+// so a map-based set may be a better fit for sparse sets.
+//
+// Functions which add elements will panic if the provided number is negative.
+// Query functions treat negative numbers as not present in the set.
+type Dense struct {
+	words []uint64
+}
+
+// DenseOf returns a Dense containing all the provided elements.
+func DenseOf(is ...int) *Dense {
+	s := &Dense{}
+	for _, i := range is {
+		s.Add(i)
+	}
+	return s
+}
+
+// NewDense returns an empty Dense with memory preallocated
+// for elements in range [0, capacity).
+func NewDense(capacity int) *Dense {
+	return &Dense{words: make([]uint64, 0, (capacity+63)/64)}
+}
+
+// grow ensures that the set has enough words to store the i-th bit.
+func (s *Dense) grow(i int) {
+	if needed := i/64 + 1; needed > len(s.words) {
+		if needed <= cap(s.words) {
+			s.words = s.words[:needed]
+		} else {
+			s.words = append(s.words, make([]uint64, needed-len(s.words))...)
+		}
+	}
+}
+
+// Has returns true if the provided number is in the set.
+func (s *Dense) Has(i int) bool {
+	if i < 0 || i/64 >= len(s.words) {
+		return false
+	}
+	return s.words[i/64]&(1<<(i%64)) != 0
+}
+
+// Add ensures that the provided number is in the set.
+func (s *Dense) Add(i int) {
+	if i < 0 {
+		panic("bitset: negative element")
+	}
+	s.grow(i)
+	s.words[i/64] |= 1 << (i % 64)
+}
+
+// Remove ensures that the provided number is not in the set.
+func (s *Dense) Remove(i int) {
+	if i >= 0 && i/64 < len(s.words) {
+		s.words[i/64] &^= 1 << (i % 64)
+	}
+}
+
+// Count returns the number of elements in the set.
+func (s *Dense) Count() int {
+	n := 0
+	for _, w := range s.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Len returns the number of elements in the set. This is an alias for [Dense.Count],
+// provided for consistency with other sets.
+func (s *Dense) Len() int { return s.Count() }
+
+// Clear ensures that no numbers are present in the set.
+// Allocated memory is kept for reuse.
+func (s *Dense) Clear() {
+	for i := range s.words {
+		s.words[i] = 0
+	}
+	s.words = s.words[:0]
+}
+
+// Clone returns a new set with the same elements.
+func (s *Dense) Clone() *Dense {
+	return &Dense{words: append([]uint64(nil), s.words...)}
+}
+
+// word returns the i-th word of the set, or zero if it's not allocated.
+func (s *Dense) word(i int) uint64 {
+	if i < len(s.words) {
+		return s.words[i]
+	}
+	return 0
+}
+
+// Equal returns true if s1 contains the same elements as s2.
+func (s1 *Dense) Equal(s2 *Dense) bool {
+	n := len(s1.words)
+	if len(s2.words) > n {
+		n = len(s2.words)
+	}
+	for i := 0; i < n; i++ {
+		if s1.word(i) != s2.word(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// Union ensures s1 contains all elements from s2.
+func (s1 *Dense) Union(s2 *Dense) {
+	if len(s2.words) > len(s1.words) {
+		s1.grow(64*len(s2.words) - 1)
+	}
+	for i, w := range s2.words {
+		s1.words[i] |= w
+	}
+}
+
+// Intersection ensures s1 only contains elements that are present in both s1 and s2.
+func (s1 *Dense) Intersection(s2 *Dense) {
+	for i := range s1.words {
+		s1.words[i] &= s2.word(i)
+	}
+}
+
+// Difference ensures s1 does not contain any elements from s2.
+func (s1 *Dense) Difference(s2 *Dense) {
+	for i := range s1.words {
+		s1.words[i] &^= s2.word(i)
+	}
+}
+
+// SymmetricDifference ensures s1 contains elements present in exactly one of s1 and s2.
+func (s1 *Dense) SymmetricDifference(s2 *Dense) {
+	if len(s2.words) > len(s1.words) {
+		s1.grow(64*len(s2.words) - 1)
+	}
+	for i, w := range s2.words {
+		s1.words[i] ^= w
+	}
+}
+
+// IsDisjoint returns true if s1 and s2 have no elements in common.
+func (s1 *Dense) IsDisjoint(s2 *Dense) bool {
+	for i, w := range s1.words {
+		if w&s2.word(i) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubset returns true if every element of s1 is also present in s2.
+func (s1 *Dense) IsSubset(s2 *Dense) bool {
+	for i, w := range s1.words {
+		if w&^s2.word(i) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if every element of s2 is also present in s1.
+func (s1 *Dense) IsSuperset(s2 *Dense) bool { return s2.IsSubset(s1) }
+
+// Complement flips all bits in range [lo, hi): numbers from that range
+// which were in the set are removed, and numbers which were not in the set are added.
+//
+// Panics if lo is negative.
+func (s *Dense) Complement(lo, hi int) {
+	if lo < 0 {
+		panic("bitset: negative element")
+	}
+	if lo >= hi {
+		return
+	}
+
+	s.grow(hi - 1)
+	loWord, hiWord := lo/64, (hi-1)/64
+	loMask := ^uint64(0) << (lo % 64)
+	hiMask := ^uint64(0) >> (63 - (hi-1)%64)
+
+	if loWord == hiWord {
+		s.words[loWord] ^= loMask & hiMask
+		return
+	}
+
+	s.words[loWord] ^= loMask
+	for i := loWord + 1; i < hiWord; i++ {
+		s.words[i] = ^s.words[i]
+	}
+	s.words[hiWord] ^= hiMask
+}
+
+// NextSet returns the smallest element of the set which is not smaller than i.
+// Returns false if there is no such element.
+func (s *Dense) NextSet(i int) (int, bool) {
+	if i < 0 {
+		i = 0
+	}
+	wordIdx := i / 64
+	if wordIdx >= len(s.words) {
+		return 0, false
+	}
+
+	// Check the first word, ignoring bits before i
+	if w := s.words[wordIdx] >> (i % 64); w != 0 {
+		return i + bits.TrailingZeros64(w), true
+	}
+
+	for wordIdx++; wordIdx < len(s.words); wordIdx++ {
+		if w := s.words[wordIdx]; w != 0 {
+			return 64*wordIdx + bits.TrailingZeros64(w), true
+		}
+	}
+	return 0, false
+}
+
+// NextClear returns the smallest non-negative number not in the set, which is not smaller than i.
+func (s *Dense) NextClear(i int) int {
+	if i < 0 {
+		i = 0
+	}
+	wordIdx := i / 64
+	if wordIdx >= len(s.words) {
+		return i
+	}
+
+	// Check the first word, ignoring bits before i
+	if w := ^s.words[wordIdx] >> (i % 64); w != 0 {
+		return i + bits.TrailingZeros64(w)
+	}
+
+	for wordIdx++; wordIdx < len(s.words); wordIdx++ {
+		if w := ^s.words[wordIdx]; w != 0 {
+			return 64*wordIdx + bits.TrailingZeros64(w)
+		}
+	}
+	return 64 * len(s.words)
+}
+
+// PrevSet returns the largest element of the set which is not greater than i.
+// Returns false if there is no such element.
+func (s *Dense) PrevSet(i int) (int, bool) {
+	if i < 0 {
+		return 0, false
+	}
+
+	wordIdx := i / 64
+	if wordIdx >= len(s.words) {
+		wordIdx, i = len(s.words)-1, 64*len(s.words)-1
+	}
+	if wordIdx < 0 {
+		return 0, false
+	}
+
+	// Check the first word, ignoring bits after i
+	if w := s.words[wordIdx] << (63 - i%64); w != 0 {
+		return i - bits.LeadingZeros64(w), true
+	}
+
+	for wordIdx--; wordIdx >= 0; wordIdx-- {
+		if w := s.words[wordIdx]; w != 0 {
+			return 64*wordIdx + 63 - bits.LeadingZeros64(w), true
+		}
+	}
+	return 0, false
+}
+
+// Rank returns the number of elements in the set which are smaller than i.
+func (s *Dense) Rank(i int) int {
+	if i <= 0 {
+		return 0
+	}
+
+	wordIdx := i / 64
+	if wordIdx >= len(s.words) {
+		return s.Count()
+	}
+
+	n := 0
+	for _, w := range s.words[:wordIdx] {
+		n += bits.OnesCount64(w)
+	}
+	return n + bits.OnesCount64(s.words[wordIdx]&(1<<(i%64)-1))
+}
+
+// Select returns the k-th smallest element of the set (counting from zero),
+// that is the element x for which Rank(x) == k. Returns false if the set has
+// k or fewer elements.
+func (s *Dense) Select(k int) (int, bool) {
+	if k < 0 {
+		return 0, false
+	}
+
+	for wordIdx, w := range s.words {
+		count := bits.OnesCount64(w)
+		if k >= count {
+			k -= count
+			continue
+		}
+
+		// The element is in this word - drop k lowest bits
+		for ; k > 0; k-- {
+			w &= w - 1
+		}
+		return 64*wordIdx + bits.TrailingZeros64(w), true
+	}
+	return 0, false
+}
+
+type denseIterator struct {
+	s       *Dense
+	n       int
+	started bool
+}
+
+func (i *denseIterator) Next() bool {
+	start := i.n
+	if i.started {
+		start++
+	} else {
+		i.started = true
+	}
+
+	var ok bool
+	i.n, ok = i.s.NextSet(start)
+	return ok
+}
+
+func (i *denseIterator) Get() int { return i.n }
+func (*denseIterator) Err() error { return nil }
+
+// Iter returns an iterator over the elements in the set, in ascending order.
+//
+// The set must not be modified during iteration.
+func (s *Dense) Iter() iter.Iterator[int] { return &denseIterator{s: s} }
+
+// UnionDense returns a new set with elements present in any of the provided sets.
+func UnionDense(sets ...*Dense) *Dense {
+	words := 0
+	for _, s := range sets {
+		if len(s.words) > words {
+			words = len(s.words)
+		}
+	}
+
+	result := &Dense{words: make([]uint64, words)}
+	for _, s := range sets {
+		result.Union(s)
+	}
+	return result
+}
+
+// IntersectionDense returns a new set with elements present in all of the provided sets.
+// Returns an empty set if no sets are provided.
+func IntersectionDense(sets ...*Dense) *Dense {
+	if len(sets) == 0 {
+		return &Dense{}
+	}
+
+	result := sets[0].Clone()
+	for _, s := range sets[1:] {
+		result.Intersection(s)
+	}
+	return result
+}
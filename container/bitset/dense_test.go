@@ -0,0 +1,315 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package bitset_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	. "github.com/MKuranowski/go-extra-lib/container/bitset"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func TestDenseAddHasCountRemove(t *testing.T) {
+	s := &Dense{}
+
+	check.EqMsg(t, s.Count(), 0, "s.Count(): empty set")
+	check.FalseMsg(t, s.Has(0), "s.Has(0): empty set")
+	check.FalseMsg(t, s.Has(-1), "s.Has(-1): empty set")
+
+	s.Add(2)
+	s.Add(3)
+	s.Add(64)
+	s.Add(200)
+
+	check.EqMsg(t, s.Count(), 4, "s.Count(): after adding")
+	check.EqMsg(t, s.Len(), 4, "s.Len(): after adding")
+	check.TrueMsg(t, s.Has(2), "s.Has(2): after adding")
+	check.TrueMsg(t, s.Has(64), "s.Has(64): after adding")
+	check.TrueMsg(t, s.Has(200), "s.Has(200): after adding")
+	check.FalseMsg(t, s.Has(63), "s.Has(63): after adding")
+	check.FalseMsg(t, s.Has(1000), "s.Has(1000): after adding")
+
+	s.Remove(64)
+	s.Remove(1000)
+	check.EqMsg(t, s.Count(), 3, "s.Count(): after removing")
+	check.FalseMsg(t, s.Has(64), "s.Has(64): after removing")
+
+	s.Clear()
+	check.EqMsg(t, s.Count(), 0, "s.Count(): after clearing")
+	check.TrueMsg(t, s.Equal(&Dense{}), "s.Equal(&Dense{}): after clearing")
+}
+
+func TestDenseEqual(t *testing.T) {
+	s := DenseOf(1, 100)
+	s.Remove(100)
+
+	check.TrueMsg(t, s.Equal(DenseOf(1)), "DenseOf(1, 100) - 100 == DenseOf(1)")
+	check.TrueMsg(t, DenseOf(1).Equal(s), "DenseOf(1) == DenseOf(1, 100) - 100")
+	check.FalseMsg(t, s.Equal(DenseOf(1, 2)), "DenseOf(1) == DenseOf(1, 2)")
+	check.TrueMsg(t, NewDense(1000).Equal(&Dense{}), "NewDense(1000) == &Dense{}")
+}
+
+func TestDenseSetAlgebra(t *testing.T) {
+	a := func() *Dense { return DenseOf(1, 2, 3, 100) }
+	b := DenseOf(2, 3, 4, 200)
+
+	s := a()
+	s.Union(b)
+	check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), []int{1, 2, 3, 4, 100, 200}, "a ∪ b")
+
+	s = a()
+	s.Intersection(b)
+	check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), []int{2, 3}, "a ∩ b")
+
+	s = a()
+	s.Difference(b)
+	check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), []int{1, 100}, "a - b")
+
+	s = a()
+	s.SymmetricDifference(b)
+	check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), []int{1, 4, 100, 200}, "a △ b")
+
+	check.DeepEqMsg(t, iter.IntoSlice(UnionDense(a(), b, DenseOf(5)).Iter()), []int{1, 2, 3, 4, 5, 100, 200}, "UnionDense(a, b, {5})")
+	check.DeepEqMsg(t, iter.IntoSlice(IntersectionDense(a(), b, DenseOf(3)).Iter()), []int{3}, "IntersectionDense(a, b, {3})")
+	check.DeepEqMsg(t, iter.IntoSlice(IntersectionDense().Iter()), []int{}, "IntersectionDense()")
+
+	original := a()
+	UnionDense(original, b)
+	IntersectionDense(original, b)
+	check.TrueMsg(t, original.Equal(a()), "UnionDense and IntersectionDense don't modify arguments")
+
+	check.TrueMsg(t, DenseOf(1, 2).IsDisjoint(DenseOf(3, 300)), "{1, 2} disjoint {3, 300}")
+	check.FalseMsg(t, DenseOf(1, 300).IsDisjoint(DenseOf(3, 300)), "{1, 300} disjoint {3, 300}")
+	check.TrueMsg(t, DenseOf(2, 3).IsSubset(a()), "{2, 3} ⊆ a")
+	check.FalseMsg(t, DenseOf(2, 300).IsSubset(a()), "{2, 300} ⊆ a")
+	check.TrueMsg(t, a().IsSuperset(DenseOf(1, 100)), "a ⊇ {1, 100}")
+	check.FalseMsg(t, a().IsSuperset(b), "a ⊇ b")
+}
+
+func TestDenseComplement(t *testing.T) {
+	s := DenseOf(1, 5, 70)
+	s.Complement(3, 7)
+	check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), []int{1, 3, 4, 6, 70}, "{1, 5, 70}: complement [3, 7)")
+
+	s = DenseOf(1, 70)
+	s.Complement(60, 130)
+	check.EqMsg(t, s.Count(), 1+70-1, "{1, 70}: complement [60, 130): count")
+	check.FalseMsg(t, s.Has(70), "{1, 70}: complement [60, 130): has 70")
+	check.TrueMsg(t, s.Has(60), "{1, 70}: complement [60, 130): has 60")
+	check.TrueMsg(t, s.Has(129), "{1, 70}: complement [60, 130): has 129")
+	check.FalseMsg(t, s.Has(130), "{1, 70}: complement [60, 130): has 130")
+
+	s = DenseOf(1)
+	s.Complement(0, 64)
+	check.EqMsg(t, s.Count(), 63, "{1}: complement [0, 64): count")
+}
+
+func TestDenseNextPrev(t *testing.T) {
+	s := DenseOf(3, 64, 65, 200)
+
+	cases := []struct {
+		i         int
+		nextSet   int
+		nextOk    bool
+		nextClear int
+		prevSet   int
+		prevOk    bool
+	}{
+		{-5, 3, true, 0, 0, false},
+		{0, 3, true, 0, 0, false},
+		{3, 3, true, 4, 3, true},
+		{4, 64, true, 4, 3, true},
+		{64, 64, true, 66, 64, true},
+		{65, 65, true, 66, 65, true},
+		{66, 200, true, 66, 65, true},
+		{200, 200, true, 201, 200, true},
+		{201, 0, false, 201, 200, true},
+		{1000, 0, false, 1000, 200, true},
+	}
+
+	for _, c := range cases {
+		next, ok := s.NextSet(c.i)
+		check.EqMsg(t, ok, c.nextOk, fmt.Sprintf("s.NextSet(%d): ok", c.i))
+		if ok {
+			check.EqMsg(t, next, c.nextSet, fmt.Sprintf("s.NextSet(%d)", c.i))
+		}
+
+		check.EqMsg(t, s.NextClear(c.i), c.nextClear, fmt.Sprintf("s.NextClear(%d)", c.i))
+
+		prev, ok := s.PrevSet(c.i)
+		check.EqMsg(t, ok, c.prevOk, fmt.Sprintf("s.PrevSet(%d): ok", c.i))
+		if ok {
+			check.EqMsg(t, prev, c.prevSet, fmt.Sprintf("s.PrevSet(%d)", c.i))
+		}
+	}
+
+	full := &Dense{}
+	full.Complement(0, 128)
+	check.EqMsg(t, full.NextClear(5), 128, "full.NextClear(5)")
+}
+
+func TestDenseRankSelect(t *testing.T) {
+	s := DenseOf(3, 64, 65, 200)
+
+	for i, expected := range map[int]int{-1: 0, 0: 0, 3: 0, 4: 1, 64: 1, 65: 2, 66: 3, 200: 3, 201: 4, 1000: 4} {
+		check.EqMsg(t, s.Rank(i), expected, fmt.Sprintf("s.Rank(%d)", i))
+	}
+
+	for k, expected := range []int{3, 64, 65, 200} {
+		x, ok := s.Select(k)
+		check.TrueMsg(t, ok, fmt.Sprintf("s.Select(%d): ok", k))
+		check.EqMsg(t, x, expected, fmt.Sprintf("s.Select(%d)", k))
+	}
+
+	_, ok := s.Select(4)
+	check.FalseMsg(t, ok, "s.Select(4): ok")
+	_, ok = s.Select(-1)
+	check.FalseMsg(t, ok, "s.Select(-1): ok")
+}
+
+func TestDenseRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	s := &Dense{}
+	reference := &BitSet{}
+
+	for i := 0; i < 2000; i++ {
+		x := r.Intn(500)
+		switch r.Intn(3) {
+		case 0:
+			s.Remove(x)
+			reference.Remove(x)
+		case 1:
+			lo := r.Intn(500)
+			hi := lo + r.Intn(100)
+			s.Complement(lo, hi)
+			for y := lo; y < hi; y++ {
+				if reference.Has(y) {
+					reference.Remove(y)
+				} else {
+					reference.Add(y)
+				}
+			}
+		default:
+			s.Add(x)
+			reference.Add(x)
+		}
+	}
+
+	elements := iter.IntoSlice(reference.Iter())
+	check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), elements, "s.Iter()")
+	check.EqMsg(t, s.Count(), len(elements), "s.Count()")
+
+	for k, x := range elements {
+		check.EqMsg(t, s.Rank(x), k, fmt.Sprintf("s.Rank(%d)", x))
+		got, _ := s.Select(k)
+		check.EqMsg(t, got, x, fmt.Sprintf("s.Select(%d)", k))
+	}
+}
+
+// Benchmarks against BitSet
+
+const benchmarkSize = 1 << 16
+
+func benchmarkElements() []int {
+	r := rand.New(rand.NewSource(42))
+	elements := make([]int, benchmarkSize/4)
+	for i := range elements {
+		elements[i] = r.Intn(benchmarkSize)
+	}
+	return elements
+}
+
+func BenchmarkBitSetAdd(b *testing.B) {
+	elements := benchmarkElements()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		s := &BitSet{}
+		for _, x := range elements {
+			s.Add(x)
+		}
+	}
+}
+
+func BenchmarkDenseAdd(b *testing.B) {
+	elements := benchmarkElements()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		s := &Dense{}
+		for _, x := range elements {
+			s.Add(x)
+		}
+	}
+}
+
+func BenchmarkBitSetHas(b *testing.B) {
+	s := Of(benchmarkElements()...)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		s.Has(n % benchmarkSize)
+	}
+}
+
+func BenchmarkDenseHas(b *testing.B) {
+	s := DenseOf(benchmarkElements()...)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		s.Has(n % benchmarkSize)
+	}
+}
+
+func BenchmarkBitSetLen(b *testing.B) {
+	s := Of(benchmarkElements()...)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		s.Len()
+	}
+}
+
+func BenchmarkDenseCount(b *testing.B) {
+	s := DenseOf(benchmarkElements()...)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		s.Count()
+	}
+}
+
+func BenchmarkBitSetIter(b *testing.B) {
+	s := Of(benchmarkElements()...)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for it := s.Iter(); it.Next(); {
+		}
+	}
+}
+
+func BenchmarkDenseIter(b *testing.B) {
+	s := DenseOf(benchmarkElements()...)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for it := s.Iter(); it.Next(); {
+		}
+	}
+}
+
+func BenchmarkBitSetUnion(b *testing.B) {
+	s1 := Of(benchmarkElements()...)
+	s2 := Of(benchmarkElements()[1:]...)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		s := s1.Clone()
+		s.Union(s2)
+	}
+}
+
+func BenchmarkDenseUnion(b *testing.B) {
+	s1 := DenseOf(benchmarkElements()...)
+	s2 := DenseOf(benchmarkElements()[1:]...)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		UnionDense(s1, s2)
+	}
+}
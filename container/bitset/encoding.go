@@ -0,0 +1,469 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package bitset
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidData is returned when unmarshaling malformed data.
+	//
+	// To prevent small inputs from causing huge allocations, decoders also reject
+	// elements greater than or equal to 1<<26 (or 64 in case of [Small]).
+	ErrInvalidData = errors.New("bitset: invalid encoded data")
+
+	// ErrTooLarge is returned when marshaling a set with an element greater than
+	// or equal to 1<<26, which couldn't be unmarshaled back.
+	ErrTooLarge = errors.New("bitset: element too large to encode")
+)
+
+const (
+	// decodeLimit is the exclusive upper bound of elements accepted by decoders
+	// of BitSet and Dense, limiting decoded bitmaps to 8 MiB.
+	// Encoders of BitSet and Dense reject such elements as well.
+	decodeLimit = 1 << 26
+
+	// smallDecodeLimit is the exclusive upper bound of elements accepted by decoders of Small.
+	smallDecodeLimit = 64
+)
+
+const (
+	binaryTagRaw  byte = 0
+	binaryTagRuns byte = 1
+)
+
+// trimBitmap removes trailing zero bytes from a bitmap.
+func trimBitmap(b []byte) []byte {
+	for len(b) > 0 && b[len(b)-1] == 0 {
+		b = b[:len(b)-1]
+	}
+	return b
+}
+
+func bitmapHas(b []byte, i int) bool { return b[i/8]&(1<<(i%8)) != 0 }
+
+// bitmapRuns calls f with every run of consecutive elements, [start, end).
+func bitmapRuns(b []byte, f func(start, end int)) {
+	start := -1
+	for i := 0; i < 8*len(b); i++ {
+		switch has := bitmapHas(b, i); {
+		case has && start < 0:
+			start = i
+		case !has && start >= 0:
+			f(start, i)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		f(start, 8*len(b))
+	}
+}
+
+// bitmapFromRuns creates a bitmap from a list of runs, flattened into [start, end) pairs.
+// The runs must have already been checked against the decoding limit.
+func bitmapFromRuns(runs []int) []byte {
+	maxEnd := 0
+	for i := 1; i < len(runs); i += 2 {
+		if runs[i] > maxEnd {
+			maxEnd = runs[i]
+		}
+	}
+
+	b := make([]byte, (maxEnd+7)/8)
+	for i := 0; i < len(runs); i += 2 {
+		bitmapSetRun(b, runs[i], runs[i+1])
+	}
+	return trimBitmap(b)
+}
+
+// bitmapSetRun sets all bits in [start, end), whole bytes at a time in the middle of the run.
+func bitmapSetRun(b []byte, start, end int) {
+	for ; start < end && start%8 != 0; start++ {
+		b[start/8] |= 1 << (start % 8)
+	}
+	for ; start+8 <= end; start += 8 {
+		b[start/8] = 0xFF
+	}
+	for ; start < end; start++ {
+		b[start/8] |= 1 << (start % 8)
+	}
+}
+
+// bitmapEnd returns the largest element of a (trimmed) bitmap plus one,
+// or zero if the bitmap is empty.
+func bitmapEnd(b []byte) int {
+	if len(b) == 0 {
+		return 0
+	}
+	return 8*(len(b)-1) + bits.Len8(b[len(b)-1])
+}
+
+// checkBitmapLimit returns an error if a (trimmed) bitmap contains an element >= limit.
+func checkBitmapLimit(b []byte, limit int) error {
+	if bitmapEnd(b) > limit {
+		return fmt.Errorf("%w: element too large", ErrInvalidData)
+	}
+	return nil
+}
+
+// encodableBitmap returns the bitmap, or an error if it contains an element >= decodeLimit.
+func encodableBitmap(b []byte) ([]byte, error) {
+	if end := bitmapEnd(b); end > decodeLimit {
+		return nil, fmt.Errorf("%w: %d", ErrTooLarge, end-1)
+	}
+	return b, nil
+}
+
+func encodeBinary(bitmap []byte) []byte {
+	runs := []byte{binaryTagRuns}
+	buf := make([]byte, binary.MaxVarintLen64)
+	prevEnd := 0
+	bitmapRuns(bitmap, func(start, end int) {
+		runs = append(runs, buf[:binary.PutUvarint(buf, uint64(start-prevEnd))]...)
+		runs = append(runs, buf[:binary.PutUvarint(buf, uint64(end-start))]...)
+		prevEnd = end
+	})
+
+	if len(runs) <= len(bitmap)+1 {
+		return runs
+	}
+	return append([]byte{binaryTagRaw}, bitmap...)
+}
+
+func decodeBinary(data []byte, limit int) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, ErrInvalidData
+	}
+
+	switch data[0] {
+	case binaryTagRaw:
+		raw := trimBitmap(data[1:])
+		if err := checkBitmapLimit(raw, limit); err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), raw...), nil
+
+	case binaryTagRuns:
+		var runs []int
+		pos := 0
+		for data = data[1:]; len(data) > 0; {
+			gap, n := binary.Uvarint(data)
+			if n <= 0 {
+				return nil, ErrInvalidData
+			}
+			data = data[n:]
+
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length == 0 {
+				return nil, ErrInvalidData
+			}
+			data = data[n:]
+
+			// Check the limit before adding to avoid overflows
+			if gap > uint64(limit-pos) || length > uint64(limit-pos)-gap {
+				return nil, fmt.Errorf("%w: element too large", ErrInvalidData)
+			}
+			start := pos + int(gap)
+			pos = start + int(length)
+			runs = append(runs, start, pos)
+		}
+		return bitmapFromRuns(runs), nil
+
+	default:
+		return nil, ErrInvalidData
+	}
+}
+
+func encodeText(bitmap []byte) []byte {
+	var text []byte
+	bitmapRuns(bitmap, func(start, end int) {
+		if len(text) > 0 {
+			text = append(text, ',')
+		}
+		text = strconv.AppendInt(text, int64(start), 10)
+		if end-start > 1 {
+			text = append(text, '-')
+			text = strconv.AppendInt(text, int64(end-1), 10)
+		}
+	})
+	return text
+}
+
+func parseElement(s string, limit int) (int, error) {
+	x, err := strconv.ParseUint(s, 10, 0)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid element %q", ErrInvalidData, s)
+	} else if x >= uint64(limit) {
+		return 0, fmt.Errorf("%w: element too large: %s", ErrInvalidData, s)
+	}
+	return int(x), nil
+}
+
+func decodeText(text []byte, limit int) ([]byte, error) {
+	if len(text) == 0 {
+		return nil, nil
+	}
+
+	var runs []int
+	for _, part := range strings.Split(string(text), ",") {
+		first, last, isRange := strings.Cut(part, "-")
+
+		start, err := parseElement(first, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		end := start
+		if isRange {
+			if end, err = parseElement(last, limit); err != nil {
+				return nil, err
+			} else if end < start {
+				return nil, fmt.Errorf("%w: invalid range %q", ErrInvalidData, part)
+			}
+		}
+
+		runs = append(runs, start, end+1)
+	}
+	return bitmapFromRuns(runs), nil
+}
+
+func encodeJSON(bitmap []byte) []byte {
+	data := []byte{'['}
+	for i := 0; i < 8*len(bitmap); i++ {
+		if bitmapHas(bitmap, i) {
+			if len(data) > 1 {
+				data = append(data, ',')
+			}
+			data = strconv.AppendInt(data, int64(i), 10)
+		}
+	}
+	return append(data, ']')
+}
+
+func decodeJSON(data []byte, limit int) ([]byte, error) {
+	var elements []uint64
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, err
+	}
+
+	runs := make([]int, 0, 2*len(elements))
+	for _, x := range elements {
+		if x >= uint64(limit) {
+			return nil, fmt.Errorf("%w: element too large: %d", ErrInvalidData, x)
+		}
+		runs = append(runs, int(x), int(x)+1)
+	}
+	return bitmapFromRuns(runs), nil
+}
+
+// BitSet
+
+func (s *BitSet) bitmap() []byte {
+	b := s.n.Bytes()
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+func (s *BitSet) setBitmap(b []byte) {
+	be := make([]byte, len(b))
+	for i, x := range b {
+		be[len(b)-1-i] = x
+	}
+	s.n.SetBytes(be)
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler].
+// Returns [ErrTooLarge] if the set contains an element greater than or equal to 1<<26.
+func (s *BitSet) MarshalBinary() ([]byte, error) {
+	b, err := encodableBitmap(s.bitmap())
+	if err != nil {
+		return nil, err
+	}
+	return encodeBinary(b), nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+func (s *BitSet) UnmarshalBinary(data []byte) error {
+	b, err := decodeBinary(data, decodeLimit)
+	if err == nil {
+		s.setBitmap(b)
+	}
+	return err
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+// Returns [ErrTooLarge] if the set contains an element greater than or equal to 1<<26.
+func (s *BitSet) MarshalText() ([]byte, error) {
+	b, err := encodableBitmap(s.bitmap())
+	if err != nil {
+		return nil, err
+	}
+	return encodeText(b), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (s *BitSet) UnmarshalText(text []byte) error {
+	b, err := decodeText(text, decodeLimit)
+	if err == nil {
+		s.setBitmap(b)
+	}
+	return err
+}
+
+// MarshalJSON implements [json.Marshaler].
+// Returns [ErrTooLarge] if the set contains an element greater than or equal to 1<<26.
+func (s *BitSet) MarshalJSON() ([]byte, error) {
+	b, err := encodableBitmap(s.bitmap())
+	if err != nil {
+		return nil, err
+	}
+	return encodeJSON(b), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (s *BitSet) UnmarshalJSON(data []byte) error {
+	b, err := decodeJSON(data, decodeLimit)
+	if err == nil {
+		s.setBitmap(b)
+	}
+	return err
+}
+
+// Dense
+
+func (s *Dense) bitmap() []byte {
+	b := make([]byte, 8*len(s.words))
+	for i, w := range s.words {
+		binary.LittleEndian.PutUint64(b[8*i:], w)
+	}
+	return trimBitmap(b)
+}
+
+func (s *Dense) setBitmap(b []byte) {
+	s.words = make([]uint64, (len(b)+7)/8)
+	for i, x := range b {
+		s.words[i/8] |= uint64(x) << (8 * (i % 8))
+	}
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler].
+// Returns [ErrTooLarge] if the set contains an element greater than or equal to 1<<26.
+func (s *Dense) MarshalBinary() ([]byte, error) {
+	b, err := encodableBitmap(s.bitmap())
+	if err != nil {
+		return nil, err
+	}
+	return encodeBinary(b), nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+func (s *Dense) UnmarshalBinary(data []byte) error {
+	b, err := decodeBinary(data, decodeLimit)
+	if err == nil {
+		s.setBitmap(b)
+	}
+	return err
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+// Returns [ErrTooLarge] if the set contains an element greater than or equal to 1<<26.
+func (s *Dense) MarshalText() ([]byte, error) {
+	b, err := encodableBitmap(s.bitmap())
+	if err != nil {
+		return nil, err
+	}
+	return encodeText(b), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (s *Dense) UnmarshalText(text []byte) error {
+	b, err := decodeText(text, decodeLimit)
+	if err == nil {
+		s.setBitmap(b)
+	}
+	return err
+}
+
+// MarshalJSON implements [json.Marshaler].
+// Returns [ErrTooLarge] if the set contains an element greater than or equal to 1<<26.
+func (s *Dense) MarshalJSON() ([]byte, error) {
+	b, err := encodableBitmap(s.bitmap())
+	if err != nil {
+		return nil, err
+	}
+	return encodeJSON(b), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (s *Dense) UnmarshalJSON(data []byte) error {
+	b, err := decodeJSON(data, decodeLimit)
+	if err == nil {
+		s.setBitmap(b)
+	}
+	return err
+}
+
+// Small
+
+func (s Small) bitmap() []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, uint64(s))
+	return trimBitmap(b)
+}
+
+func (s *Small) setBitmap(b []byte) error {
+	if len(b) > 8 {
+		return fmt.Errorf("%w: element out of range for Small", ErrInvalidData)
+	}
+
+	padded := make([]byte, 8)
+	copy(padded, b)
+	*s = Small(binary.LittleEndian.Uint64(padded))
+	return nil
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler].
+func (s Small) MarshalBinary() ([]byte, error) { return encodeBinary(s.bitmap()), nil }
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+func (s *Small) UnmarshalBinary(data []byte) error {
+	b, err := decodeBinary(data, smallDecodeLimit)
+	if err != nil {
+		return err
+	}
+	return s.setBitmap(b)
+}
+
+// MarshalText implements [encoding.TextMarshaler].
+func (s Small) MarshalText() ([]byte, error) { return encodeText(s.bitmap()), nil }
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (s *Small) UnmarshalText(text []byte) error {
+	b, err := decodeText(text, smallDecodeLimit)
+	if err != nil {
+		return err
+	}
+	return s.setBitmap(b)
+}
+
+// MarshalJSON implements [json.Marshaler].
+func (s Small) MarshalJSON() ([]byte, error) { return encodeJSON(s.bitmap()), nil }
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (s *Small) UnmarshalJSON(data []byte) error {
+	b, err := decodeJSON(data, smallDecodeLimit)
+	if err != nil {
+		return err
+	}
+	return s.setBitmap(b)
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package bitset_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	. "github.com/MKuranowski/go-extra-lib/container/bitset"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/assert"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func TestBitSetMarshalBinary(t *testing.T) {
+	// Sparse sets are encoded as runs
+	data, err := Of(1, 2, 3, 1000).MarshalBinary()
+	assert.NoErr(t, err)
+	check.DeepEqMsg(t, data, []byte{1, 1, 3, 0xE4, 0x07, 1}, "Of(1, 2, 3, 1000).MarshalBinary()")
+
+	// Dense sets are encoded as raw bits
+	data, err = Of(0, 2, 4, 6, 8, 10, 12).MarshalBinary()
+	assert.NoErr(t, err)
+	check.DeepEqMsg(t, data, []byte{0, 0x55, 0x15}, "Of(0, 2, ..., 12).MarshalBinary()")
+
+	data, err = (&BitSet{}).MarshalBinary()
+	assert.NoErr(t, err)
+	check.DeepEqMsg(t, data, []byte{1}, "Of().MarshalBinary()")
+}
+
+func TestBitSetEncodingRoundTrip(t *testing.T) {
+	sets := []*BitSet{Of(), Of(0), Of(1, 2, 3, 1000), Of(0, 2, 4, 6, 8, 10, 12), Of(63, 64, 65, 100000)}
+
+	for _, s := range sets {
+		text, _ := s.MarshalText()
+		name := "{" + string(text) + "}"
+
+		data, err := s.MarshalBinary()
+		assert.NoErr(t, err)
+		decoded := Of(5)
+		assert.NoErr(t, decoded.UnmarshalBinary(data))
+		check.TrueMsg(t, decoded.Equal(s), name+": binary round trip")
+
+		text, err = s.MarshalText()
+		assert.NoErr(t, err)
+		decoded = Of(5)
+		assert.NoErr(t, decoded.UnmarshalText(text))
+		check.TrueMsg(t, decoded.Equal(s), name+": text round trip")
+
+		data, err = json.Marshal(s)
+		assert.NoErr(t, err)
+		decoded = Of(5)
+		assert.NoErr(t, json.Unmarshal(data, decoded))
+		check.TrueMsg(t, decoded.Equal(s), name+": JSON round trip")
+
+		var buf bytes.Buffer
+		assert.NoErr(t, gob.NewEncoder(&buf).Encode(s))
+		decoded = Of(5)
+		assert.NoErr(t, gob.NewDecoder(&buf).Decode(decoded))
+		check.TrueMsg(t, decoded.Equal(s), name+": gob round trip")
+	}
+}
+
+func TestBitSetTextAndJSON(t *testing.T) {
+	text, err := Of(1, 2, 3, 5, 8, 9).MarshalText()
+	assert.NoErr(t, err)
+	check.EqMsg(t, string(text), "1-3,5,8-9", "Of(1, 2, 3, 5, 8, 9).MarshalText()")
+
+	data, err := json.Marshal(struct{ S *BitSet }{Of(10, 1, 3)})
+	assert.NoErr(t, err)
+	check.EqMsg(t, string(data), `{"S":[1,3,10]}`, "json.Marshal(Of(10, 1, 3))")
+
+	s := &BitSet{}
+	assert.NoErr(t, s.UnmarshalText([]byte("7,1-2")))
+	check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), []int{1, 2, 7}, "UnmarshalText(7,1-2)")
+
+	check.SpecificErrMsg(t, s.UnmarshalText([]byte("1-")), ErrInvalidData, "UnmarshalText(1-)")
+	check.SpecificErrMsg(t, s.UnmarshalText([]byte("3-1")), ErrInvalidData, "UnmarshalText(3-1)")
+	check.SpecificErrMsg(t, s.UnmarshalText([]byte("-1")), ErrInvalidData, "UnmarshalText(-1)")
+	check.SpecificErrMsg(t, s.UnmarshalBinary(nil), ErrInvalidData, "UnmarshalBinary(nil)")
+	check.SpecificErrMsg(t, s.UnmarshalBinary([]byte{2}), ErrInvalidData, "UnmarshalBinary(invalid tag)")
+	check.SpecificErrMsg(t, s.UnmarshalBinary([]byte{1, 0x80}), ErrInvalidData, "UnmarshalBinary(truncated uvarint)")
+	check.ErrMsg(t, json.Unmarshal([]byte(`[-1]`), s), "json.Unmarshal([-1])")
+}
+
+func TestDenseEncodingRoundTrip(t *testing.T) {
+	sets := []*Dense{DenseOf(), DenseOf(0), DenseOf(1, 2, 3, 1000), DenseOf(63, 64, 65, 100000)}
+
+	for _, s := range sets {
+		data, err := s.MarshalBinary()
+		assert.NoErr(t, err)
+		decoded := DenseOf(5)
+		assert.NoErr(t, decoded.UnmarshalBinary(data))
+		check.TrueMsg(t, decoded.Equal(s), "binary round trip")
+
+		text, err := s.MarshalText()
+		assert.NoErr(t, err)
+		decoded = DenseOf(5)
+		assert.NoErr(t, decoded.UnmarshalText(text))
+		check.TrueMsg(t, decoded.Equal(s), "text round trip")
+
+		data, err = json.Marshal(s)
+		assert.NoErr(t, err)
+		decoded = DenseOf(5)
+		assert.NoErr(t, json.Unmarshal(data, decoded))
+		check.TrueMsg(t, decoded.Equal(s), "JSON round trip")
+	}
+
+	// Encodings are interchangeable between set types
+	data, _ := DenseOf(1, 2, 3, 1000).MarshalBinary()
+	s := &BitSet{}
+	assert.NoErr(t, s.UnmarshalBinary(data))
+	check.TrueMsg(t, s.Equal(Of(1, 2, 3, 1000)), "Dense → BitSet")
+}
+
+func TestSmallEncodingRoundTrip(t *testing.T) {
+	sets := []Small{SmallOf(), SmallOf(0), SmallOf(1, 2, 3, 40), SmallOf(0, 2, 4, 6, 8, 63)}
+
+	for _, s := range sets {
+		data, err := s.MarshalBinary()
+		assert.NoErr(t, err)
+		decoded := SmallOf(5)
+		assert.NoErr(t, decoded.UnmarshalBinary(data))
+		check.EqMsg(t, decoded, s, "binary round trip")
+
+		text, err := s.MarshalText()
+		assert.NoErr(t, err)
+		decoded = SmallOf(5)
+		assert.NoErr(t, decoded.UnmarshalText(text))
+		check.EqMsg(t, decoded, s, "text round trip")
+
+		data, err = json.Marshal(s)
+		assert.NoErr(t, err)
+		decoded = SmallOf(5)
+		assert.NoErr(t, json.Unmarshal(data, &decoded))
+		check.EqMsg(t, decoded, s, "JSON round trip")
+
+		var buf bytes.Buffer
+		assert.NoErr(t, gob.NewEncoder(&buf).Encode(s))
+		decoded = SmallOf(5)
+		assert.NoErr(t, gob.NewDecoder(&buf).Decode(&decoded))
+		check.EqMsg(t, decoded, s, "gob round trip")
+	}
+
+	s := Small(0)
+	check.SpecificErrMsg(t, s.UnmarshalText([]byte("1,64")), ErrInvalidData, "UnmarshalText(1,64)")
+}
+
+type unmarshaler interface {
+	UnmarshalBinary([]byte) error
+	UnmarshalText([]byte) error
+	UnmarshalJSON([]byte) error
+}
+
+func checkOversizedRejected(t *testing.T, name string, u unmarshaler, limit int) {
+	t.Helper()
+
+	check.SpecificErrMsg(t, u.UnmarshalText([]byte("0-4294967295")), ErrInvalidData, name+".UnmarshalText(0-4294967295)")
+	check.SpecificErrMsg(t, u.UnmarshalText([]byte(strconv.Itoa(limit))), ErrInvalidData, name+".UnmarshalText(limit)")
+	check.SpecificErrMsg(t, u.UnmarshalJSON([]byte("[1,4294967295]")), ErrInvalidData, name+".UnmarshalJSON([1,4294967295])")
+	check.SpecificErrMsg(t, u.UnmarshalJSON([]byte("["+strconv.Itoa(limit)+"]")), ErrInvalidData, name+".UnmarshalJSON([limit])")
+
+	// A single run [0, 2³²), encoded as gap=0 and length=2³² uvarints
+	check.SpecificErrMsg(
+		t,
+		u.UnmarshalBinary([]byte{1, 0, 0x80, 0x80, 0x80, 0x80, 0x10}),
+		ErrInvalidData,
+		name+".UnmarshalBinary(run of 2³² elements)",
+	)
+
+	// Runs which would overflow int, if added together
+	check.SpecificErrMsg(
+		t,
+		u.UnmarshalBinary([]byte{1, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F, 1}),
+		ErrInvalidData,
+		name+".UnmarshalBinary(huge gap)",
+	)
+
+	// Raw bitmap with the first element past the limit
+	raw := make([]byte, 1+limit/8+1)
+	raw[len(raw)-1] = 1
+	check.SpecificErrMsg(t, u.UnmarshalBinary(raw), ErrInvalidData, name+".UnmarshalBinary(raw, limit)")
+
+	// Elements just below the limit are accepted
+	assert.NoErr(t, u.UnmarshalText([]byte("0-"+strconv.Itoa(limit-1))))
+}
+
+func TestOversizedInputsRejected(t *testing.T) {
+	checkOversizedRejected(t, "BitSet", &BitSet{}, 1<<26)
+	checkOversizedRejected(t, "Dense", &Dense{}, 1<<26)
+	s := Small(0)
+	checkOversizedRejected(t, "Small", &s, 64)
+	check.EqMsg(t, s.Len(), 64, "Small.Len() after decoding 0-63")
+}
+
+type marshaler interface {
+	MarshalBinary() ([]byte, error)
+	MarshalText() ([]byte, error)
+	MarshalJSON() ([]byte, error)
+}
+
+func checkLimitRoundTrip(t *testing.T, name string, below, above marshaler, decoded unmarshaler) {
+	t.Helper()
+
+	// The largest element which can be encoded survives a round trip
+	data, err := below.MarshalBinary()
+	assert.NoErr(t, err)
+	assert.NoErr(t, decoded.UnmarshalBinary(data))
+
+	data, err = below.MarshalText()
+	assert.NoErr(t, err)
+	check.EqMsg(t, string(data), strconv.Itoa(1<<26-1), name+".MarshalText(limit-1)")
+	assert.NoErr(t, decoded.UnmarshalText(data))
+
+	data, err = below.MarshalJSON()
+	assert.NoErr(t, err)
+	assert.NoErr(t, decoded.UnmarshalJSON(data))
+
+	// The limit itself is rejected by both encoders and decoders
+	_, err = above.MarshalBinary()
+	check.SpecificErrMsg(t, err, ErrTooLarge, name+".MarshalBinary(limit)")
+	_, err = above.MarshalText()
+	check.SpecificErrMsg(t, err, ErrTooLarge, name+".MarshalText(limit)")
+	_, err = above.MarshalJSON()
+	check.SpecificErrMsg(t, err, ErrTooLarge, name+".MarshalJSON(limit)")
+	_, err = json.Marshal(above)
+	check.SpecificErrMsg(t, err, ErrTooLarge, "json.Marshal("+name+"(limit))")
+}
+
+func TestEncodeLimit(t *testing.T) {
+	decodedSet := &BitSet{}
+	checkLimitRoundTrip(t, "BitSet", Of(1<<26-1), Of(1<<26), decodedSet)
+	check.TrueMsg(t, decodedSet.Equal(Of(1<<26-1)), "decoded BitSet equals Of(limit-1)")
+
+	decodedDense := &Dense{}
+	checkLimitRoundTrip(t, "Dense", DenseOf(1<<26-1), DenseOf(1<<26), decodedDense)
+	check.TrueMsg(t, decodedDense.Equal(DenseOf(1<<26-1)), "decoded Dense equals DenseOf(limit-1)")
+}
+
+func TestDecodeLongRun(t *testing.T) {
+	s := &Dense{}
+	assert.NoErr(t, s.UnmarshalText([]byte("3-1000,1003")))
+	check.EqMsg(t, s.Count(), 999, "s.Count()")
+	check.FalseMsg(t, s.Has(2), "s.Has(2)")
+	check.TrueMsg(t, s.Has(3), "s.Has(3)")
+	check.TrueMsg(t, s.Has(1000), "s.Has(1000)")
+	check.FalseMsg(t, s.Has(1001), "s.Has(1001)")
+	check.TrueMsg(t, s.Has(1003), "s.Has(1003)")
+
+	data, err := s.MarshalBinary()
+	assert.NoErr(t, err)
+	decoded := &Dense{}
+	assert.NoErr(t, decoded.UnmarshalBinary(data))
+	check.TrueMsg(t, decoded.Equal(s), "binary round trip")
+}
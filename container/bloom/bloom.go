@@ -0,0 +1,274 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// bloom contains Bloom filters - probabilistic set-membership structures,
+// which may report false positives, but never report false negatives.
+//
+// Elements are hashed with 128-bit FNV-1a, and the k bit positions are derived
+// using double hashing. The hashing is deterministic, so serialized filters
+// can be safely shared between processes.
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+var (
+	// ErrIncompatible is returned when trying to combine filters with different sizes
+	// or numbers of hash functions.
+	ErrIncompatible = errors.New("bloom: incompatible filters")
+
+	// ErrInvalidData is returned when unmarshaling malformed binary data.
+	ErrInvalidData = errors.New("bloom: invalid binary data")
+)
+
+const (
+	formatFilter   byte = 'b'
+	formatCounting byte = 'c'
+
+	headerSize = 1 + 8 + 4 // format, m, k
+
+	// maxHashes is the upper bound on the number of hash functions.
+	// OptimalParameters never returns more than ~1100 hash functions,
+	// even for the smallest positive false-positive rate.
+	maxHashes = 1 << 12
+)
+
+// OptimalParameters returns the number of bits (m) and the number of hash functions (k)
+// for a filter expected to hold n elements with the provided false-positive rate.
+//
+// Panics if n is not positive, or if falsePositiveRate is not in (0, 1).
+func OptimalParameters(n int, falsePositiveRate float64) (m, k int) {
+	if n <= 0 {
+		panic(fmt.Sprintf("bloom: expected count must be positive, got %d", n))
+	}
+	if !(falsePositiveRate > 0 && falsePositiveRate < 1) {
+		panic(fmt.Sprintf("bloom: false positive rate must be in (0, 1), got %g", falsePositiveRate))
+	}
+
+	m = int(math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	k = int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return
+}
+
+// hashes contains two independent hashes of an element, used to derive bit positions.
+type hashes struct{ h1, h2 uint64 }
+
+func hashBytes(data []byte) hashes {
+	h := fnv.New128a()
+	h.Write(data)
+	var sum [16]byte
+	h.Sum(sum[:0])
+	return hashes{binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:]) | 1}
+}
+
+// location returns the i-th bit position, in range [0, m).
+func (h hashes) location(i uint32, m uint64) uint64 {
+	return (h.h1 + uint64(i)*h.h2) % m
+}
+
+// params holds the shape of a filter, shared by Filter and CountingFilter.
+type params struct {
+	m uint64 // number of bits or counters
+	k uint32 // number of hash functions
+}
+
+func newParams(m, k int) params {
+	if m <= 0 {
+		panic(fmt.Sprintf("bloom: number of bits must be positive, got %d", m))
+	}
+	if k <= 0 || k > maxHashes {
+		panic(fmt.Sprintf("bloom: number of hash functions must be in [1, %d], got %d", maxHashes, k))
+	}
+	return params{uint64(m), uint32(k)}
+}
+
+// marshal returns a byte slice with the header filled in and room for extra bytes of payload.
+func (p params) marshal(format byte, extra int) []byte {
+	b := make([]byte, headerSize+extra)
+	b[0] = format
+	binary.BigEndian.PutUint64(b[1:9], p.m)
+	binary.BigEndian.PutUint32(b[9:13], p.k)
+	return b
+}
+
+func readHeader(b []byte, format byte) (params, []byte, error) {
+	if len(b) < headerSize || b[0] != format {
+		return params{}, nil, ErrInvalidData
+	}
+	p := params{binary.BigEndian.Uint64(b[1:9]), binary.BigEndian.Uint32(b[9:13])}
+	if p.m == 0 || p.k == 0 || p.k > maxHashes {
+		return params{}, nil, ErrInvalidData
+	}
+	return p, b[headerSize:], nil
+}
+
+// estimateCount estimates the number of elements in a filter with x bits set.
+func (p params) estimateCount(x uint64) int {
+	if x >= p.m {
+		return math.MaxInt
+	}
+	m := float64(p.m)
+	return int(math.Round(-m / float64(p.k) * math.Log(1-float64(x)/m)))
+}
+
+// Filter is a Bloom filter - a set of byte strings, which may return false positives.
+//
+// Use [New] or [NewWithSize] to create a Filter; the zero value is not usable.
+type Filter struct {
+	params
+	words []uint64
+}
+
+// New returns an empty Filter sized to hold n elements with the provided false-positive rate.
+//
+// Panics if n is not positive, or if falsePositiveRate is not in (0, 1).
+func New(n int, falsePositiveRate float64) *Filter {
+	return NewWithSize(OptimalParameters(n, falsePositiveRate))
+}
+
+// NewWithSize returns an empty Filter with m bits and k hash functions.
+//
+// Panics if m is not positive, or if k is not in [1, 4096].
+func NewWithSize(m, k int) *Filter {
+	p := newParams(m, k)
+	return &Filter{params: p, words: make([]uint64, (p.m+63)/64)}
+}
+
+// Size returns the number of bits in the filter.
+func (f *Filter) Size() int { return int(f.m) }
+
+// Hashes returns the number of hash functions used by the filter.
+func (f *Filter) Hashes() int { return int(f.k) }
+
+func (f *Filter) add(h hashes) {
+	for i := uint32(0); i < f.k; i++ {
+		loc := h.location(i, f.m)
+		f.words[loc/64] |= 1 << (loc % 64)
+	}
+}
+
+func (f *Filter) mayContain(h hashes) bool {
+	for i := uint32(0); i < f.k; i++ {
+		loc := h.location(i, f.m)
+		if f.words[loc/64]&(1<<(loc%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Add adds an element to the filter.
+//
+// Complexity: O(k + len(data))
+func (f *Filter) Add(data []byte) { f.add(hashBytes(data)) }
+
+// AddString adds an element to the filter.
+//
+// Complexity: O(k + len(s))
+func (f *Filter) AddString(s string) { f.add(hashBytes([]byte(s))) }
+
+// MayContain returns false if the element was definitely not added to the filter,
+// and true if the element may have been added to the filter.
+//
+// Complexity: O(k + len(data))
+func (f *Filter) MayContain(data []byte) bool { return f.mayContain(hashBytes(data)) }
+
+// MayContainString returns false if the element was definitely not added to the filter,
+// and true if the element may have been added to the filter.
+//
+// Complexity: O(k + len(s))
+func (f *Filter) MayContainString(s string) bool { return f.mayContain(hashBytes([]byte(s))) }
+
+// OnesCount returns the number of set bits in the filter.
+func (f *Filter) OnesCount() int {
+	n := 0
+	for _, w := range f.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// EstimatedCount approximates the number of distinct elements added to the filter,
+// based on the number of set bits.
+func (f *Filter) EstimatedCount() int { return f.estimateCount(uint64(f.OnesCount())) }
+
+// Clear removes all elements from the filter.
+func (f *Filter) Clear() {
+	for i := range f.words {
+		f.words[i] = 0
+	}
+}
+
+// Clone returns a copy of the filter.
+func (f *Filter) Clone() *Filter {
+	return &Filter{params: f.params, words: append([]uint64(nil), f.words...)}
+}
+
+// Equal returns true if both filters have the same shape and the same bits set.
+func (f1 *Filter) Equal(f2 *Filter) bool {
+	if f1.params != f2.params {
+		return false
+	}
+	for i, w := range f1.words {
+		if w != f2.words[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Union ensures that f1 reports all elements added to f2 as possibly present.
+//
+// Returns [ErrIncompatible] if the filters have different sizes or numbers of hash functions.
+func (f1 *Filter) Union(f2 *Filter) error {
+	if f1.params != f2.params {
+		return ErrIncompatible
+	}
+	for i, w := range f2.words {
+		f1.words[i] |= w
+	}
+	return nil
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler].
+//
+// The format is a single format byte, followed by the number of bits (big-endian uint64),
+// the number of hash functions (big-endian uint32) and the bits, as big-endian uint64 words.
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	b := f.marshal(formatFilter, 8*len(f.words))
+	for i, w := range f.words {
+		binary.BigEndian.PutUint64(b[headerSize+8*i:], w)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+// Returns [ErrInvalidData] if the data is malformed.
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	p, data, err := readHeader(data, formatFilter)
+	if err != nil {
+		return err
+	}
+
+	// (p.m + 63) / 64 could overflow
+	words := p.m/64 + (p.m%64+63)/64
+	if len(data)%8 != 0 || uint64(len(data)/8) != words {
+		return ErrInvalidData
+	}
+
+	f.params = p
+	f.words = make([]uint64, words)
+	for i := range f.words {
+		f.words[i] = binary.BigEndian.Uint64(data[8*i:])
+	}
+	return nil
+}
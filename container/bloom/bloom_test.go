@@ -0,0 +1,161 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package bloom_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/container/bloom"
+	"github.com/MKuranowski/go-extra-lib/testing2/assert"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func TestOptimalParameters(t *testing.T) {
+	m, k := bloom.OptimalParameters(1000, 0.01)
+	check.EqMsg(t, m, 9586, "m")
+	check.EqMsg(t, k, 7, "k")
+}
+
+func TestFilterNoFalseNegatives(t *testing.T) {
+	f := bloom.New(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		f.AddString(strconv.Itoa(i))
+	}
+	for i := 0; i < 1000; i++ {
+		if !f.MayContainString(strconv.Itoa(i)) {
+			t.Errorf("f.MayContainString(%d) = false", i)
+		}
+	}
+}
+
+func TestFilterFalsePositiveRate(t *testing.T) {
+	f := bloom.New(10000, 0.01)
+	for i := 0; i < 10000; i++ {
+		f.Add([]byte("in-" + strconv.Itoa(i)))
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if f.MayContain([]byte("out-" + strconv.Itoa(i))) {
+			falsePositives++
+		}
+	}
+
+	if falsePositives > 200 {
+		t.Errorf("too many false positives: %d / 10000", falsePositives)
+	}
+
+	estimated := f.EstimatedCount()
+	if estimated < 9500 || estimated > 10500 {
+		t.Errorf("f.EstimatedCount() = %d, expected about 10000", estimated)
+	}
+}
+
+func TestFilterUnion(t *testing.T) {
+	f1 := bloom.New(100, 0.01)
+	f1.AddString("foo")
+	f2 := bloom.New(100, 0.01)
+	f2.AddString("bar")
+
+	assert.NoErr(t, f1.Union(f2))
+	check.TrueMsg(t, f1.MayContainString("foo"), "f1.MayContainString(foo)")
+	check.TrueMsg(t, f1.MayContainString("bar"), "f1.MayContainString(bar)")
+
+	check.SpecificErrMsg(t, f1.Union(bloom.New(200, 0.01)), bloom.ErrIncompatible, "f1.Union(different size)")
+	check.SpecificErrMsg(t, f1.Union(bloom.NewWithSize(f1.Size(), f1.Hashes()+1)), bloom.ErrIncompatible, "f1.Union(different hashes)")
+}
+
+func TestFilterBinary(t *testing.T) {
+	f := bloom.New(100, 0.01)
+	f.AddString("foo")
+	f.AddString("bar")
+
+	data, err := f.MarshalBinary()
+	assert.NoErr(t, err)
+
+	g := &bloom.Filter{}
+	assert.NoErr(t, g.UnmarshalBinary(data))
+	check.TrueMsg(t, f.Equal(g), "f.Equal(g)")
+	check.TrueMsg(t, g.MayContainString("foo"), "g.MayContainString(foo)")
+	check.TrueMsg(t, g.MayContainString("bar"), "g.MayContainString(bar)")
+
+	check.SpecificErrMsg(t, g.UnmarshalBinary(data[:len(data)-1]), bloom.ErrInvalidData, "g.UnmarshalBinary(truncated)")
+	check.SpecificErrMsg(t, g.UnmarshalBinary(nil), bloom.ErrInvalidData, "g.UnmarshalBinary(nil)")
+
+	counting, _ := bloom.NewCounting(100, 0.01).MarshalBinary()
+	check.SpecificErrMsg(t, g.UnmarshalBinary(counting), bloom.ErrInvalidData, "g.UnmarshalBinary(counting)")
+}
+
+func TestFilterBinaryMalformedHeader(t *testing.T) {
+	g := &bloom.Filter{}
+
+	hugeM := []byte{'b', 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 3}
+	check.SpecificErrMsg(t, g.UnmarshalBinary(hugeM), bloom.ErrInvalidData, "g.UnmarshalBinary(m = 2^64-1)")
+
+	hugeK := []byte{'b', 0, 0, 0, 0, 0, 0, 0, 64, 0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0, 0, 0, 0, 0}
+	check.SpecificErrMsg(t, g.UnmarshalBinary(hugeK), bloom.ErrInvalidData, "g.UnmarshalBinary(k = 2^32-1)")
+
+	c := &bloom.CountingFilter{}
+	hugeM[0] = 'c'
+	check.SpecificErrMsg(t, c.UnmarshalBinary(hugeM), bloom.ErrInvalidData, "c.UnmarshalBinary(m = 2^64-1)")
+}
+
+func TestCountingFilter(t *testing.T) {
+	f := bloom.NewCounting(100, 0.01)
+	f.AddString("foo")
+	f.AddString("bar")
+	f.AddString("bar")
+
+	check.TrueMsg(t, f.MayContainString("foo"), "f.MayContainString(foo)")
+	check.TrueMsg(t, f.MayContainString("bar"), "f.MayContainString(bar)")
+
+	check.TrueMsg(t, f.RemoveString("foo"), "f.RemoveString(foo)")
+	check.FalseMsg(t, f.MayContainString("foo"), "f.MayContainString(foo): after remove")
+
+	check.TrueMsg(t, f.RemoveString("bar"), "f.RemoveString(bar)")
+	check.TrueMsg(t, f.MayContainString("bar"), "f.MayContainString(bar): after 1 remove")
+	check.TrueMsg(t, f.Remove([]byte("bar")), "f.Remove(bar)")
+	check.FalseMsg(t, f.MayContainString("bar"), "f.MayContainString(bar): after 2 removes")
+
+	check.FalseMsg(t, f.RemoveString("baz"), "f.RemoveString(baz)")
+	check.EqMsg(t, f.EstimatedCount(), 0, "f.EstimatedCount()")
+}
+
+func TestCountingFilterUnionAndConversion(t *testing.T) {
+	f1 := bloom.NewCounting(100, 0.01)
+	f1.AddString("foo")
+	f2 := bloom.NewCounting(100, 0.01)
+	f2.AddString("foo")
+	f2.AddString("bar")
+
+	assert.NoErr(t, f1.Union(f2))
+	check.TrueMsg(t, f1.RemoveString("foo"), "f1.RemoveString(foo)")
+	check.TrueMsg(t, f1.MayContainString("foo"), "f1.MayContainString(foo): after 1 remove")
+	check.TrueMsg(t, f1.MayContainString("bar"), "f1.MayContainString(bar)")
+
+	plain := bloom.New(100, 0.01)
+	plain.AddString("foo")
+	plain.AddString("bar")
+	check.TrueMsg(t, f1.Filter().Equal(plain), "f1.Filter().Equal(plain)")
+
+	check.SpecificErrMsg(t, f1.Union(bloom.NewCounting(200, 0.01)), bloom.ErrIncompatible, "f1.Union(different size)")
+}
+
+func TestCountingFilterBinary(t *testing.T) {
+	f := bloom.NewCounting(100, 0.01)
+	f.AddString("foo")
+	f.AddString("foo")
+
+	data, err := f.MarshalBinary()
+	assert.NoErr(t, err)
+
+	g := &bloom.CountingFilter{}
+	assert.NoErr(t, g.UnmarshalBinary(data))
+	check.TrueMsg(t, f.Equal(g), "f.Equal(g)")
+	check.TrueMsg(t, g.RemoveString("foo"), "g.RemoveString(foo)")
+	check.TrueMsg(t, g.MayContainString("foo"), "g.MayContainString(foo): after 1 remove")
+
+	check.SpecificErrMsg(t, g.UnmarshalBinary(data[:len(data)-1]), bloom.ErrInvalidData, "g.UnmarshalBinary(truncated)")
+}
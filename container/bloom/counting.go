@@ -0,0 +1,193 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package bloom
+
+import "math"
+
+// CountingFilter is a Bloom filter which keeps a counter instead of a single bit
+// for every position, and thus supports removal of elements.
+//
+// Counters saturate at 255 - a saturated counter is never decremented,
+// which guarantees no false negatives at the cost of possibly never
+// clearing that position.
+//
+// Removing an element which was never added can introduce false negatives.
+//
+// Use [NewCounting] or [NewCountingWithSize] to create a CountingFilter; the zero value is not usable.
+type CountingFilter struct {
+	params
+	counters []uint8
+}
+
+// NewCounting returns an empty CountingFilter sized to hold n elements
+// with the provided false-positive rate.
+//
+// Panics if n is not positive, or if falsePositiveRate is not in (0, 1).
+func NewCounting(n int, falsePositiveRate float64) *CountingFilter {
+	return NewCountingWithSize(OptimalParameters(n, falsePositiveRate))
+}
+
+// NewCountingWithSize returns an empty CountingFilter with m counters and k hash functions.
+//
+// Panics if m is not positive, or if k is not in [1, 4096].
+func NewCountingWithSize(m, k int) *CountingFilter {
+	p := newParams(m, k)
+	return &CountingFilter{params: p, counters: make([]uint8, p.m)}
+}
+
+// Size returns the number of counters in the filter.
+func (f *CountingFilter) Size() int { return int(f.m) }
+
+// Hashes returns the number of hash functions used by the filter.
+func (f *CountingFilter) Hashes() int { return int(f.k) }
+
+func (f *CountingFilter) add(h hashes) {
+	for i := uint32(0); i < f.k; i++ {
+		loc := h.location(i, f.m)
+		if f.counters[loc] < math.MaxUint8 {
+			f.counters[loc]++
+		}
+	}
+}
+
+func (f *CountingFilter) remove(h hashes) bool {
+	if !f.mayContain(h) {
+		return false
+	}
+
+	for i := uint32(0); i < f.k; i++ {
+		loc := h.location(i, f.m)
+		if f.counters[loc] < math.MaxUint8 {
+			f.counters[loc]--
+		}
+	}
+	return true
+}
+
+func (f *CountingFilter) mayContain(h hashes) bool {
+	for i := uint32(0); i < f.k; i++ {
+		if f.counters[h.location(i, f.m)] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Add adds an element to the filter.
+//
+// Complexity: O(k + len(data))
+func (f *CountingFilter) Add(data []byte) { f.add(hashBytes(data)) }
+
+// AddString adds an element to the filter.
+//
+// Complexity: O(k + len(s))
+func (f *CountingFilter) AddString(s string) { f.add(hashBytes([]byte(s))) }
+
+// Remove removes a previously added element from the filter.
+// Returns false (and leaves the filter unchanged) if the element is definitely not in the filter.
+//
+// Complexity: O(k + len(data))
+func (f *CountingFilter) Remove(data []byte) bool { return f.remove(hashBytes(data)) }
+
+// RemoveString removes a previously added element from the filter.
+// Returns false (and leaves the filter unchanged) if the element is definitely not in the filter.
+//
+// Complexity: O(k + len(s))
+func (f *CountingFilter) RemoveString(s string) bool { return f.remove(hashBytes([]byte(s))) }
+
+// MayContain returns false if the element is definitely not in the filter,
+// and true if the element may be in the filter.
+//
+// Complexity: O(k + len(data))
+func (f *CountingFilter) MayContain(data []byte) bool { return f.mayContain(hashBytes(data)) }
+
+// MayContainString returns false if the element is definitely not in the filter,
+// and true if the element may be in the filter.
+//
+// Complexity: O(k + len(s))
+func (f *CountingFilter) MayContainString(s string) bool { return f.mayContain(hashBytes([]byte(s))) }
+
+// EstimatedCount approximates the number of distinct elements in the filter,
+// based on the number of non-zero counters.
+func (f *CountingFilter) EstimatedCount() int {
+	nonZero := uint64(0)
+	for _, c := range f.counters {
+		if c != 0 {
+			nonZero++
+		}
+	}
+	return f.estimateCount(nonZero)
+}
+
+// Clear removes all elements from the filter.
+func (f *CountingFilter) Clear() {
+	for i := range f.counters {
+		f.counters[i] = 0
+	}
+}
+
+// Clone returns a copy of the filter.
+func (f *CountingFilter) Clone() *CountingFilter {
+	return &CountingFilter{params: f.params, counters: append([]uint8(nil), f.counters...)}
+}
+
+// Equal returns true if both filters have the same shape and the same counters.
+func (f1 *CountingFilter) Equal(f2 *CountingFilter) bool {
+	return f1.params == f2.params && string(f1.counters) == string(f2.counters)
+}
+
+// Union ensures that f1 contains all elements from f2, by adding the counters (saturating at 255).
+//
+// Returns [ErrIncompatible] if the filters have different sizes or numbers of hash functions.
+func (f1 *CountingFilter) Union(f2 *CountingFilter) error {
+	if f1.params != f2.params {
+		return ErrIncompatible
+	}
+	for i, c := range f2.counters {
+		if sum := int(f1.counters[i]) + int(c); sum < math.MaxUint8 {
+			f1.counters[i] = uint8(sum)
+		} else {
+			f1.counters[i] = math.MaxUint8
+		}
+	}
+	return nil
+}
+
+// Filter returns a plain [Filter] with the same shape, reporting the same elements as possibly present.
+func (f *CountingFilter) Filter() *Filter {
+	plain := &Filter{params: f.params, words: make([]uint64, (f.m+63)/64)}
+	for i, c := range f.counters {
+		if c != 0 {
+			plain.words[i/64] |= 1 << (i % 64)
+		}
+	}
+	return plain
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler].
+//
+// The format is a single format byte, followed by the number of counters (big-endian uint64),
+// the number of hash functions (big-endian uint32) and the counters, one byte each.
+func (f *CountingFilter) MarshalBinary() ([]byte, error) {
+	b := f.marshal(formatCounting, len(f.counters))
+	copy(b[headerSize:], f.counters)
+	return b, nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+// Returns [ErrInvalidData] if the data is malformed.
+func (f *CountingFilter) UnmarshalBinary(data []byte) error {
+	p, data, err := readHeader(data, formatCounting)
+	if err != nil {
+		return err
+	}
+
+	if uint64(len(data)) != p.m {
+		return ErrInvalidData
+	}
+
+	f.params = p
+	f.counters = append([]uint8(nil), data...)
+	return nil
+}
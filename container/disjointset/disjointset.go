@@ -0,0 +1,281 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// disjointset contains implementations of the union-find (disjoint set) data structure,
+// which tracks a partition of elements into non-overlapping groups.
+package disjointset
+
+import (
+	"github.com/MKuranowski/go-extra-lib/container/set"
+	"github.com/MKuranowski/go-extra-lib/iter"
+)
+
+type node[T comparable] struct {
+	parent T
+	rank   uint8
+	size   int
+}
+
+// DisjointSet is a partition of arbitrary elements into non-overlapping groups,
+// supporting fast merging of groups and checking whether two elements belong to the same group.
+//
+// Every group is identified by its representative - an arbitrary element of the group,
+// as returned by Find. The representative may change after calls to Union.
+//
+// The implementation uses path compression and union by rank, which gives
+// an amortized complexity of O(α(n)) (inverse Ackermann function) for Find and Union -
+// effectively constant.
+//
+// The zero value (`&DisjointSet[T]{}`) is an empty DisjointSet.
+//
+// See also [Dense] - a more efficient implementation if elements are
+// integers from a small range.
+type DisjointSet[T comparable] struct {
+	nodes map[T]*node[T]
+	sets  int
+}
+
+// Of returns a DisjointSet with every provided element in its own, single-element group.
+func Of[T comparable](xs ...T) *DisjointSet[T] {
+	d := &DisjointSet[T]{}
+	for _, x := range xs {
+		d.Add(x)
+	}
+	return d
+}
+
+// Add ensures that the provided element is in the DisjointSet.
+// New elements are placed in their own, single-element group.
+// Returns true if the element was added.
+func (d *DisjointSet[T]) Add(x T) bool {
+	if d.nodes == nil {
+		d.nodes = make(map[T]*node[T])
+	}
+
+	if _, has := d.nodes[x]; has {
+		return false
+	}
+
+	d.nodes[x] = &node[T]{parent: x, size: 1}
+	d.sets++
+	return true
+}
+
+// Has returns true if the provided element is in the DisjointSet.
+func (d *DisjointSet[T]) Has(x T) bool {
+	_, has := d.nodes[x]
+	return has
+}
+
+// find returns the root node of x, compressing the path to it.
+// x must be in the DisjointSet.
+func (d *DisjointSet[T]) find(x T) (T, *node[T]) {
+	// Find the root
+	root := x
+	rootNode := d.nodes[root]
+	for rootNode.parent != root {
+		root = rootNode.parent
+		rootNode = d.nodes[root]
+	}
+
+	// Compress the path
+	for x != root {
+		n := d.nodes[x]
+		x, n.parent = n.parent, root
+	}
+
+	return root, rootNode
+}
+
+// Find returns the representative of the group containing x.
+//
+// If x is not in the DisjointSet, returns x and false.
+func (d *DisjointSet[T]) Find(x T) (T, bool) {
+	if _, has := d.nodes[x]; !has {
+		return x, false
+	}
+	root, _ := d.find(x)
+	return root, true
+}
+
+// Union merges the groups of a and b. Elements which are not in the DisjointSet are added first.
+//
+// Returns true if a and b were in different groups before the call.
+func (d *DisjointSet[T]) Union(a, b T) bool {
+	d.Add(a)
+	d.Add(b)
+
+	a, aNode := d.find(a)
+	b, bNode := d.find(b)
+	if a == b {
+		return false
+	}
+
+	// Attach the tree with the smaller rank under the tree with the bigger rank
+	if aNode.rank < bNode.rank {
+		a, b = b, a
+		aNode, bNode = bNode, aNode
+	}
+	bNode.parent = a
+	aNode.size += bNode.size
+	if aNode.rank == bNode.rank {
+		aNode.rank++
+	}
+
+	d.sets--
+	return true
+}
+
+// Connected returns true if a and b belong to the same group.
+//
+// Elements which are not in the DisjointSet are only connected to themselves.
+func (d *DisjointSet[T]) Connected(a, b T) bool {
+	if a == b {
+		return true
+	}
+
+	aRoot, aOk := d.Find(a)
+	bRoot, bOk := d.Find(b)
+	return aOk && bOk && aRoot == bRoot
+}
+
+// Len returns the number of elements in the DisjointSet.
+func (d *DisjointSet[T]) Len() int { return len(d.nodes) }
+
+// SetCount returns the number of groups in the DisjointSet.
+func (d *DisjointSet[T]) SetCount() int { return d.sets }
+
+// SetSize returns the number of elements in the group containing x,
+// or 0 if x is not in the DisjointSet.
+func (d *DisjointSet[T]) SetSize(x T) int {
+	if _, has := d.nodes[x]; !has {
+		return 0
+	}
+	_, root := d.find(x)
+	return root.size
+}
+
+// Groups returns an iterator over all groups of the DisjointSet.
+//
+// Groups are generated in an arbitrary order. All groups are computed upfront,
+// so modifications made during iteration are not reflected in the iterator.
+//
+// Complexity: linear in terms of the number of elements.
+func (d *DisjointSet[T]) Groups() iter.Iterator[set.Set[T]] {
+	groups := make(map[T]set.Set[T], d.sets)
+	for x := range d.nodes {
+		root, rootNode := d.find(x)
+		g, ok := groups[root]
+		if !ok {
+			g = make(set.Set[T], rootNode.size)
+			groups[root] = g
+		}
+		g.Add(x)
+	}
+	return iter.OverMapValues(groups)
+}
+
+// Dense is a partition of integers in range [0, n) into non-overlapping groups,
+// backed by slices instead of maps.
+//
+// Dense supports the same operations as [DisjointSet], but Find and Union
+// panic if provided with elements outside of the [0, Len()) range.
+//
+// The zero value (`&Dense{}`) is an empty Dense DisjointSet; use [Dense.Grow]
+// or [NewDense] to add elements.
+type Dense struct {
+	parent []int
+	rank   []uint8
+	size   []int
+	sets   int
+}
+
+// NewDense returns a Dense DisjointSet with elements 0, 1, ..., n-1,
+// each in its own single-element group.
+func NewDense(n int) *Dense {
+	d := &Dense{}
+	d.Grow(n)
+	return d
+}
+
+// Grow adds n new elements to the DisjointSet, Len(), Len()+1, ..., Len()+n-1,
+// each in its own single-element group.
+func (d *Dense) Grow(n int) {
+	for i := 0; i < n; i++ {
+		d.parent = append(d.parent, len(d.parent))
+		d.rank = append(d.rank, 0)
+		d.size = append(d.size, 1)
+	}
+	d.sets += n
+}
+
+// Find returns the representative of the group containing x.
+func (d *Dense) Find(x int) int {
+	root := x
+	for d.parent[root] != root {
+		root = d.parent[root]
+	}
+
+	for x != root {
+		x, d.parent[x] = d.parent[x], root
+	}
+
+	return root
+}
+
+// Union merges the groups of a and b.
+//
+// Returns true if a and b were in different groups before the call.
+func (d *Dense) Union(a, b int) bool {
+	a, b = d.Find(a), d.Find(b)
+	if a == b {
+		return false
+	}
+
+	if d.rank[a] < d.rank[b] {
+		a, b = b, a
+	}
+	d.parent[b] = a
+	d.size[a] += d.size[b]
+	if d.rank[a] == d.rank[b] {
+		d.rank[a]++
+	}
+
+	d.sets--
+	return true
+}
+
+// Connected returns true if a and b belong to the same group.
+func (d *Dense) Connected(a, b int) bool { return d.Find(a) == d.Find(b) }
+
+// Len returns the number of elements in the DisjointSet.
+func (d *Dense) Len() int { return len(d.parent) }
+
+// SetCount returns the number of groups in the DisjointSet.
+func (d *Dense) SetCount() int { return d.sets }
+
+// SetSize returns the number of elements in the group containing x.
+func (d *Dense) SetSize(x int) int { return d.size[d.Find(x)] }
+
+// Groups returns an iterator over all groups of the DisjointSet.
+// Every group is a slice of its elements, in ascending order.
+//
+// Groups are ordered by their smallest element. All groups are computed upfront,
+// so modifications made during iteration are not reflected in the iterator.
+//
+// Complexity: linear in terms of the number of elements, both in time and memory.
+func (d *Dense) Groups() iter.Iterator[[]int] {
+	groups := make([][]int, 0, d.sets)
+	groupOfRoot := make(map[int]int, d.sets)
+	for x := range d.parent {
+		root := d.Find(x)
+		i, ok := groupOfRoot[root]
+		if !ok {
+			i = len(groups)
+			groupOfRoot[root] = i
+			groups = append(groups, make([]int, 0, d.size[root]))
+		}
+		groups[i] = append(groups[i], x)
+	}
+	return iter.OverSlice(groups)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package disjointset_test
+
+import (
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/container/disjointset"
+	"github.com/MKuranowski/go-extra-lib/container/set"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+	"golang.org/x/exp/slices"
+)
+
+// DisjointSet
+
+func TestDisjointSetUnionFind(t *testing.T) {
+	d := disjointset.Of("a", "b", "c", "d", "e")
+
+	check.EqMsg(t, d.Len(), 5, "d.Len(): initial")
+	check.EqMsg(t, d.SetCount(), 5, "d.SetCount(): initial")
+	check.FalseMsg(t, d.Connected("a", "b"), "d.Connected(a, b): initial")
+
+	check.TrueMsg(t, d.Union("a", "b"), "d.Union(a, b)")
+	check.TrueMsg(t, d.Union("c", "d"), "d.Union(c, d)")
+	check.TrueMsg(t, d.Union("b", "d"), "d.Union(b, d)")
+	check.FalseMsg(t, d.Union("a", "c"), "d.Union(a, c)")
+
+	check.EqMsg(t, d.SetCount(), 2, "d.SetCount(): after union")
+	check.TrueMsg(t, d.Connected("a", "c"), "d.Connected(a, c)")
+	check.FalseMsg(t, d.Connected("a", "e"), "d.Connected(a, e)")
+	check.EqMsg(t, d.SetSize("d"), 4, "d.SetSize(d)")
+	check.EqMsg(t, d.SetSize("e"), 1, "d.SetSize(e)")
+
+	rootA, _ := d.Find("a")
+	rootD, _ := d.Find("d")
+	check.EqMsg(t, rootA, rootD, "d.Find(a) == d.Find(d)")
+}
+
+func TestDisjointSetMissingElements(t *testing.T) {
+	d := &disjointset.DisjointSet[int]{}
+
+	root, ok := d.Find(1)
+	check.FalseMsg(t, ok, "d.Find(1): ok")
+	check.EqMsg(t, root, 1, "d.Find(1)")
+	check.EqMsg(t, d.SetSize(1), 0, "d.SetSize(1)")
+	check.TrueMsg(t, d.Connected(1, 1), "d.Connected(1, 1)")
+	check.FalseMsg(t, d.Connected(1, 2), "d.Connected(1, 2)")
+
+	d.Union(1, 2)
+	check.EqMsg(t, d.Len(), 2, "d.Len(): after union")
+	check.EqMsg(t, d.SetCount(), 1, "d.SetCount(): after union")
+	check.TrueMsg(t, d.Connected(1, 2), "d.Connected(1, 2): after union")
+}
+
+func TestDisjointSetGroups(t *testing.T) {
+	d := disjointset.Of(1, 2, 3, 4, 5, 6)
+	d.Union(1, 3)
+	d.Union(3, 5)
+	d.Union(2, 4)
+
+	groups := iter.IntoSlice(d.Groups())
+	slices.SortFunc(groups, func(a, b set.Set[int]) bool { return a.Len() > b.Len() })
+
+	check.EqMsg(t, len(groups), 3, "len(groups)")
+	check.TrueMsg(t, groups[0].Equal(set.Set[int]{1: {}, 3: {}, 5: {}}), "groups[0]")
+	check.TrueMsg(t, groups[1].Equal(set.Set[int]{2: {}, 4: {}}), "groups[1]")
+	check.TrueMsg(t, groups[2].Equal(set.Set[int]{6: {}}), "groups[2]")
+}
+
+// Dense
+
+func TestDenseUnionFind(t *testing.T) {
+	d := disjointset.NewDense(5)
+
+	check.EqMsg(t, d.Len(), 5, "d.Len(): initial")
+	check.EqMsg(t, d.SetCount(), 5, "d.SetCount(): initial")
+
+	check.TrueMsg(t, d.Union(0, 1), "d.Union(0, 1)")
+	check.TrueMsg(t, d.Union(2, 3), "d.Union(2, 3)")
+	check.TrueMsg(t, d.Union(1, 3), "d.Union(1, 3)")
+	check.FalseMsg(t, d.Union(0, 2), "d.Union(0, 2)")
+
+	check.EqMsg(t, d.SetCount(), 2, "d.SetCount(): after union")
+	check.TrueMsg(t, d.Connected(0, 2), "d.Connected(0, 2)")
+	check.FalseMsg(t, d.Connected(0, 4), "d.Connected(0, 4)")
+	check.EqMsg(t, d.SetSize(3), 4, "d.SetSize(3)")
+	check.EqMsg(t, d.SetSize(4), 1, "d.SetSize(4)")
+
+	d.Grow(2)
+	check.EqMsg(t, d.Len(), 7, "d.Len(): after grow")
+	check.EqMsg(t, d.SetCount(), 4, "d.SetCount(): after grow")
+	check.TrueMsg(t, d.Union(4, 6), "d.Union(4, 6)")
+}
+
+func TestDenseGroups(t *testing.T) {
+	d := disjointset.NewDense(6)
+	d.Union(1, 3)
+	d.Union(3, 5)
+	d.Union(0, 4)
+
+	groups := iter.IntoSlice(d.Groups())
+
+	check.EqMsg(t, len(groups), 3, "len(groups)")
+	check.DeepEqMsg(t, groups[0], []int{0, 4}, "groups[0]")
+	check.DeepEqMsg(t, groups[1], []int{1, 3, 5}, "groups[1]")
+	check.DeepEqMsg(t, groups[2], []int{2}, "groups[2]")
+}
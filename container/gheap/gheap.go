@@ -0,0 +1,132 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// gheap is a generic version of the [container/heap] package,
+// implementing a binary min-heap (priority queue).
+package gheap
+
+import "golang.org/x/exp/constraints"
+
+// Heap is a binary min-heap of elements of type T - an element for which Less
+// returns true when compared against all other elements is always at the top.
+//
+// `&Heap[T]{Less: ...}` is ready to use. See also helper [New] and [NewFunc] functions.
+//
+// To get a max-heap, simply reverse the Less function.
+type Heap[T any] struct {
+	// Less compares two elements of the heap, and must implement a [strict weak ordering].
+	//
+	// Less must not be changed if the heap is not empty.
+	//
+	// [strict weak ordering]: https://en.wikipedia.org/wiki/Weak_ordering#Strict_weak_orderings
+	Less func(a, b T) bool
+
+	data []T
+}
+
+func less[T constraints.Ordered](a, b T) bool { return a < b }
+
+// New returns a min-heap of the provided elements, ordered with the `<` operator.
+//
+// Complexity: linear in terms of len(xs).
+func New[T constraints.Ordered](xs ...T) *Heap[T] {
+	return NewFunc(less[T], xs...)
+}
+
+// NewFunc returns a min-heap of the provided elements, ordered with the provided Less function.
+// The xs slice is used by the heap and must not be modified afterwards.
+//
+// Complexity: linear in terms of len(xs).
+func NewFunc[T any](less func(a, b T) bool, xs ...T) *Heap[T] {
+	h := &Heap[T]{Less: less, data: xs}
+	for i := len(xs)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+	return h
+}
+
+// Len returns the number of elements in the heap.
+func (h *Heap[T]) Len() int { return len(h.data) }
+
+// Push adds an element to the heap.
+//
+// Complexity: O(log n)
+func (h *Heap[T]) Push(x T) {
+	h.data = append(h.data, x)
+	h.up(len(h.data) - 1)
+}
+
+// Pop removes and returns the minimum element of the heap.
+// Panics if the heap is empty.
+//
+// Complexity: O(log n)
+func (h *Heap[T]) Pop() T {
+	var zero T
+	n := len(h.data) - 1
+	top := h.data[0]
+
+	h.data[0] = h.data[n]
+	h.data[n] = zero // avoid memory leaks
+	h.data = h.data[:n]
+	if n > 0 {
+		h.down(0)
+	}
+
+	return top
+}
+
+// Peek returns the minimum element of the heap, without removing it.
+// Panics if the heap is empty.
+//
+// Complexity: constant
+func (h *Heap[T]) Peek() T { return h.data[0] }
+
+// PushPop adds x to the heap, and then removes and returns the minimum element.
+// This is more efficient than a Push followed by a Pop.
+//
+// Complexity: O(log n)
+func (h *Heap[T]) PushPop(x T) T {
+	if len(h.data) > 0 && h.Less(h.data[0], x) {
+		x, h.data[0] = h.data[0], x
+		h.down(0)
+	}
+	return x
+}
+
+// Clear removes all elements from the heap.
+func (h *Heap[T]) Clear() {
+	var zero T
+	for i := range h.data {
+		h.data[i] = zero
+	}
+	h.data = h.data[:0]
+}
+
+func (h *Heap[T]) up(j int) {
+	for j > 0 {
+		i := (j - 1) / 2 // parent
+		if !h.Less(h.data[j], h.data[i]) {
+			break
+		}
+		h.data[i], h.data[j] = h.data[j], h.data[i]
+		j = i
+	}
+}
+
+func (h *Heap[T]) down(i int) {
+	n := len(h.data)
+	for {
+		j := 2*i + 1 // left child
+		if j >= n {
+			break
+		}
+		if right := j + 1; right < n && h.Less(h.data[right], h.data[j]) {
+			j = right
+		}
+		if !h.Less(h.data[j], h.data[i]) {
+			break
+		}
+		h.data[i], h.data[j] = h.data[j], h.data[i]
+		i = j
+	}
+}
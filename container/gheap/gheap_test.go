@@ -0,0 +1,60 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package gheap_test
+
+import (
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/container/gheap"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func drain[T any](h *gheap.Heap[T]) []T {
+	r := make([]T, 0, h.Len())
+	for h.Len() > 0 {
+		r = append(r, h.Pop())
+	}
+	return r
+}
+
+func TestHeapPushPop(t *testing.T) {
+	h := &gheap.Heap[int]{Less: func(a, b int) bool { return a < b }}
+
+	for _, x := range []int{5, 2, 8, 1, 9, 3} {
+		h.Push(x)
+	}
+
+	check.EqMsg(t, h.Len(), 6, "h.Len()")
+	check.EqMsg(t, h.Peek(), 1, "h.Peek()")
+	check.DeepEqMsg(t, drain(h), []int{1, 2, 3, 5, 8, 9}, "popped elements")
+}
+
+func TestHeapNew(t *testing.T) {
+	h := gheap.New(5, 2, 8, 1, 9, 3, 2)
+	check.DeepEqMsg(t, drain(h), []int{1, 2, 2, 3, 5, 8, 9}, "popped elements")
+}
+
+func TestHeapNewFunc(t *testing.T) {
+	h := gheap.NewFunc(func(a, b string) bool { return len(a) > len(b) }, "a", "ccc", "bb", "dddd")
+	check.DeepEqMsg(t, drain(h), []string{"dddd", "ccc", "bb", "a"}, "popped elements")
+}
+
+func TestHeapPushPopCombined(t *testing.T) {
+	h := gheap.New(3, 5, 7)
+
+	check.EqMsg(t, h.PushPop(1), 1, "h.PushPop(1)")
+	check.EqMsg(t, h.PushPop(4), 3, "h.PushPop(4)")
+	check.DeepEqMsg(t, drain(h), []int{4, 5, 7}, "popped elements")
+
+	check.EqMsg(t, h.PushPop(2), 2, "h.PushPop(2): empty heap")
+}
+
+func TestHeapClear(t *testing.T) {
+	h := gheap.New(1, 2, 3)
+	h.Clear()
+	check.EqMsg(t, h.Len(), 0, "h.Len(): after clear")
+
+	h.Push(42)
+	check.EqMsg(t, h.Pop(), 42, "h.Pop(): after clear")
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// interval contains containers for half-open intervals of ordered values:
+// an interval tree, for fast overlap queries, and a normalized interval set.
+//
+// Containers are generic over any type with a [strict weak ordering],
+// provided with a `Less` function. For types satisfying [constraints.Ordered],
+// helper constructors using the `<` operator are available. For [time.Time],
+// use `func(a, b time.Time) bool { return a.Before(b) }`.
+//
+// [strict weak ordering]: https://en.wikipedia.org/wiki/Weak_ordering#Strict_weak_orderings
+package interval
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Interval is a half-open range of values [Lo, Hi) - Lo is included in the interval,
+// while Hi is not.
+//
+// An interval with Lo >= Hi is empty.
+type Interval[T any] struct {
+	Lo T
+	Hi T
+}
+
+// Of returns an interval [lo, hi).
+func Of[T any](lo, hi T) Interval[T] { return Interval[T]{Lo: lo, Hi: hi} }
+
+func (i Interval[T]) String() string { return fmt.Sprintf("[%v, %v)", i.Lo, i.Hi) }
+
+func less[T constraints.Ordered](a, b T) bool { return a < b }
+
+func isEmpty[T any](i Interval[T], less func(a, b T) bool) bool { return !less(i.Lo, i.Hi) }
+
+func equal[T any](a, b T, less func(a, b T) bool) bool { return !less(a, b) && !less(b, a) }
+
+func maxOf[T any](a, b T, less func(a, b T) bool) T {
+	if less(a, b) {
+		return b
+	}
+	return a
+}
+
+func minOf[T any](a, b T, less func(a, b T) bool) T {
+	if less(b, a) {
+		return b
+	}
+	return a
+}
+
+// compareIntervals orders intervals by Lo, and then by Hi.
+func compareIntervals[T any](a, b Interval[T], less func(a, b T) bool) int {
+	switch {
+	case less(a.Lo, b.Lo):
+		return -1
+	case less(b.Lo, a.Lo):
+		return 1
+	case less(a.Hi, b.Hi):
+		return -1
+	case less(b.Hi, a.Hi):
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package interval
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"golang.org/x/exp/constraints"
+)
+
+// Set is a set of values, represented as a normalized list of intervals:
+// sorted, non-empty, and neither overlapping nor adjacent. Adding [1, 3) and [3, 5)
+// results in a single [1, 5) interval.
+//
+// `&Set[T]{Less: ...}` is ready to use. See also helper [NewSet] and [NewSetFunc] functions.
+//
+// Given operation complexity is in terms of n - the number of intervals in the set,
+// and m - the number of intervals in the other set.
+type Set[T any] struct {
+	// Less compares two values, and must implement a [strict weak ordering].
+	//
+	// Less must not be changed if the set is not empty.
+	//
+	// [strict weak ordering]: https://en.wikipedia.org/wiki/Weak_ordering#Strict_weak_orderings
+	Less func(a, b T) bool
+
+	intervals []Interval[T]
+}
+
+// NewSet returns a set of values ordered with the `<` operator, containing the provided intervals.
+func NewSet[T constraints.Ordered](intervals ...Interval[T]) *Set[T] {
+	return NewSetFunc(less[T], intervals...)
+}
+
+// NewSetFunc returns a set of values ordered with the provided less function,
+// containing the provided intervals.
+func NewSetFunc[T any](less func(a, b T) bool, intervals ...Interval[T]) *Set[T] {
+	s := &Set[T]{Less: less}
+	for _, i := range intervals {
+		s.Add(i)
+	}
+	return s
+}
+
+// Len returns the number of disjoint intervals in the set.
+func (s *Set[T]) Len() int { return len(s.intervals) }
+
+// Clear removes all intervals from the set.
+func (s *Set[T]) Clear() { s.intervals = nil }
+
+// Clone returns a copy of the set.
+func (s *Set[T]) Clone() *Set[T] {
+	return &Set[T]{Less: s.Less, intervals: append([]Interval[T](nil), s.intervals...)}
+}
+
+// Equal returns true if both sets contain exactly the same values.
+//
+// Complexity: O(n)
+func (s1 *Set[T]) Equal(s2 *Set[T]) bool {
+	if len(s1.intervals) != len(s2.intervals) {
+		return false
+	}
+	for idx, i := range s1.intervals {
+		if compareIntervals(i, s2.intervals[idx], s1.Less) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// search returns the index of the first interval for which f returns true.
+func (s *Set[T]) search(f func(i Interval[T]) bool) int {
+	return sort.Search(len(s.intervals), func(idx int) bool { return f(s.intervals[idx]) })
+}
+
+// splice replaces s.intervals[start:end] with the provided intervals.
+func (s *Set[T]) splice(start, end int, with ...Interval[T]) {
+	tail := len(s.intervals) - end
+	newLen := start + len(with) + tail
+
+	if newLen > len(s.intervals) {
+		s.intervals = append(s.intervals, make([]Interval[T], newLen-len(s.intervals))...)
+	}
+	copy(s.intervals[start+len(with):], s.intervals[end:end+tail])
+	copy(s.intervals[start:], with)
+
+	// Zero-out the tail, so that no references are kept
+	var zero Interval[T]
+	for idx := newLen; idx < len(s.intervals); idx++ {
+		s.intervals[idx] = zero
+	}
+	s.intervals = s.intervals[:newLen]
+}
+
+// Add ensures all values from the provided interval are in the set.
+// Empty intervals are ignored.
+//
+// Complexity: O(log n + k), where k is the number of intervals merged with the provided one.
+func (s *Set[T]) Add(i Interval[T]) {
+	if isEmpty(i, s.Less) {
+		return
+	}
+
+	// Find intervals overlapping or adjacent to i
+	start := s.search(func(x Interval[T]) bool { return !s.Less(x.Hi, i.Lo) })
+	end := s.search(func(x Interval[T]) bool { return s.Less(i.Hi, x.Lo) })
+
+	if start < end {
+		i.Lo = minOf(i.Lo, s.intervals[start].Lo, s.Less)
+		i.Hi = maxOf(i.Hi, s.intervals[end-1].Hi, s.Less)
+	}
+	s.splice(start, end, i)
+}
+
+// Remove ensures no values from the provided interval are in the set.
+//
+// Complexity: O(log n + k), where k is the number of intervals overlapping with the provided one.
+func (s *Set[T]) Remove(i Interval[T]) {
+	if isEmpty(i, s.Less) {
+		return
+	}
+
+	// Find intervals overlapping with i
+	start := s.search(func(x Interval[T]) bool { return s.Less(i.Lo, x.Hi) })
+	end := s.search(func(x Interval[T]) bool { return !s.Less(x.Lo, i.Hi) })
+	if start >= end {
+		return
+	}
+
+	remaining := make([]Interval[T], 0, 2)
+	if first := s.intervals[start]; s.Less(first.Lo, i.Lo) {
+		remaining = append(remaining, Interval[T]{Lo: first.Lo, Hi: i.Lo})
+	}
+	if last := s.intervals[end-1]; s.Less(i.Hi, last.Hi) {
+		remaining = append(remaining, Interval[T]{Lo: i.Hi, Hi: last.Hi})
+	}
+	s.splice(start, end, remaining...)
+}
+
+// Contains returns true if the provided value is in the set.
+//
+// Complexity: O(log n)
+func (s *Set[T]) Contains(x T) bool {
+	idx := s.search(func(i Interval[T]) bool { return s.Less(x, i.Hi) })
+	return idx < len(s.intervals) && !s.Less(x, s.intervals[idx].Lo)
+}
+
+// Covers returns true if all values from the provided interval are in the set.
+// Empty intervals are always covered.
+//
+// Complexity: O(log n)
+func (s *Set[T]) Covers(i Interval[T]) bool {
+	if isEmpty(i, s.Less) {
+		return true
+	}
+	idx := s.search(func(x Interval[T]) bool { return s.Less(i.Lo, x.Hi) })
+	return idx < len(s.intervals) &&
+		!s.Less(i.Lo, s.intervals[idx].Lo) &&
+		!s.Less(s.intervals[idx].Hi, i.Hi)
+}
+
+// Overlaps returns true if any value from the provided interval is in the set.
+//
+// Complexity: O(log n)
+func (s *Set[T]) Overlaps(i Interval[T]) bool {
+	if isEmpty(i, s.Less) {
+		return false
+	}
+	idx := s.search(func(x Interval[T]) bool { return s.Less(i.Lo, x.Hi) })
+	return idx < len(s.intervals) && s.Less(s.intervals[idx].Lo, i.Hi)
+}
+
+// appendMerged appends i to a normalized list of intervals, merging it with
+// the last interval if they overlap or are adjacent. i must not start before the last interval.
+func appendMerged[T any](intervals []Interval[T], i Interval[T], less func(a, b T) bool) []Interval[T] {
+	if last := len(intervals) - 1; last >= 0 && !less(intervals[last].Hi, i.Lo) {
+		intervals[last].Hi = maxOf(intervals[last].Hi, i.Hi, less)
+		return intervals
+	}
+	return append(intervals, i)
+}
+
+// Union ensures all values from s2 are in s1.
+//
+// Complexity: O(n + m)
+func (s1 *Set[T]) Union(s2 *Set[T]) {
+	result := make([]Interval[T], 0, len(s1.intervals)+len(s2.intervals))
+	a, b := s1.intervals, s2.intervals
+
+	for len(a) > 0 || len(b) > 0 {
+		if len(b) == 0 || (len(a) > 0 && !s1.Less(b[0].Lo, a[0].Lo)) {
+			result = appendMerged(result, a[0], s1.Less)
+			a = a[1:]
+		} else {
+			result = appendMerged(result, b[0], s1.Less)
+			b = b[1:]
+		}
+	}
+
+	s1.intervals = result
+}
+
+// Intersection ensures only values which are in both s1 and s2 are in s1.
+//
+// Complexity: O(n + m)
+func (s1 *Set[T]) Intersection(s2 *Set[T]) {
+	result := make([]Interval[T], 0, len(s1.intervals))
+	a, b := s1.intervals, s2.intervals
+
+	for len(a) > 0 && len(b) > 0 {
+		i := Interval[T]{Lo: maxOf(a[0].Lo, b[0].Lo, s1.Less), Hi: minOf(a[0].Hi, b[0].Hi, s1.Less)}
+		if !isEmpty(i, s1.Less) {
+			result = append(result, i)
+		}
+
+		// Drop the interval which ends first
+		if s1.Less(a[0].Hi, b[0].Hi) {
+			a = a[1:]
+		} else {
+			b = b[1:]
+		}
+	}
+
+	s1.intervals = result
+}
+
+// Difference ensures all values from s2 are not in s1.
+//
+// Complexity: O(n + m)
+func (s1 *Set[T]) Difference(s2 *Set[T]) {
+	result := make([]Interval[T], 0, len(s1.intervals))
+	b := s2.intervals
+
+	for _, i := range s1.intervals {
+		// Skip intervals from s2 ending before i
+		for len(b) > 0 && !s1.Less(i.Lo, b[0].Hi) {
+			b = b[1:]
+		}
+
+		// Cut out all intervals from s2 starting before i ends
+		for j := 0; j < len(b) && s1.Less(b[j].Lo, i.Hi); j++ {
+			if s1.Less(i.Lo, b[j].Lo) {
+				result = append(result, Interval[T]{Lo: i.Lo, Hi: b[j].Lo})
+			}
+			i.Lo = maxOf(i.Lo, b[j].Hi, s1.Less)
+		}
+
+		if !isEmpty(i, s1.Less) {
+			result = append(result, i)
+		}
+	}
+
+	s1.intervals = result
+}
+
+// Intervals returns a copy of the normalized list of intervals in the set.
+func (s *Set[T]) Intervals() []Interval[T] {
+	return append([]Interval[T](nil), s.intervals...)
+}
+
+// Iter returns an iterator over the normalized list of intervals in the set.
+// The set must not be modified during iteration.
+func (s *Set[T]) Iter() iter.Iterator[Interval[T]] { return iter.OverSlice(s.intervals) }
+
+func (s *Set[T]) String() string {
+	b := strings.Builder{}
+	b.WriteByte('{')
+	for idx, i := range s.intervals {
+		if idx > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(i.String())
+	}
+	b.WriteByte('}')
+	return b.String()
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package interval_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/MKuranowski/go-extra-lib/container/interval"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func TestSetAdd(t *testing.T) {
+	s := interval.NewSet[int]()
+
+	s.Add(interval.Of(1, 3))
+	s.Add(interval.Of(5, 7))
+	check.EqMsg(t, s.String(), "{[1, 3), [5, 7)}", "s: after adding disjoint")
+
+	s.Add(interval.Of(3, 4))
+	check.EqMsg(t, s.String(), "{[1, 4), [5, 7)}", "s: after adding adjacent")
+
+	s.Add(interval.Of(6, 10))
+	check.EqMsg(t, s.String(), "{[1, 4), [5, 10)}", "s: after adding overlapping")
+
+	s.Add(interval.Of(0, 20))
+	check.EqMsg(t, s.String(), "{[0, 20)}", "s: after adding covering")
+
+	s.Add(interval.Of(30, 25))
+	check.EqMsg(t, s.String(), "{[0, 20)}", "s: after adding empty")
+
+	s.Add(interval.Of(-5, -2))
+	check.EqMsg(t, s.String(), "{[-5, -2), [0, 20)}", "s: after adding in front")
+	check.EqMsg(t, s.Len(), 2, "s.Len()")
+}
+
+func TestSetRemove(t *testing.T) {
+	s := interval.NewSet(interval.Of(0, 10), interval.Of(20, 30))
+
+	s.Remove(interval.Of(3, 5))
+	check.EqMsg(t, s.String(), "{[0, 3), [5, 10), [20, 30)}", "s: after removing inside")
+
+	s.Remove(interval.Of(8, 25))
+	check.EqMsg(t, s.String(), "{[0, 3), [5, 8), [25, 30)}", "s: after removing across")
+
+	s.Remove(interval.Of(10, 20))
+	check.EqMsg(t, s.String(), "{[0, 3), [5, 8), [25, 30)}", "s: after removing gap")
+
+	s.Remove(interval.Of(0, 8))
+	check.EqMsg(t, s.String(), "{[25, 30)}", "s: after removing exact")
+}
+
+func TestSetQueries(t *testing.T) {
+	s := interval.NewSet(interval.Of(0, 10), interval.Of(20, 30))
+
+	check.TrueMsg(t, s.Contains(0), "s.Contains(0)")
+	check.TrueMsg(t, s.Contains(9), "s.Contains(9)")
+	check.FalseMsg(t, s.Contains(10), "s.Contains(10)")
+	check.FalseMsg(t, s.Contains(-1), "s.Contains(-1)")
+	check.FalseMsg(t, s.Contains(30), "s.Contains(30)")
+
+	check.TrueMsg(t, s.Covers(interval.Of(2, 8)), "s.Covers([2, 8))")
+	check.TrueMsg(t, s.Covers(interval.Of(20, 30)), "s.Covers([20, 30))")
+	check.FalseMsg(t, s.Covers(interval.Of(5, 25)), "s.Covers([5, 25))")
+
+	check.TrueMsg(t, s.Overlaps(interval.Of(5, 25)), "s.Overlaps([5, 25))")
+	check.TrueMsg(t, s.Overlaps(interval.Of(29, 40)), "s.Overlaps([29, 40))")
+	check.FalseMsg(t, s.Overlaps(interval.Of(10, 20)), "s.Overlaps([10, 20))")
+	check.FalseMsg(t, s.Overlaps(interval.Of(5, 5)), "s.Overlaps([5, 5))")
+}
+
+func TestSetAlgebra(t *testing.T) {
+	a := func() *interval.Set[int] { return interval.NewSet(interval.Of(0, 5), interval.Of(10, 15)) }
+	b := interval.NewSet(interval.Of(3, 10), interval.Of(12, 13), interval.Of(20, 25))
+
+	s := a()
+	s.Union(b)
+	check.EqMsg(t, s.String(), "{[0, 15), [20, 25)}", "a ∪ b")
+
+	s = a()
+	s.Intersection(b)
+	check.EqMsg(t, s.String(), "{[3, 5), [12, 13)}", "a ∩ b")
+
+	s = a()
+	s.Difference(b)
+	check.EqMsg(t, s.String(), "{[0, 3), [10, 12), [13, 15)}", "a - b")
+}
+
+func TestSetTime(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2023, time.May, d, 0, 0, 0, 0, time.UTC) }
+
+	s := interval.NewSetFunc(
+		func(a, b time.Time) bool { return a.Before(b) },
+		interval.Of(day(1), day(5)),
+		interval.Of(day(5), day(10)),
+	)
+	check.EqMsg(t, s.Len(), 1, "s.Len()")
+	check.TrueMsg(t, s.Contains(day(7)), "s.Contains(May 7)")
+	check.FalseMsg(t, s.Contains(day(10)), "s.Contains(May 10)")
+}
+
+func TestSetRandomized(t *testing.T) {
+	const universe = 64
+	r := rand.New(rand.NewSource(42))
+
+	randomSet := func() (*interval.Set[int], [universe]bool) {
+		s := interval.NewSet[int]()
+		var bits [universe]bool
+		for i := 0; i < 10; i++ {
+			lo := r.Intn(universe)
+			hi := lo + r.Intn(universe-lo+1)
+			if r.Intn(3) == 0 {
+				s.Remove(interval.Of(lo, hi))
+				for x := lo; x < hi; x++ {
+					bits[x] = false
+				}
+			} else {
+				s.Add(interval.Of(lo, hi))
+				for x := lo; x < hi; x++ {
+					bits[x] = true
+				}
+			}
+		}
+		return s, bits
+	}
+
+	checkSame := func(s *interval.Set[int], bits [universe]bool, msg string) {
+		t.Helper()
+		for x, expected := range bits {
+			if s.Contains(x) != expected {
+				t.Errorf("%s: s.Contains(%d) = %v, expected %v", msg, x, !expected, expected)
+			}
+		}
+
+		// Ensure the list is normalized
+		ivs := s.Intervals()
+		for i := 1; i < len(ivs); i++ {
+			if ivs[i-1].Hi >= ivs[i].Lo {
+				t.Errorf("%s: intervals %v and %v are not separated", msg, ivs[i-1], ivs[i])
+			}
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		a, aBits := randomSet()
+		b, bBits := randomSet()
+		checkSame(a, aBits, "a")
+
+		var union, intersection, difference [universe]bool
+		for x := range aBits {
+			union[x] = aBits[x] || bBits[x]
+			intersection[x] = aBits[x] && bBits[x]
+			difference[x] = aBits[x] && !bBits[x]
+		}
+
+		s := a.Clone()
+		s.Union(b)
+		checkSame(s, union, "a ∪ b")
+
+		s = a.Clone()
+		s.Intersection(b)
+		checkSame(s, intersection, "a ∩ b")
+
+		s = a.Clone()
+		s.Difference(b)
+		checkSame(s, difference, "a - b")
+
+		check.TrueMsg(t, a.Equal(a.Clone()), "a.Equal(a.Clone())")
+	}
+}
@@ -0,0 +1,335 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package interval
+
+import (
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"golang.org/x/exp/constraints"
+)
+
+type treeNode[T, V any] struct {
+	interval    Interval[T]
+	value       V
+	maxHi       T // maximum Hi in the subtree
+	height      int
+	left, right *treeNode[T, V]
+}
+
+func height[T, V any](n *treeNode[T, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// Tree is a map from intervals to values of type V, supporting efficient queries
+// for intervals overlapping a range or containing a point.
+//
+// The implementation is an AVL tree, ordered by (Lo, Hi), with every node additionally
+// storing the maximum Hi of its subtree.
+//
+// `&Tree[T, V]{Less: ...}` is ready to use. See also helper [NewTree] and [NewTreeFunc] functions.
+//
+// Given operation complexity is in terms of n - the number of intervals in the tree.
+type Tree[T, V any] struct {
+	// Less compares two values, and must implement a [strict weak ordering].
+	//
+	// Less must not be changed if the tree is not empty.
+	//
+	// [strict weak ordering]: https://en.wikipedia.org/wiki/Weak_ordering#Strict_weak_orderings
+	Less func(a, b T) bool
+
+	root *treeNode[T, V]
+	size int
+}
+
+// NewTree returns an empty interval tree of values ordered with the `<` operator.
+func NewTree[T constraints.Ordered, V any]() *Tree[T, V] {
+	return &Tree[T, V]{Less: less[T]}
+}
+
+// NewTreeFunc returns an empty interval tree of values ordered with the provided less function.
+func NewTreeFunc[T, V any](less func(a, b T) bool) *Tree[T, V] {
+	return &Tree[T, V]{Less: less}
+}
+
+// Len returns the number of intervals in the tree.
+func (t *Tree[T, V]) Len() int { return t.size }
+
+func (t *Tree[T, V]) update(n *treeNode[T, V]) {
+	n.height = 1 + height(n.left)
+	if h := height(n.right); h >= n.height {
+		n.height = h + 1
+	}
+
+	n.maxHi = n.interval.Hi
+	if n.left != nil {
+		n.maxHi = maxOf(n.maxHi, n.left.maxHi, t.Less)
+	}
+	if n.right != nil {
+		n.maxHi = maxOf(n.maxHi, n.right.maxHi, t.Less)
+	}
+}
+
+func (t *Tree[T, V]) rotateLeft(n *treeNode[T, V]) *treeNode[T, V] {
+	r := n.right
+	n.right, r.left = r.left, n
+	t.update(n)
+	t.update(r)
+	return r
+}
+
+func (t *Tree[T, V]) rotateRight(n *treeNode[T, V]) *treeNode[T, V] {
+	l := n.left
+	n.left, l.right = l.right, n
+	t.update(n)
+	t.update(l)
+	return l
+}
+
+func (t *Tree[T, V]) balance(n *treeNode[T, V]) *treeNode[T, V] {
+	t.update(n)
+	switch factor := height(n.left) - height(n.right); {
+	case factor > 1:
+		if height(n.left.left) < height(n.left.right) {
+			n.left = t.rotateLeft(n.left)
+		}
+		return t.rotateRight(n)
+
+	case factor < -1:
+		if height(n.right.right) < height(n.right.left) {
+			n.right = t.rotateRight(n.right)
+		}
+		return t.rotateLeft(n)
+
+	default:
+		return n
+	}
+}
+
+func (t *Tree[T, V]) insert(n *treeNode[T, V], i Interval[T], v V) (*treeNode[T, V], bool) {
+	if n == nil {
+		return &treeNode[T, V]{interval: i, value: v, maxHi: i.Hi, height: 1}, true
+	}
+
+	var added bool
+	switch compareIntervals(i, n.interval, t.Less) {
+	case -1:
+		n.left, added = t.insert(n.left, i, v)
+	case 1:
+		n.right, added = t.insert(n.right, i, v)
+	default:
+		n.value = v
+		return n, false
+	}
+	return t.balance(n), added
+}
+
+// Insert associates a value with an interval. If the same interval was already in the tree,
+// its value is replaced. Returns true if the interval was added.
+//
+// Complexity: O(log n)
+func (t *Tree[T, V]) Insert(i Interval[T], value V) bool {
+	var added bool
+	t.root, added = t.insert(t.root, i, value)
+	if added {
+		t.size++
+	}
+	return added
+}
+
+func (t *Tree[T, V]) removeMin(n *treeNode[T, V]) (root, min *treeNode[T, V]) {
+	if n.left == nil {
+		return n.right, n
+	}
+	n.left, min = t.removeMin(n.left)
+	return t.balance(n), min
+}
+
+func (t *Tree[T, V]) delete(n *treeNode[T, V], i Interval[T]) (*treeNode[T, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	var deleted bool
+	switch compareIntervals(i, n.interval, t.Less) {
+	case -1:
+		n.left, deleted = t.delete(n.left, i)
+	case 1:
+		n.right, deleted = t.delete(n.right, i)
+	default:
+		if n.left == nil {
+			return n.right, true
+		} else if n.right == nil {
+			return n.left, true
+		}
+
+		var successor *treeNode[T, V]
+		n.right, successor = t.removeMin(n.right)
+		successor.left, successor.right = n.left, n.right
+		n, deleted = successor, true
+	}
+	return t.balance(n), deleted
+}
+
+// Delete ensures the provided interval is not in the tree. Returns true if the interval was removed.
+//
+// Complexity: O(log n)
+func (t *Tree[T, V]) Delete(i Interval[T]) bool {
+	var deleted bool
+	t.root, deleted = t.delete(t.root, i)
+	if deleted {
+		t.size--
+	}
+	return deleted
+}
+
+// Get returns the value associated with exactly the provided interval.
+//
+// Complexity: O(log n)
+func (t *Tree[T, V]) Get(i Interval[T]) (value V, ok bool) {
+	n := t.root
+	for n != nil {
+		switch compareIntervals(i, n.interval, t.Less) {
+		case -1:
+			n = n.left
+		case 1:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	return
+}
+
+type treeIterator[T, V any] struct {
+	less  func(a, b T) bool
+	stack []*treeNode[T, V]
+
+	// Query parameters. For point queries, lo == hi.
+	lo, hi T
+	point  bool
+
+	current *treeNode[T, V]
+}
+
+// pruned returns true if no interval in the subtree can match the query.
+func (i *treeIterator[T, V]) pruned(n *treeNode[T, V]) bool {
+	// All intervals in the subtree end before the query starts
+	return !i.less(i.lo, n.maxHi)
+}
+
+// pastQuery returns true if the interval, and all subsequent intervals, start after the query.
+func (i *treeIterator[T, V]) pastQuery(iv Interval[T]) bool {
+	if i.point {
+		return i.less(i.lo, iv.Lo)
+	}
+	return !i.less(iv.Lo, i.hi)
+}
+
+func (i *treeIterator[T, V]) matches(iv Interval[T]) bool {
+	if isEmpty(iv, i.less) {
+		return false
+	}
+	if i.point {
+		return !i.less(i.lo, iv.Lo) && i.less(i.lo, iv.Hi)
+	}
+	return i.less(iv.Lo, i.hi) && i.less(i.lo, iv.Hi)
+}
+
+func (i *treeIterator[T, V]) pushLeft(n *treeNode[T, V]) {
+	for n != nil && !i.pruned(n) {
+		i.stack = append(i.stack, n)
+		n = n.left
+	}
+}
+
+func (i *treeIterator[T, V]) Next() bool {
+	for len(i.stack) > 0 {
+		n := i.stack[len(i.stack)-1]
+		i.stack[len(i.stack)-1] = nil
+		i.stack = i.stack[:len(i.stack)-1]
+
+		if i.pastQuery(n.interval) {
+			i.stack = nil
+			return false
+		}
+
+		i.pushLeft(n.right)
+
+		if i.matches(n.interval) {
+			i.current = n
+			return true
+		}
+	}
+	return false
+}
+
+func (i *treeIterator[T, V]) Get() iter.Pair[Interval[T], V] {
+	return iter.Pair[Interval[T], V]{First: i.current.interval, Second: i.current.value}
+}
+
+func (i *treeIterator[T, V]) Err() error { return nil }
+
+// Overlapping returns an iterator over all intervals (and their values)
+// which overlap with [lo, hi) - that is, which have at least one value in common with [lo, hi).
+//
+// Intervals are generated in order of (Lo, Hi). The tree must not be modified during iteration.
+//
+// Complexity: O(k log n), where k is the number of generated intervals.
+func (t *Tree[T, V]) Overlapping(lo, hi T) iter.Iterator[iter.Pair[Interval[T], V]] {
+	i := &treeIterator[T, V]{less: t.Less, lo: lo, hi: hi}
+	if t.Less(lo, hi) {
+		i.pushLeft(t.root)
+	}
+	return i
+}
+
+// Containing returns an iterator over all intervals (and their values) which contain the point.
+//
+// Intervals are generated in order of (Lo, Hi). The tree must not be modified during iteration.
+//
+// Complexity: O(k log n), where k is the number of generated intervals.
+func (t *Tree[T, V]) Containing(point T) iter.Iterator[iter.Pair[Interval[T], V]] {
+	i := &treeIterator[T, V]{less: t.Less, lo: point, hi: point, point: true}
+	i.pushLeft(t.root)
+	return i
+}
+
+type allIterator[T, V any] struct {
+	stack   []*treeNode[T, V]
+	current *treeNode[T, V]
+}
+
+func (i *allIterator[T, V]) pushLeft(n *treeNode[T, V]) {
+	for ; n != nil; n = n.left {
+		i.stack = append(i.stack, n)
+	}
+}
+
+func (i *allIterator[T, V]) Next() bool {
+	if len(i.stack) == 0 {
+		return false
+	}
+
+	i.current = i.stack[len(i.stack)-1]
+	i.stack[len(i.stack)-1] = nil
+	i.stack = i.stack[:len(i.stack)-1]
+	i.pushLeft(i.current.right)
+	return true
+}
+
+func (i *allIterator[T, V]) Get() iter.Pair[Interval[T], V] {
+	return iter.Pair[Interval[T], V]{First: i.current.interval, Second: i.current.value}
+}
+
+func (i *allIterator[T, V]) Err() error { return nil }
+
+// Iter returns an iterator over all intervals (and their values) in the tree,
+// in order of (Lo, Hi). The tree must not be modified during iteration.
+func (t *Tree[T, V]) Iter() iter.Iterator[iter.Pair[Interval[T], V]] {
+	i := &allIterator[T, V]{}
+	i.pushLeft(t.root)
+	return i
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package interval_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/MKuranowski/go-extra-lib/container/interval"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+	"golang.org/x/exp/slices"
+)
+
+func values[T any](i iter.Iterator[iter.Pair[interval.Interval[T], string]]) []string {
+	return iter.IntoSlice(iter.Map(i, func(p iter.Pair[interval.Interval[T], string]) string { return p.Second }))
+}
+
+func exampleTree() *interval.Tree[int, string] {
+	t := interval.NewTree[int, string]()
+	t.Insert(interval.Of(1, 5), "a")
+	t.Insert(interval.Of(3, 8), "b")
+	t.Insert(interval.Of(6, 7), "c")
+	t.Insert(interval.Of(10, 15), "d")
+	t.Insert(interval.Of(12, 13), "e")
+	t.Insert(interval.Of(1, 3), "f")
+	return t
+}
+
+func TestTreeInsertGetDelete(t *testing.T) {
+	tr := exampleTree()
+	check.EqMsg(t, tr.Len(), 6, "tr.Len()")
+
+	v, ok := tr.Get(interval.Of(3, 8))
+	check.TrueMsg(t, ok, "tr.Get([3, 8)): ok")
+	check.EqMsg(t, v, "b", "tr.Get([3, 8))")
+
+	_, ok = tr.Get(interval.Of(3, 7))
+	check.FalseMsg(t, ok, "tr.Get([3, 7)): ok")
+
+	check.FalseMsg(t, tr.Insert(interval.Of(3, 8), "B"), "tr.Insert([3, 8), B)")
+	v, _ = tr.Get(interval.Of(3, 8))
+	check.EqMsg(t, v, "B", "tr.Get([3, 8)): after overwrite")
+	check.EqMsg(t, tr.Len(), 6, "tr.Len(): after overwrite")
+
+	check.TrueMsg(t, tr.Delete(interval.Of(1, 5)), "tr.Delete([1, 5))")
+	check.FalseMsg(t, tr.Delete(interval.Of(1, 5)), "tr.Delete([1, 5)): second time")
+	check.EqMsg(t, tr.Len(), 5, "tr.Len(): after delete")
+
+	check.DeepEqMsg(t, values(tr.Iter()), []string{"f", "B", "c", "d", "e"}, "tr.Iter(): after delete")
+}
+
+func TestTreeIter(t *testing.T) {
+	tr := exampleTree()
+	check.DeepEqMsg(t, values(tr.Iter()), []string{"f", "a", "b", "c", "d", "e"}, "tr.Iter()")
+}
+
+func TestTreeOverlapping(t *testing.T) {
+	tr := exampleTree()
+
+	check.DeepEqMsg(t, values(tr.Overlapping(4, 7)), []string{"a", "b", "c"}, "tr.Overlapping(4, 7)")
+	check.DeepEqMsg(t, values(tr.Overlapping(5, 6)), []string{"b"}, "tr.Overlapping(5, 6)")
+	check.DeepEqMsg(t, values(tr.Overlapping(8, 10)), []string{}, "tr.Overlapping(8, 10)")
+	check.DeepEqMsg(t, values(tr.Overlapping(0, 100)), []string{"f", "a", "b", "c", "d", "e"}, "tr.Overlapping(0, 100)")
+	check.DeepEqMsg(t, values(tr.Overlapping(14, 20)), []string{"d"}, "tr.Overlapping(14, 20)")
+	check.DeepEqMsg(t, values(tr.Overlapping(4, 4)), []string{}, "tr.Overlapping(4, 4)")
+}
+
+func TestTreeContaining(t *testing.T) {
+	tr := exampleTree()
+
+	check.DeepEqMsg(t, values(tr.Containing(1)), []string{"f", "a"}, "tr.Containing(1)")
+	check.DeepEqMsg(t, values(tr.Containing(3)), []string{"a", "b"}, "tr.Containing(3)")
+	check.DeepEqMsg(t, values(tr.Containing(6)), []string{"b", "c"}, "tr.Containing(6)")
+	check.DeepEqMsg(t, values(tr.Containing(8)), []string{}, "tr.Containing(8)")
+	check.DeepEqMsg(t, values(tr.Containing(12)), []string{"d", "e"}, "tr.Containing(12)")
+	check.DeepEqMsg(t, values(tr.Containing(15)), []string{}, "tr.Containing(15)")
+}
+
+func TestTreeTime(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2023, time.May, d, 0, 0, 0, 0, time.UTC) }
+
+	tr := interval.NewTreeFunc[time.Time, string](func(a, b time.Time) bool { return a.Before(b) })
+	tr.Insert(interval.Of(day(1), day(10)), "spring timetable")
+	tr.Insert(interval.Of(day(10), day(20)), "summer timetable")
+	tr.Insert(interval.Of(day(5), day(7)), "maintenance")
+
+	check.DeepEqMsg(
+		t,
+		values(tr.Containing(day(6))),
+		[]string{"spring timetable", "maintenance"},
+		"tr.Containing(May 6)",
+	)
+	check.DeepEqMsg(
+		t,
+		values(tr.Overlapping(day(9), day(11))),
+		[]string{"spring timetable", "summer timetable"},
+		"tr.Overlapping(May 9, May 11)",
+	)
+}
+
+func TestTreeRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	tr := interval.NewTree[int, string]()
+	present := make(map[interval.Interval[int]]string)
+
+	randomInterval := func() interval.Interval[int] {
+		lo := r.Intn(50)
+		return interval.Of(lo, lo+r.Intn(10))
+	}
+
+	for i := 0; i < 2000; i++ {
+		iv := randomInterval()
+		if r.Intn(3) == 0 {
+			_, inMap := present[iv]
+			check.EqMsg(t, tr.Delete(iv), inMap, "tr.Delete("+iv.String()+")")
+			delete(present, iv)
+		} else {
+			_, inMap := present[iv]
+			check.EqMsg(t, tr.Insert(iv, iv.String()), !inMap, "tr.Insert("+iv.String()+")")
+			present[iv] = iv.String()
+		}
+	}
+
+	check.EqMsg(t, tr.Len(), len(present), "tr.Len()")
+
+	all := make([]interval.Interval[int], 0, len(present))
+	for iv := range present {
+		all = append(all, iv)
+	}
+	slices.SortFunc(all, func(a, b interval.Interval[int]) bool {
+		return a.Lo < b.Lo || (a.Lo == b.Lo && a.Hi < b.Hi)
+	})
+
+	for i := 0; i < 200; i++ {
+		q := randomInterval()
+		expected := []string{}
+		for _, iv := range all {
+			if q.Lo < q.Hi && iv.Lo < iv.Hi && iv.Lo < q.Hi && q.Lo < iv.Hi {
+				expected = append(expected, iv.String())
+			}
+		}
+		check.DeepEqMsg(t, values(tr.Overlapping(q.Lo, q.Hi)), expected, "tr.Overlapping"+q.String())
+
+		p := r.Intn(60)
+		expected = []string{}
+		for _, iv := range all {
+			if iv.Lo <= p && p < iv.Hi {
+				expected = append(expected, iv.String())
+			}
+		}
+		check.DeepEqMsg(t, values(tr.Containing(p)), expected, "tr.Containing")
+	}
+}
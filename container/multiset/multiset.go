@@ -0,0 +1,288 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// multiset contains an implementation of an unordered collection of elements,
+// which may occur multiple times, in a `map[T]int`.
+package multiset
+
+import (
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"golang.org/x/exp/slices"
+)
+
+// Multiset (also known as a bag) is a type implementing an unordered collection of elements,
+// which keeps track of the number of occurrences of each element.
+//
+// The representation is a map[T]int from elements to their (positive) counts.
+// Elements with zero occurrences are never kept in the map.
+// The semantics follow Python's collections.Counter, except that
+// counts are never allowed to be negative.
+//
+// Multisets, just as maps, can be nil - and in this state new elements can't be added to it.
+//
+// To make an empty, non-nil multiset use make(Multiset[T]).
+//
+// To make a non-empty multiset, use map literals (with only positive values),
+// or the [Of] and [FromIter] functions:
+//
+//	letters := Multiset[rune]{'a': 2, 'b': 1}
+//
+// Given operation complexity assumes that element access, insertion and removal
+// of a map is on average constant.
+type Multiset[T comparable] map[T]int
+
+// Of returns a multiset containing all the provided elements.
+func Of[T comparable](xs ...T) Multiset[T] {
+	m := make(Multiset[T], len(xs))
+	for _, x := range xs {
+		m[x]++
+	}
+	return m
+}
+
+// FromIter returns a multiset with all elements generated by an iterator.
+// Returns nil and the error if the iterator fails.
+//
+//	FromIter(iter.OverString("hello")) → {'h': 1, 'e': 1, 'l': 2, 'o': 1}
+func FromIter[T comparable](i iter.Iterator[T]) (Multiset[T], error) {
+	m := make(Multiset[T])
+	for i.Next() {
+		m[i.Get()]++
+	}
+	if err := i.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Has returns true if the provided element occurs at least once in the multiset.
+//
+// Average complexity: constant
+func (m Multiset[T]) Has(x T) bool {
+	_, has := m[x]
+	return has
+}
+
+// Count returns the number of occurrences of the provided element.
+//
+// Average complexity: constant
+func (m Multiset[T]) Count(x T) int { return m[x] }
+
+// Add adds n occurrences of the provided element.
+// Nothing happens if n is not positive.
+//
+// Average complexity: constant
+func (m Multiset[T]) Add(x T, n int) {
+	if n > 0 {
+		m[x] += n
+	}
+}
+
+// Remove removes n occurrences of the provided element.
+// If the element occurs n times or less, it is removed completely.
+// Nothing happens if n is not positive.
+//
+// Average complexity: constant
+func (m Multiset[T]) Remove(x T, n int) {
+	if n <= 0 {
+		return
+	}
+
+	if c := m[x] - n; c > 0 {
+		m[x] = c
+	} else {
+		delete(m, x)
+	}
+}
+
+// RemoveAll ensures the provided element does not occur in the multiset.
+//
+// Average complexity: constant
+func (m Multiset[T]) RemoveAll(x T) { delete(m, x) }
+
+// Len returns the number of distinct elements in the multiset.
+// Shorthand for len(m).
+func (m Multiset[T]) Len() int { return len(m) }
+
+// Total returns the number of all elements in the multiset, including repetitions.
+//
+// Average complexity: linear
+func (m Multiset[T]) Total() int {
+	n := 0
+	for _, c := range m {
+		n += c
+	}
+	return n
+}
+
+// Clear ensures no elements are present in the multiset.
+//
+// Average complexity: linear
+func (m Multiset[T]) Clear() {
+	for elem := range m {
+		delete(m, elem)
+	}
+}
+
+// Clone returns a shallow copy of the multiset.
+//
+// Average complexity: linear
+func (m Multiset[T]) Clone() Multiset[T] {
+	if m == nil {
+		return nil
+	}
+
+	n := make(Multiset[T], len(m))
+	for elem, c := range m {
+		n[elem] = c
+	}
+	return n
+}
+
+// Equal returns true if m1 and m2 contain the same elements, with the same counts.
+//
+// Average complexity: constant if len(m1) != len(m2),
+// otherwise linear in terms of len(m1).
+func (m1 Multiset[T]) Equal(m2 Multiset[T]) bool {
+	if len(m1) != len(m2) {
+		return false
+	}
+
+	for elem, c := range m1 {
+		if m2[elem] != c {
+			return false
+		}
+	}
+	return true
+}
+
+// MostCommon returns the k most common elements and their counts,
+// ordered from the most common. If k is negative or greater than m.Len(),
+// all elements are returned.
+//
+// The order of elements with equal counts is unspecified.
+//
+// Average complexity: O(n log n), where n = len(m).
+func (m Multiset[T]) MostCommon(k int) []iter.Pair[T, int] {
+	all := make([]iter.Pair[T, int], 0, len(m))
+	for elem, c := range m {
+		all = append(all, iter.Pair[T, int]{First: elem, Second: c})
+	}
+	slices.SortFunc(all, func(a, b iter.Pair[T, int]) bool { return a.Second > b.Second })
+
+	if k >= 0 && k < len(all) {
+		all = all[:k]
+	}
+	return all
+}
+
+// Union ensures every element of m1 occurs at least as many times as in m2;
+// setting the count of every element to the maximum of counts from m1 and m2.
+//
+//	{a: 2, b: 1} ∪ {a: 1, c: 3} = {a: 2, b: 1, c: 3}
+//
+// Average complexity: linear in terms of len(m2).
+func (m1 Multiset[T]) Union(m2 Multiset[T]) {
+	for elem, c := range m2 {
+		if c > m1[elem] {
+			m1[elem] = c
+		}
+	}
+}
+
+// Intersection sets the count of every element of m1 to the minimum of counts from m1 and m2.
+//
+//	{a: 2, b: 1} ∩ {a: 1, c: 3} = {a: 1}
+//
+// Average complexity: linear in terms of len(m1).
+func (m1 Multiset[T]) Intersection(m2 Multiset[T]) {
+	for elem, c := range m1 {
+		if c2 := m2[elem]; c2 == 0 {
+			delete(m1, elem)
+		} else if c2 < c {
+			m1[elem] = c2
+		}
+	}
+}
+
+// Sum adds all occurrences of elements from m2 to m1.
+//
+//	{a: 2, b: 1} + {a: 1, c: 3} = {a: 3, b: 1, c: 3}
+//
+// Average complexity: linear in terms of len(m2).
+func (m1 Multiset[T]) Sum(m2 Multiset[T]) {
+	for elem, c := range m2 {
+		m1[elem] += c
+	}
+}
+
+// Difference removes all occurrences of elements from m2 from m1.
+// Elements whose count would drop to zero or below are removed.
+//
+//	{a: 2, b: 1} - {a: 1, b: 3} = {a: 1}
+//
+// Average complexity: linear in terms of len(m2).
+func (m1 Multiset[T]) Difference(m2 Multiset[T]) {
+	for elem, c := range m2 {
+		m1.Remove(elem, c)
+	}
+}
+
+// IsSubset returns true if every element of m1 occurs in m2 at least as many times as in m1.
+//
+// Average complexity: constant if len(m1) > len(m2),
+// otherwise linear in terms of len(m1).
+func (m1 Multiset[T]) IsSubset(m2 Multiset[T]) bool {
+	// short-circuit by the pigeonhole principle
+	if len(m1) > len(m2) {
+		return false
+	}
+
+	for elem, c := range m1 {
+		if m2[elem] < c {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if every element of m2 occurs in m1 at least as many times as in m2.
+//
+// Average complexity: constant if len(m2) > len(m1),
+// otherwise linear in terms of len(m2).
+func (m1 Multiset[T]) IsSuperset(m2 Multiset[T]) bool { return m2.IsSubset(m1) }
+
+type multiplicityIterator[T any] struct {
+	i    iter.Iterator[iter.Pair[T, int]]
+	elem T
+	left int
+}
+
+func (i *multiplicityIterator[T]) Next() bool {
+	for i.left <= 0 {
+		if !i.i.Next() {
+			return false
+		}
+		p := i.i.Get()
+		i.elem, i.left = p.First, p.Second
+	}
+	i.left--
+	return true
+}
+
+func (i *multiplicityIterator[T]) Get() T     { return i.elem }
+func (i *multiplicityIterator[T]) Err() error { return nil }
+
+// Iter returns an [iter.Iterator] over the elements of the multiset,
+// with every element repeated as many times as it occurs in the multiset.
+//
+// Elements are generated in an arbitrary order, but all occurrences
+// of a single element are generated one after another.
+func (m Multiset[T]) Iter() iter.Iterator[T] {
+	return &multiplicityIterator[T]{i: iter.OverMap(m)}
+}
+
+// IterCounts returns an [iter.Iterator] over distinct elements of the multiset and their counts.
+//
+// Elements are generated in an arbitrary order.
+func (m Multiset[T]) IterCounts() iter.Iterator[iter.Pair[T, int]] { return iter.OverMap(m) }
@@ -0,0 +1,136 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package multiset_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/MKuranowski/go-extra-lib/container/multiset"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+	"golang.org/x/exp/slices"
+)
+
+func TestMultisetAddRemoveCount(t *testing.T) {
+	m := Multiset[string]{}
+
+	check.EqMsg(t, m.Len(), 0, "m.Len(): empty multiset")
+	check.EqMsg(t, m.Total(), 0, "m.Total(): empty multiset")
+
+	m.Add("a", 2)
+	m.Add("b", 1)
+	m.Add("a", 1)
+	m.Add("c", 0)
+
+	check.EqMsg(t, m.Len(), 2, "m.Len(): after adding")
+	check.EqMsg(t, m.Total(), 4, "m.Total(): after adding")
+	check.EqMsg(t, m.Count("a"), 3, "m.Count(a): after adding")
+	check.EqMsg(t, m.Count("b"), 1, "m.Count(b): after adding")
+	check.EqMsg(t, m.Count("c"), 0, "m.Count(c): after adding")
+	check.FalseMsg(t, m.Has("c"), "m.Has(c): after adding")
+
+	m.Remove("a", 2)
+	m.Remove("b", 5)
+
+	check.EqMsg(t, m.Len(), 1, "m.Len(): after removing")
+	check.EqMsg(t, m.Count("a"), 1, "m.Count(a): after removing")
+	check.FalseMsg(t, m.Has("b"), "m.Has(b): after removing")
+
+	m.RemoveAll("a")
+	check.EqMsg(t, m.Len(), 0, "m.Len(): after RemoveAll")
+}
+
+func TestMultisetFromIter(t *testing.T) {
+	m, err := FromIter(iter.OverString("hello"))
+	check.NoErr(t, err)
+	check.DeepEqMsg(t, m, Multiset[rune]{'h': 1, 'e': 1, 'l': 2, 'o': 1}, "FromIter(hello)")
+
+	errTest := errors.New("test")
+	m, err = FromIter(iter.Error[rune](errTest))
+	check.SpecificErr(t, err, errTest)
+	check.TrueMsg(t, m == nil, "FromIter(error)")
+}
+
+func TestMultisetClone(t *testing.T) {
+	m1 := Of(1, 1, 2)
+	m2 := m1.Clone()
+
+	m2.Add(1, 1)
+
+	check.EqMsg(t, m1.Count(1), 2, "m1.Count(1)")
+	check.EqMsg(t, m2.Count(1), 3, "m2.Count(1)")
+}
+
+func TestMultisetEqual(t *testing.T) {
+	check.TrueMsg(t, Of(1, 1, 2).Equal(Of(2, 1, 1)), "{1, 1, 2} == {2, 1, 1}")
+	check.FalseMsg(t, Of(1, 1, 2).Equal(Of(1, 2)), "{1, 1, 2} == {1, 2}")
+	check.FalseMsg(t, Of(1, 1, 2).Equal(Of(1, 1, 3)), "{1, 1, 2} == {1, 1, 3}")
+}
+
+func TestMultisetMostCommon(t *testing.T) {
+	m := Multiset[string]{"a": 5, "b": 2, "c": 7, "d": 1}
+
+	check.DeepEqMsg(
+		t,
+		m.MostCommon(2),
+		[]iter.Pair[string, int]{{First: "c", Second: 7}, {First: "a", Second: 5}},
+		"m.MostCommon(2)",
+	)
+
+	check.DeepEqMsg(
+		t,
+		m.MostCommon(-1),
+		[]iter.Pair[string, int]{
+			{First: "c", Second: 7},
+			{First: "a", Second: 5},
+			{First: "b", Second: 2},
+			{First: "d", Second: 1},
+		},
+		"m.MostCommon(-1)",
+	)
+}
+
+func TestMultisetUnion(t *testing.T) {
+	m := Multiset[string]{"a": 2, "b": 1}
+	m.Union(Multiset[string]{"a": 1, "c": 3})
+	check.DeepEqMsg(t, m, Multiset[string]{"a": 2, "b": 1, "c": 3}, "after union")
+}
+
+func TestMultisetIntersection(t *testing.T) {
+	m := Multiset[string]{"a": 2, "b": 1}
+	m.Intersection(Multiset[string]{"a": 1, "c": 3})
+	check.DeepEqMsg(t, m, Multiset[string]{"a": 1}, "after intersection")
+}
+
+func TestMultisetSum(t *testing.T) {
+	m := Multiset[string]{"a": 2, "b": 1}
+	m.Sum(Multiset[string]{"a": 1, "c": 3})
+	check.DeepEqMsg(t, m, Multiset[string]{"a": 3, "b": 1, "c": 3}, "after sum")
+}
+
+func TestMultisetDifference(t *testing.T) {
+	m := Multiset[string]{"a": 2, "b": 1}
+	m.Difference(Multiset[string]{"a": 1, "b": 3})
+	check.DeepEqMsg(t, m, Multiset[string]{"a": 1}, "after difference")
+}
+
+func TestMultisetIsSubset(t *testing.T) {
+	check.TrueMsg(t, Of(1, 2).IsSubset(Of(1, 1, 2)), "{1, 2} ⊆ {1, 1, 2}")
+	check.FalseMsg(t, Of(1, 1, 2).IsSubset(Of(1, 2, 3)), "{1, 1, 2} ⊆ {1, 2, 3}")
+	check.TrueMsg(t, Of[int]().IsSubset(Of(1)), "{} ⊆ {1}")
+}
+
+func TestMultisetIsSuperset(t *testing.T) {
+	check.TrueMsg(t, Of(1, 1, 2).IsSuperset(Of(1, 2)), "{1, 1, 2} ⊇ {1, 2}")
+	check.FalseMsg(t, Of(1, 2, 3).IsSuperset(Of(1, 1, 2)), "{1, 2, 3} ⊇ {1, 1, 2}")
+}
+
+func TestMultisetIter(t *testing.T) {
+	sl := iter.IntoSlice(Multiset[int]{1: 2, 5: 1, 7: 3}.Iter())
+	slices.Sort(sl) // sort the collected slice to avoid problems with undefined map order
+
+	check.DeepEqMsg(t, sl, []int{1, 1, 5, 7, 7, 7}, "{1: 2, 5: 1, 7: 3}.Iter()")
+	check.DeepEqMsg(t, iter.IntoSlice(Multiset[int]{}.Iter()), []int{}, "{}.Iter()")
+}
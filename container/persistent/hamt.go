@@ -0,0 +1,270 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package persistent
+
+import (
+	"hash/maphash"
+	"math/bits"
+
+	"github.com/MKuranowski/go-extra-lib/internal/keyhash"
+)
+
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+
+	// hamtMaxShift is the shift at which all 64 bits of the hash are consumed,
+	// and nodes only hold entries with colliding hashes.
+	hamtMaxShift = 64
+)
+
+// seed is shared by all maps, so that hashes are stable for the lifetime of the program.
+var seed = maphash.MakeSeed()
+
+func hash[K comparable](key K) uint64 { return keyhash.Of(seed, key) }
+
+// hamtEntry is either a key-value pair (if child is nil), or a pointer to a child node.
+type hamtEntry[K comparable, V any] struct {
+	hash  uint64
+	key   K
+	value V
+	child *hamtNode[K, V]
+}
+
+// hamtNode is a node of a hash array mapped trie.
+//
+// For nodes with shift < hamtMaxShift, bitmap marks which of the 32 possible
+// hash fragments are present, and entries are ordered by the hash fragment.
+// Nodes with shift >= hamtMaxShift are collision nodes: bitmap is unused,
+// and entries contain key-value pairs with identical hashes.
+type hamtNode[K comparable, V any] struct {
+	edit    *editToken
+	bitmap  uint32
+	entries []hamtEntry[K, V]
+}
+
+func fragment(hash uint64, shift uint) uint32 { return 1 << ((hash >> shift) & hamtMask) }
+
+func (n *hamtNode[K, V]) index(bit uint32) int { return bits.OnesCount32(n.bitmap & (bit - 1)) }
+
+// editable returns a node which can be modified in place by the owner of the edit token.
+func (n *hamtNode[K, V]) editable(edit *editToken) *hamtNode[K, V] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+	return &hamtNode[K, V]{
+		edit:    edit,
+		bitmap:  n.bitmap,
+		entries: append(make([]hamtEntry[K, V], 0, len(n.entries)+1), n.entries...),
+	}
+}
+
+func (n *hamtNode[K, V]) find(shift uint, hash uint64, key K) (V, bool) {
+	for n != nil {
+		if shift >= hamtMaxShift {
+			for _, e := range n.entries {
+				if e.key == key {
+					return e.value, true
+				}
+			}
+			break
+		}
+
+		bit := fragment(hash, shift)
+		if n.bitmap&bit == 0 {
+			break
+		}
+
+		e := &n.entries[n.index(bit)]
+		if e.child == nil {
+			if e.hash == hash && e.key == key {
+				return e.value, true
+			}
+			break
+		}
+
+		n = e.child
+		shift += hamtBits
+	}
+
+	var zero V
+	return zero, false
+}
+
+// pair creates a node at the given shift containing two key-value pairs with different keys.
+func pair[K comparable, V any](edit *editToken, shift uint, a, b hamtEntry[K, V]) *hamtNode[K, V] {
+	if shift >= hamtMaxShift {
+		return &hamtNode[K, V]{edit: edit, entries: []hamtEntry[K, V]{a, b}}
+	}
+
+	aBit, bBit := fragment(a.hash, shift), fragment(b.hash, shift)
+	if aBit == bBit {
+		child := pair(edit, shift+hamtBits, a, b)
+		return &hamtNode[K, V]{edit: edit, bitmap: aBit, entries: []hamtEntry[K, V]{{child: child}}}
+	}
+
+	if bBit < aBit {
+		a, b = b, a
+	}
+	return &hamtNode[K, V]{edit: edit, bitmap: aBit | bBit, entries: []hamtEntry[K, V]{a, b}}
+}
+
+// insert returns a node with the provided key-value pair, and whether the key is new.
+func (n *hamtNode[K, V]) insert(edit *editToken, shift uint, leaf hamtEntry[K, V]) (*hamtNode[K, V], bool) {
+	if n == nil {
+		n = &hamtNode[K, V]{edit: edit}
+	}
+
+	if shift >= hamtMaxShift {
+		for i, e := range n.entries {
+			if e.key == leaf.key {
+				n = n.editable(edit)
+				n.entries[i] = leaf
+				return n, false
+			}
+		}
+		n = n.editable(edit)
+		n.entries = append(n.entries, leaf)
+		return n, true
+	}
+
+	bit := fragment(leaf.hash, shift)
+	idx := n.index(bit)
+
+	if n.bitmap&bit == 0 {
+		n = n.editable(edit)
+		n.bitmap |= bit
+		n.entries = append(n.entries, hamtEntry[K, V]{})
+		copy(n.entries[idx+1:], n.entries[idx:])
+		n.entries[idx] = leaf
+		return n, true
+	}
+
+	e := n.entries[idx]
+	switch {
+	case e.child != nil:
+		child, added := e.child.insert(edit, shift+hamtBits, leaf)
+		if child != e.child {
+			n = n.editable(edit)
+			n.entries[idx] = hamtEntry[K, V]{child: child}
+		}
+		return n, added
+
+	case e.hash == leaf.hash && e.key == leaf.key:
+		n = n.editable(edit)
+		n.entries[idx] = leaf
+		return n, false
+
+	default:
+		n = n.editable(edit)
+		n.entries[idx] = hamtEntry[K, V]{child: pair(edit, shift+hamtBits, e, leaf)}
+		return n, true
+	}
+}
+
+// remove returns a node without the provided key (possibly nil, if the node would be empty),
+// and whether the key was present.
+func (n *hamtNode[K, V]) remove(edit *editToken, shift uint, hash uint64, key K) (*hamtNode[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	var idx int
+	if shift >= hamtMaxShift {
+		idx = -1
+		for i, e := range n.entries {
+			if e.key == key {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return n, false
+		}
+	} else {
+		bit := fragment(hash, shift)
+		if n.bitmap&bit == 0 {
+			return n, false
+		}
+		idx = n.index(bit)
+
+		if e := n.entries[idx]; e.child != nil {
+			child, removed := e.child.remove(edit, shift+hamtBits, hash, key)
+			if !removed {
+				return n, false
+			}
+
+			if child != nil {
+				n = n.editable(edit)
+				if len(child.entries) == 1 && child.entries[0].child == nil {
+					// Pull a lone key-value pair up, to keep the trie shallow
+					n.entries[idx] = child.entries[0]
+				} else {
+					n.entries[idx] = hamtEntry[K, V]{child: child}
+				}
+				return n, true
+			}
+		} else if e.hash != hash || e.key != key {
+			return n, false
+		}
+		// fallthrough: remove the entry at idx
+	}
+
+	if len(n.entries) == 1 {
+		return nil, true
+	}
+
+	n = n.editable(edit)
+	if shift < hamtMaxShift {
+		n.bitmap &^= fragment(hash, shift)
+	}
+	copy(n.entries[idx:], n.entries[idx+1:])
+	n.entries[len(n.entries)-1] = hamtEntry[K, V]{}
+	n.entries = n.entries[:len(n.entries)-1]
+	return n, true
+}
+
+// hamtIterator traverses the trie depth-first.
+type hamtIterator[K comparable, V any] struct {
+	stack []hamtIteratorFrame[K, V]
+	entry *hamtEntry[K, V]
+}
+
+type hamtIteratorFrame[K comparable, V any] struct {
+	node *hamtNode[K, V]
+	next int
+}
+
+func newHamtIterator[K comparable, V any](root *hamtNode[K, V]) *hamtIterator[K, V] {
+	it := &hamtIterator[K, V]{}
+	if root != nil {
+		it.stack = append(it.stack, hamtIteratorFrame[K, V]{node: root})
+	}
+	return it
+}
+
+func (it *hamtIterator[K, V]) Next() bool {
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+		if top.next >= len(top.node.entries) {
+			it.stack = it.stack[:len(it.stack)-1]
+			continue
+		}
+
+		e := &top.node.entries[top.next]
+		top.next++
+
+		if e.child != nil {
+			it.stack = append(it.stack, hamtIteratorFrame[K, V]{node: e.child})
+		} else {
+			it.entry = e
+			return true
+		}
+	}
+
+	it.entry = nil
+	return false
+}
+
+func (*hamtIterator[K, V]) Err() error { return nil }
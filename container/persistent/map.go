@@ -0,0 +1,186 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package persistent
+
+import "github.com/MKuranowski/go-extra-lib/iter"
+
+// Map is an immutable mapping from keys to values,
+// implemented as a hash array mapped trie (HAMT).
+//
+// The zero value is an empty map ready to use. Maps are small values,
+// which should be passed around and compared by value.
+//
+// Given complexity assumes hashing a key takes constant time.
+// Since every level of the trie consumes 5 bits of a hash, operations are
+// practically constant-time, as a trie with n entries has an average depth of log₃₂(n).
+type Map[K comparable, V any] struct {
+	root *hamtNode[K, V]
+	len  int
+}
+
+// MapFrom returns a [Map] with the same entries as a built-in map.
+func MapFrom[K comparable, V any](m map[K]V) Map[K, V] {
+	b := &MapBuilder[K, V]{}
+	for k, v := range m {
+		b.Set(k, v)
+	}
+	return b.Map()
+}
+
+// Len returns the number of entries in the map.
+//
+// Complexity: constant
+func (m Map[K, V]) Len() int { return m.len }
+
+// Get returns the value associated with the key.
+// The second return value is false if the key is not in the map.
+//
+// Complexity: O(log n)
+func (m Map[K, V]) Get(key K) (V, bool) { return m.root.find(0, hash(key), key) }
+
+// Has returns true if the key is in the map.
+//
+// Complexity: O(log n)
+func (m Map[K, V]) Has(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// With returns a map with the key associated with the provided value.
+//
+// Complexity: O(log n)
+func (m Map[K, V]) With(key K, value V) Map[K, V] {
+	root, added := m.root.insert(nil, 0, hamtEntry[K, V]{hash: hash(key), key: key, value: value})
+	if added {
+		return Map[K, V]{root, m.len + 1}
+	}
+	return Map[K, V]{root, m.len}
+}
+
+// Without returns a map without the provided key.
+// If the key is not in the map, m is returned.
+//
+// Complexity: O(log n)
+func (m Map[K, V]) Without(key K) Map[K, V] {
+	root, removed := m.root.remove(nil, 0, hash(key), key)
+	if removed {
+		return Map[K, V]{root, m.len - 1}
+	}
+	return m
+}
+
+// Builder returns a [MapBuilder] initialized with entries of the map.
+//
+// Complexity: constant
+func (m Map[K, V]) Builder() *MapBuilder[K, V] {
+	return &MapBuilder[K, V]{root: m.root, len: m.len}
+}
+
+// ToMap returns a built-in map with the same entries.
+//
+// Complexity: O(n)
+func (m Map[K, V]) ToMap() map[K]V {
+	r := make(map[K]V, m.len)
+	for it := newHamtIterator(m.root); it.Next(); {
+		r[it.entry.key] = it.entry.value
+	}
+	return r
+}
+
+// Iter returns an [iter.Iterator] over key-value pairs of the map, in unspecified order.
+// The order is the same for every iteration over the same map.
+func (m Map[K, V]) Iter() iter.Iterator[iter.Pair[K, V]] {
+	return &mapIterator[K, V]{newHamtIterator(m.root)}
+}
+
+// Keys returns an [iter.Iterator] over keys of the map, in the same order as [Map.Iter].
+func (m Map[K, V]) Keys() iter.Iterator[K] { return &mapKeyIterator[K, V]{newHamtIterator(m.root)} }
+
+// Values returns an [iter.Iterator] over values of the map, in the same order as [Map.Iter].
+func (m Map[K, V]) Values() iter.Iterator[V] {
+	return &mapValueIterator[K, V]{newHamtIterator(m.root)}
+}
+
+// MapBuilder is a mutable, transient version of a [Map], used for efficient batch construction.
+//
+// The zero value is an empty builder ready to use.
+// Builders must not be used concurrently, and must not be copied.
+type MapBuilder[K comparable, V any] struct {
+	root *hamtNode[K, V]
+	len  int
+	edit *editToken
+}
+
+func (b *MapBuilder[K, V]) ensureEdit() {
+	if b.edit == nil {
+		b.edit = &editToken{}
+	}
+}
+
+// Len returns the number of entries in the builder.
+//
+// Complexity: constant
+func (b *MapBuilder[K, V]) Len() int { return b.len }
+
+// Get returns the value associated with the key.
+// The second return value is false if the key is not in the builder.
+//
+// Complexity: O(log n)
+func (b *MapBuilder[K, V]) Get(key K) (V, bool) { return b.root.find(0, hash(key), key) }
+
+// Has returns true if the key is in the builder.
+//
+// Complexity: O(log n)
+func (b *MapBuilder[K, V]) Has(key K) bool {
+	_, ok := b.Get(key)
+	return ok
+}
+
+// Set associates the key with the provided value.
+//
+// Complexity: O(log n)
+func (b *MapBuilder[K, V]) Set(key K, value V) {
+	b.ensureEdit()
+	var added bool
+	b.root, added = b.root.insert(b.edit, 0, hamtEntry[K, V]{hash: hash(key), key: key, value: value})
+	if added {
+		b.len++
+	}
+}
+
+// Delete ensures the key is not in the builder.
+//
+// Complexity: O(log n)
+func (b *MapBuilder[K, V]) Delete(key K) {
+	b.ensureEdit()
+	var removed bool
+	b.root, removed = b.root.remove(b.edit, 0, hash(key), key)
+	if removed {
+		b.len--
+	}
+}
+
+// Map returns an immutable [Map] with the entries of the builder.
+// The builder can still be used afterwards, without affecting the returned map.
+//
+// Complexity: constant
+func (b *MapBuilder[K, V]) Map() Map[K, V] {
+	// Forget the edit token, so that nodes shared with the returned map are no longer modified
+	b.edit = nil
+	return Map[K, V]{b.root, b.len}
+}
+
+type mapIterator[K comparable, V any] struct{ *hamtIterator[K, V] }
+
+func (it mapIterator[K, V]) Get() iter.Pair[K, V] {
+	return iter.Pair[K, V]{First: it.entry.key, Second: it.entry.value}
+}
+
+type mapKeyIterator[K comparable, V any] struct{ *hamtIterator[K, V] }
+
+func (it mapKeyIterator[K, V]) Get() K { return it.entry.key }
+
+type mapValueIterator[K comparable, V any] struct{ *hamtIterator[K, V] }
+
+func (it mapValueIterator[K, V]) Get() V { return it.entry.value }
@@ -0,0 +1,177 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package persistent_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/container/persistent"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+	"golang.org/x/exp/slices"
+)
+
+func TestMapWithWithout(t *testing.T) {
+	var m persistent.Map[string, int]
+	check.EqMsg(t, m.Len(), 0, "m.Len(): empty map")
+
+	m1 := m.With("a", 1).With("b", 2).With("c", 3)
+	m2 := m1.With("b", 20).Without("c")
+
+	check.EqMsg(t, m.Len(), 0, "m.Len(): after deriving")
+	check.DeepEqMsg(t, m1.ToMap(), map[string]int{"a": 1, "b": 2, "c": 3}, "m1.ToMap()")
+	check.DeepEqMsg(t, m2.ToMap(), map[string]int{"a": 1, "b": 20}, "m2.ToMap()")
+
+	v, ok := m2.Get("b")
+	check.TrueMsg(t, ok, "m2.Get(b): ok")
+	check.EqMsg(t, v, 20, "m2.Get(b): value")
+	check.FalseMsg(t, m2.Has("c"), "m2.Has(c)")
+
+	m3 := m2.Without("nonexistent")
+	check.EqMsg(t, m3.Len(), 2, "m3.Len()")
+}
+
+func TestMapIter(t *testing.T) {
+	m := persistent.MapFrom(map[int]string{1: "one", 2: "two", 3: "three"})
+
+	pairs := iter.IntoSlice(m.Iter())
+	slices.SortFunc(pairs, func(a, b iter.Pair[int, string]) bool { return a.First < b.First })
+	check.DeepEqMsg(
+		t,
+		pairs,
+		[]iter.Pair[int, string]{{First: 1, Second: "one"}, {First: 2, Second: "two"}, {First: 3, Second: "three"}},
+		"m.Iter()",
+	)
+
+	keys := iter.IntoSlice(m.Keys())
+	slices.Sort(keys)
+	check.DeepEqMsg(t, keys, []int{1, 2, 3}, "m.Keys()")
+
+	values := iter.IntoSlice(m.Values())
+	slices.Sort(values)
+	check.DeepEqMsg(t, values, []string{"one", "three", "two"}, "m.Values()")
+}
+
+func TestMapBuilder(t *testing.T) {
+	b := &persistent.MapBuilder[int, int]{}
+	for i := 0; i < 1000; i++ {
+		b.Set(i, i)
+	}
+	b.Delete(500)
+	m := b.Map()
+
+	check.EqMsg(t, m.Len(), 999, "m.Len()")
+	check.FalseMsg(t, m.Has(500), "m.Has(500)")
+
+	// Further modifications of the builder don't affect m
+	b.Set(1, 100)
+	b.Delete(2)
+	b.Set(500, 500)
+	v, _ := m.Get(1)
+	check.EqMsg(t, v, 1, "m.Get(1): after modifying builder")
+	check.TrueMsg(t, m.Has(2), "m.Has(2): after modifying builder")
+	check.FalseMsg(t, m.Has(500), "m.Has(500): after modifying builder")
+	check.EqMsg(t, b.Len(), 999, "b.Len()")
+
+	// Builders don't affect their source maps
+	b = m.Builder()
+	b.Set(3, 300)
+	b.Delete(4)
+	v, _ = m.Get(3)
+	check.EqMsg(t, v, 3, "m.Get(3): after modifying derived builder")
+	check.TrueMsg(t, m.Has(4), "m.Has(4): after modifying derived builder")
+	v, _ = b.Get(3)
+	check.EqMsg(t, v, 300, "b.Get(3)")
+}
+
+func TestMapRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	var m persistent.Map[int, int]
+	expected := make(map[int]int)
+	versions := []persistent.Map[int, int]{}
+	snapshots := []map[int]int{}
+
+	for step := 0; step < 20000; step++ {
+		// A small key space makes removals and overwrites common
+		k := r.Intn(3000)
+		if r.Intn(3) == 0 {
+			m = m.Without(k)
+			delete(expected, k)
+		} else {
+			v := r.Int()
+			m = m.With(k, v)
+			expected[k] = v
+		}
+
+		if step%2000 == 0 {
+			snapshot := make(map[int]int, len(expected))
+			for k, v := range expected {
+				snapshot[k] = v
+			}
+			versions = append(versions, m)
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	check.EqMsg(t, m.Len(), len(expected), "m.Len()")
+	check.DeepEqMsg(t, m.ToMap(), expected, "m.ToMap()")
+
+	for i, old := range versions {
+		check.DeepEqMsg(t, old.ToMap(), snapshots[i], "old version")
+	}
+
+	// Remove everything
+	for k := range expected {
+		m = m.Without(k)
+	}
+	check.EqMsg(t, m.Len(), 0, "m.Len(): after removing everything")
+	check.DeepEqMsg(t, iter.IntoSlice(m.Keys()), []int{}, "m.Keys(): after removing everything")
+}
+
+type structKey struct {
+	group bool
+	id    int
+}
+
+func TestMapStructKeys(t *testing.T) {
+	b := &persistent.MapBuilder[structKey, int]{}
+	for i := 0; i < 50000; i++ {
+		b.Set(structKey{i%2 == 0, i}, i)
+	}
+	m := b.Map()
+	check.EqMsg(t, m.Len(), 50000, "m.Len()")
+
+	for i := 0; i < 50000; i += 2 {
+		m = m.Without(structKey{true, i})
+	}
+	check.EqMsg(t, m.Len(), 25000, "m.Len(): after removing")
+	v, ok := m.Get(structKey{false, 49999})
+	check.TrueMsg(t, ok, "m.Get({false, 49999}): ok")
+	check.EqMsg(t, v, 49999, "m.Get({false, 49999}): value")
+	check.FalseMsg(t, m.Has(structKey{true, 49999}), "m.Has({true, 49999})")
+}
+
+func TestSet(t *testing.T) {
+	s := persistent.SetOf(1, 2, 3)
+	s2 := s.With(4).Without(1)
+
+	check.EqMsg(t, s.Len(), 3, "s.Len()")
+	check.TrueMsg(t, s.Has(1), "s.Has(1)")
+	check.FalseMsg(t, s.Has(4), "s.Has(4)")
+	check.TrueMsg(t, s2.Equal(persistent.SetOf(2, 3, 4)), "s2 == {2, 3, 4}")
+	check.FalseMsg(t, s.Equal(s2), "s == s2")
+
+	b := s2.Builder()
+	b.Add(5)
+	b.Remove(2)
+	check.TrueMsg(t, b.Has(5), "b.Has(5)")
+	check.EqMsg(t, b.Len(), 3, "b.Len()")
+	check.TrueMsg(t, b.Set().Equal(persistent.SetOf(3, 4, 5)), "b.Set() == {3, 4, 5}")
+	check.TrueMsg(t, s2.Equal(persistent.SetOf(2, 3, 4)), "s2 == {2, 3, 4}: after modifying builder")
+
+	elements := iter.IntoSlice(s2.Iter())
+	slices.Sort(elements)
+	check.DeepEqMsg(t, elements, []int{2, 3, 4}, "s2.Iter()")
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// persistent contains immutable collections with structural sharing:
+// [Vector], [Map] and [Set].
+//
+// Every modification returns a new version of a collection, leaving the original untouched.
+// New versions share most of their structure with the original, so modifications
+// only copy O(log n) elements. Thus, persistent collections can be freely shared
+// between goroutines without any copying or locking.
+//
+// For batch construction, every collection has a transient builder,
+// which modifies nodes it has created in place. Builders are not safe for concurrent use.
+package persistent
+
+// editToken marks nodes owned by a builder, which can be modified in place.
+//
+// editToken is not zero-sized, so that distinct allocations have distinct addresses.
+type editToken struct{ _ byte }
@@ -0,0 +1,105 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package persistent
+
+import "github.com/MKuranowski/go-extra-lib/iter"
+
+// Set is an immutable, unordered collection of elements,
+// implemented as a hash array mapped trie (HAMT).
+//
+// The zero value is an empty set ready to use. Sets are small values,
+// which should be passed around by value.
+//
+// See [Map] for the complexity of operations.
+type Set[T comparable] struct {
+	m Map[T, struct{}]
+}
+
+// SetOf returns a [Set] with the provided elements.
+func SetOf[T comparable](elements ...T) Set[T] {
+	b := &SetBuilder[T]{}
+	for _, x := range elements {
+		b.Add(x)
+	}
+	return b.Set()
+}
+
+// Len returns the number of elements in the set.
+//
+// Complexity: constant
+func (s Set[T]) Len() int { return s.m.Len() }
+
+// Has returns true if the provided element is in the set.
+//
+// Complexity: O(log n)
+func (s Set[T]) Has(x T) bool { return s.m.Has(x) }
+
+// With returns a set with the provided element.
+//
+// Complexity: O(log n)
+func (s Set[T]) With(x T) Set[T] { return Set[T]{s.m.With(x, struct{}{})} }
+
+// Without returns a set without the provided element.
+//
+// Complexity: O(log n)
+func (s Set[T]) Without(x T) Set[T] { return Set[T]{s.m.Without(x)} }
+
+// Equal returns true if s1 and s2 contain the same elements.
+//
+// Complexity: constant if s1.Len() != s2.Len(), otherwise O(n log n)
+func (s1 Set[T]) Equal(s2 Set[T]) bool {
+	if s1.Len() != s2.Len() {
+		return false
+	}
+
+	for it := newHamtIterator(s1.m.root); it.Next(); {
+		if !s2.Has(it.entry.key) {
+			return false
+		}
+	}
+	return true
+}
+
+// Builder returns a [SetBuilder] initialized with elements of the set.
+//
+// Complexity: constant
+func (s Set[T]) Builder() *SetBuilder[T] { return &SetBuilder[T]{*s.m.Builder()} }
+
+// Iter returns an [iter.Iterator] over the elements of the set, in unspecified order.
+// The order is the same for every iteration over the same set.
+func (s Set[T]) Iter() iter.Iterator[T] { return s.m.Keys() }
+
+// SetBuilder is a mutable, transient version of a [Set], used for efficient batch construction.
+//
+// The zero value is an empty builder ready to use.
+// Builders must not be used concurrently, and must not be copied.
+type SetBuilder[T comparable] struct {
+	b MapBuilder[T, struct{}]
+}
+
+// Len returns the number of elements in the builder.
+//
+// Complexity: constant
+func (b *SetBuilder[T]) Len() int { return b.b.Len() }
+
+// Has returns true if the provided element is in the builder.
+//
+// Complexity: O(log n)
+func (b *SetBuilder[T]) Has(x T) bool { return b.b.Has(x) }
+
+// Add ensures the provided element is in the builder.
+//
+// Complexity: O(log n)
+func (b *SetBuilder[T]) Add(x T) { b.b.Set(x, struct{}{}) }
+
+// Remove ensures the provided element is not in the builder.
+//
+// Complexity: O(log n)
+func (b *SetBuilder[T]) Remove(x T) { b.b.Delete(x) }
+
+// Set returns an immutable [Set] with the elements of the builder.
+// The builder can still be used afterwards, without affecting the returned set.
+//
+// Complexity: constant
+func (b *SetBuilder[T]) Set() Set[T] { return Set[T]{b.b.Map()} }
@@ -0,0 +1,412 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package persistent
+
+import (
+	"fmt"
+
+	"github.com/MKuranowski/go-extra-lib/iter"
+)
+
+const (
+	vectorBits  = 5
+	vectorWidth = 1 << vectorBits
+	vectorMask  = vectorWidth - 1
+)
+
+// vectorNode is a node of a bit-partitioned vector trie.
+// Internal nodes only have children, while leaves only have values.
+type vectorNode[T any] struct {
+	edit     *editToken
+	children []*vectorNode[T]
+	values   []T
+}
+
+func (n *vectorNode[T]) editable(edit *editToken) *vectorNode[T] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+
+	c := &vectorNode[T]{edit: edit}
+	if n.children != nil {
+		c.children = make([]*vectorNode[T], len(n.children), vectorWidth)
+		copy(c.children, n.children)
+	}
+	if n.values != nil {
+		c.values = append(make([]T, 0, vectorWidth), n.values...)
+	}
+	return c
+}
+
+// vectorData is the representation shared by [Vector] and [VectorBuilder].
+//
+// Elements are stored in a trie of 32-element leaves, except for the last (up to 32) elements,
+// which are stored in a separate tail - making appends and removals from the end cheap.
+type vectorData[T any] struct {
+	len   int
+	shift uint
+	root  *vectorNode[T]
+	tail  []T
+}
+
+func (v *vectorData[T]) tailOffset() int {
+	if v.len < vectorWidth {
+		return 0
+	}
+	return ((v.len - 1) >> vectorBits) << vectorBits
+}
+
+func (v *vectorData[T]) checkIndex(i int) {
+	if i < 0 || i >= v.len {
+		panic(fmt.Sprintf("persistent: index out of range [%d] with length %d", i, v.len))
+	}
+}
+
+// leafFor returns the slice containing the i-th element.
+func (v *vectorData[T]) leafFor(i int) []T {
+	if i >= v.tailOffset() {
+		return v.tail
+	}
+
+	n := v.root
+	for level := v.shift; level > 0; level -= vectorBits {
+		n = n.children[(i>>level)&vectorMask]
+	}
+	return n.values
+}
+
+func (v *vectorData[T]) get(i int) T {
+	v.checkIndex(i)
+	return v.leafFor(i)[i&vectorMask]
+}
+
+func newPath[T any](edit *editToken, level uint, n *vectorNode[T]) *vectorNode[T] {
+	if level == 0 {
+		return n
+	}
+	return &vectorNode[T]{edit: edit, children: []*vectorNode[T]{newPath(edit, level-vectorBits, n)}}
+}
+
+func (v *vectorData[T]) pushTail(edit *editToken, level uint, parent, tail *vectorNode[T]) *vectorNode[T] {
+	n := parent.editable(edit)
+	sub := ((v.len - 1) >> level) & vectorMask
+
+	var child *vectorNode[T]
+	if level == vectorBits {
+		child = tail
+	} else if sub < len(parent.children) {
+		child = v.pushTail(edit, level-vectorBits, parent.children[sub], tail)
+	} else {
+		child = newPath(edit, level-vectorBits, tail)
+	}
+
+	if sub < len(n.children) {
+		n.children[sub] = child
+	} else {
+		n.children = append(n.children, child)
+	}
+	return n
+}
+
+// append adds an element to the end. If ownTail is set, the tail is modified in place.
+func (v *vectorData[T]) append(edit *editToken, ownTail bool, x T) {
+	if v.len-v.tailOffset() < vectorWidth {
+		if ownTail {
+			v.tail = append(v.tail, x)
+		} else {
+			v.tail = append(append(make([]T, 0, len(v.tail)+1), v.tail...), x)
+		}
+		v.len++
+		return
+	}
+
+	// Tail is full - push it into the trie
+	tail := &vectorNode[T]{edit: edit, values: v.tail}
+	if v.root == nil {
+		v.root = &vectorNode[T]{edit: edit, children: []*vectorNode[T]{}}
+		v.shift = vectorBits
+	}
+
+	if (v.len >> vectorBits) > (1 << v.shift) {
+		// Root is full - grow the trie
+		v.root = &vectorNode[T]{
+			edit:     edit,
+			children: []*vectorNode[T]{v.root, newPath(edit, v.shift, tail)},
+		}
+		v.shift += vectorBits
+	} else {
+		v.root = v.pushTail(edit, v.shift, v.root, tail)
+	}
+
+	if ownTail {
+		v.tail = make([]T, 1, vectorWidth)
+		v.tail[0] = x
+	} else {
+		v.tail = []T{x}
+	}
+	v.len++
+}
+
+func (v *vectorData[T]) doSet(edit *editToken, level uint, n *vectorNode[T], i int, x T) *vectorNode[T] {
+	n = n.editable(edit)
+	if level == 0 {
+		n.values[i&vectorMask] = x
+	} else {
+		sub := (i >> level) & vectorMask
+		n.children[sub] = v.doSet(edit, level-vectorBits, n.children[sub], i, x)
+	}
+	return n
+}
+
+// set replaces the i-th element. If ownTail is set, the tail is modified in place.
+func (v *vectorData[T]) set(edit *editToken, ownTail bool, i int, x T) {
+	v.checkIndex(i)
+
+	if i >= v.tailOffset() {
+		if !ownTail {
+			v.tail = append([]T(nil), v.tail...)
+		}
+		v.tail[i&vectorMask] = x
+	} else {
+		v.root = v.doSet(edit, v.shift, v.root, i, x)
+	}
+}
+
+func (v *vectorData[T]) popTail(edit *editToken, level uint, n *vectorNode[T]) *vectorNode[T] {
+	sub := ((v.len - 2) >> level) & vectorMask
+
+	if level > vectorBits {
+		child := v.popTail(edit, level-vectorBits, n.children[sub])
+		if child == nil && sub == 0 {
+			return nil
+		}
+
+		n = n.editable(edit)
+		if child == nil {
+			n.children[sub] = nil
+			n.children = n.children[:sub]
+		} else {
+			n.children[sub] = child
+		}
+		return n
+	} else if sub == 0 {
+		return nil
+	}
+
+	n = n.editable(edit)
+	n.children[sub] = nil
+	n.children = n.children[:sub]
+	return n
+}
+
+// pop removes the last element. If ownTail is set, the tail may be modified in place.
+func (v *vectorData[T]) pop(edit *editToken, ownTail bool) {
+	if v.len == 0 {
+		panic("persistent: pop from an empty vector")
+	}
+
+	if v.len == 1 {
+		*v = vectorData[T]{}
+		if ownTail {
+			v.tail = make([]T, 0, vectorWidth)
+		}
+		return
+	}
+
+	if v.len-v.tailOffset() > 1 {
+		if ownTail {
+			var zero T
+			v.tail[len(v.tail)-1] = zero
+		}
+		// Otherwise, the tail is shared and re-slicing is safe,
+		// as a shared tail is never modified in place.
+		v.tail = v.tail[:len(v.tail)-1]
+		v.len--
+		return
+	}
+
+	// Tail becomes empty - pull the last leaf out of the trie
+	newTail := v.leafFor(v.len - 2)
+	if ownTail {
+		newTail = append(make([]T, 0, vectorWidth), newTail...)
+	}
+
+	root := v.popTail(edit, v.shift, v.root)
+	switch {
+	case root == nil:
+		v.root = nil
+		v.shift = 0
+	case v.shift > vectorBits && len(root.children) == 1:
+		v.root = root.children[0]
+		v.shift -= vectorBits
+	default:
+		v.root = root
+	}
+
+	v.tail = newTail
+	v.len--
+}
+
+// Vector is an immutable sequence of elements,
+// implemented as a bit-partitioned vector trie with a 32-element tail.
+//
+// The zero value is an empty vector ready to use. Vectors are small values,
+// which should be passed around by value.
+//
+// Since every level of the trie holds 32 elements, O(log n) operations
+// are practically constant-time, as a trie with n elements has a depth of log₃₂(n).
+type Vector[T any] struct {
+	d vectorData[T]
+}
+
+// VectorOf returns a [Vector] with the provided elements.
+func VectorOf[T any](elements ...T) Vector[T] {
+	b := &VectorBuilder[T]{}
+	for _, x := range elements {
+		b.Append(x)
+	}
+	return b.Vector()
+}
+
+// Len returns the number of elements in the vector.
+//
+// Complexity: constant
+func (v Vector[T]) Len() int { return v.d.len }
+
+// Get returns the i-th element of the vector. Panics if i is out of range.
+//
+// Complexity: O(log n)
+func (v Vector[T]) Get(i int) T { return v.d.get(i) }
+
+// With returns a vector with the i-th element replaced by x. Panics if i is out of range.
+//
+// Complexity: O(log n)
+func (v Vector[T]) With(i int, x T) Vector[T] {
+	v.d.set(nil, false, i, x)
+	return v
+}
+
+// Append returns a vector with x added to the end.
+//
+// Complexity: O(log n)
+func (v Vector[T]) Append(x T) Vector[T] {
+	v.d.append(nil, false, x)
+	return v
+}
+
+// Pop returns a vector without the last element. Panics if the vector is empty.
+//
+// Complexity: O(log n)
+func (v Vector[T]) Pop() Vector[T] {
+	v.d.pop(nil, false)
+	return v
+}
+
+// Builder returns a [VectorBuilder] initialized with elements of the vector.
+//
+// Complexity: constant
+func (v Vector[T]) Builder() *VectorBuilder[T] {
+	b := &VectorBuilder[T]{d: v.d}
+	b.d.tail = append(make([]T, 0, vectorWidth), v.d.tail...)
+	return b
+}
+
+// ToSlice returns a slice with all elements of the vector.
+//
+// Complexity: O(n)
+func (v Vector[T]) ToSlice() []T {
+	s := make([]T, 0, v.d.len)
+	for i := 0; i < v.d.len; i += vectorWidth {
+		s = append(s, v.d.leafFor(i)...)
+	}
+	return s
+}
+
+// Iter returns an [iter.Iterator] over the elements of the vector, from first to last.
+func (v Vector[T]) Iter() iter.Iterator[T] { return &vectorIterator[T]{d: v.d, i: -1} }
+
+// VectorBuilder is a mutable, transient version of a [Vector], used for efficient batch construction.
+//
+// The zero value is an empty builder ready to use.
+// Builders must not be used concurrently, and must not be copied.
+type VectorBuilder[T any] struct {
+	d    vectorData[T]
+	edit *editToken
+}
+
+func (b *VectorBuilder[T]) ensureEdit() {
+	if b.edit == nil {
+		b.edit = &editToken{}
+	}
+}
+
+// Len returns the number of elements in the builder.
+//
+// Complexity: constant
+func (b *VectorBuilder[T]) Len() int { return b.d.len }
+
+// Get returns the i-th element of the builder. Panics if i is out of range.
+//
+// Complexity: O(log n)
+func (b *VectorBuilder[T]) Get(i int) T { return b.d.get(i) }
+
+// Set replaces the i-th element of the builder. Panics if i is out of range.
+//
+// Complexity: O(log n)
+func (b *VectorBuilder[T]) Set(i int, x T) {
+	b.ensureEdit()
+	b.d.set(b.edit, true, i, x)
+}
+
+// Append adds x to the end of the builder.
+//
+// Complexity: amortized constant
+func (b *VectorBuilder[T]) Append(x T) {
+	b.ensureEdit()
+	b.d.append(b.edit, true, x)
+}
+
+// Pop removes the last element of the builder. Panics if the builder is empty.
+//
+// Complexity: O(log n)
+func (b *VectorBuilder[T]) Pop() {
+	b.ensureEdit()
+	b.d.pop(b.edit, true)
+}
+
+// Vector returns an immutable [Vector] with the elements of the builder.
+// The builder can still be used afterwards, without affecting the returned vector.
+//
+// Complexity: constant
+func (b *VectorBuilder[T]) Vector() Vector[T] {
+	// Forget the edit token, so that nodes shared with the returned vector are no longer modified
+	b.edit = nil
+
+	v := Vector[T]{b.d}
+	v.d.tail = append([]T(nil), b.d.tail...)
+	return v
+}
+
+type vectorIterator[T any] struct {
+	d    vectorData[T]
+	i    int
+	leaf []T
+}
+
+func (it *vectorIterator[T]) Next() bool {
+	if it.i+1 >= it.d.len {
+		it.i = it.d.len
+		return false
+	}
+
+	it.i++
+	if it.i&vectorMask == 0 || it.leaf == nil {
+		it.leaf = it.d.leafFor(it.i)
+	}
+	return true
+}
+
+func (it *vectorIterator[T]) Get() T { return it.leaf[it.i&vectorMask] }
+
+func (*vectorIterator[T]) Err() error { return nil }
@@ -0,0 +1,153 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package persistent_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/container/persistent"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func TestVectorAppendGet(t *testing.T) {
+	var v persistent.Vector[int]
+	versions := []persistent.Vector[int]{v}
+
+	for i := 0; i < 2000; i++ {
+		v = v.Append(i)
+		versions = append(versions, v)
+	}
+
+	check.EqMsg(t, v.Len(), 2000, "v.Len()")
+	for i := 0; i < 2000; i++ {
+		if x := v.Get(i); x != i {
+			t.Fatalf("v.Get(%d) = %d, expected %d", i, x, i)
+		}
+	}
+
+	// Old versions are unaffected
+	for n, old := range versions {
+		if old.Len() != n {
+			t.Fatalf("versions[%d].Len() = %d", n, old.Len())
+		}
+		if n > 0 && old.Get(n-1) != n-1 {
+			t.Fatalf("versions[%d].Get(%d) = %d", n, n-1, old.Get(n-1))
+		}
+	}
+}
+
+func TestVectorWith(t *testing.T) {
+	v := persistent.VectorOf(0, 1, 2, 3, 4)
+	w := v.With(2, 20).With(4, 40)
+
+	check.DeepEqMsg(t, v.ToSlice(), []int{0, 1, 2, 3, 4}, "v.ToSlice()")
+	check.DeepEqMsg(t, w.ToSlice(), []int{0, 1, 20, 3, 40}, "w.ToSlice()")
+
+	big := persistent.VectorOf(make([]int, 5000)...)
+	bigger := big.With(1234, 1)
+	check.EqMsg(t, big.Get(1234), 0, "big.Get(1234)")
+	check.EqMsg(t, bigger.Get(1234), 1, "bigger.Get(1234)")
+}
+
+func TestVectorPop(t *testing.T) {
+	v := persistent.VectorOf[int]()
+	for i := 0; i < 1100; i++ {
+		v = v.Append(i)
+	}
+
+	w := v
+	for i := 1099; i >= 0; i-- {
+		check.EqMsg(t, w.Get(i), i, "w.Get(last)")
+		w = w.Pop()
+		check.EqMsg(t, w.Len(), i, "w.Len(): after pop")
+	}
+
+	check.EqMsg(t, v.Len(), 1100, "v.Len(): after popping from w")
+	check.EqMsg(t, v.Get(1099), 1099, "v.Get(1099): after popping from w")
+
+	// Appending after popping
+	w = v.Pop().Pop().Append(-1)
+	check.EqMsg(t, w.Get(1097), 1097, "w.Get(1097)")
+	check.EqMsg(t, w.Get(1098), -1, "w.Get(1098)")
+	check.EqMsg(t, v.Get(1098), 1098, "v.Get(1098)")
+}
+
+func TestVectorOutOfRange(t *testing.T) {
+	defer func() {
+		check.TrueMsg(t, recover() != nil, "Get(3) didn't panic")
+	}()
+	persistent.VectorOf(1, 2, 3).Get(3)
+}
+
+func TestVectorBuilder(t *testing.T) {
+	b := &persistent.VectorBuilder[int]{}
+	for i := 0; i < 1500; i++ {
+		b.Append(i)
+	}
+	b.Set(10, -10)
+	b.Pop()
+
+	v := b.Vector()
+	check.EqMsg(t, v.Len(), 1499, "v.Len()")
+	check.EqMsg(t, v.Get(10), -10, "v.Get(10)")
+
+	// Further modifications of the builder don't affect v
+	b.Set(10, 10)
+	b.Set(1498, 0)
+	b.Append(1499)
+	check.EqMsg(t, v.Get(10), -10, "v.Get(10): after modifying builder")
+	check.EqMsg(t, v.Get(1498), 1498, "v.Get(1498): after modifying builder")
+	check.EqMsg(t, v.Len(), 1499, "v.Len(): after modifying builder")
+
+	// Builders don't affect their source vectors
+	b = v.Builder()
+	b.Set(0, 100)
+	b.Set(1498, 100)
+	b.Append(100)
+	check.EqMsg(t, v.Get(0), 0, "v.Get(0): after modifying derived builder")
+	check.EqMsg(t, v.Get(1498), 1498, "v.Get(1498): after modifying derived builder")
+	check.EqMsg(t, b.Vector().Get(1499), 100, "b.Vector().Get(1499)")
+}
+
+func TestVectorIter(t *testing.T) {
+	check.DeepEqMsg(t, iter.IntoSlice(persistent.Vector[int]{}.Iter()), []int{}, "empty vector")
+
+	expected := make([]int, 100)
+	for i := range expected {
+		expected[i] = i * i
+	}
+	v := persistent.VectorOf(expected...)
+	check.DeepEqMsg(t, iter.IntoSlice(v.Iter()), expected, "v.Iter()")
+	check.DeepEqMsg(t, v.ToSlice(), expected, "v.ToSlice()")
+}
+
+func TestVectorRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	var v persistent.Vector[int]
+	var s []int
+
+	for step := 0; step < 20000; step++ {
+		switch op := r.Intn(10); {
+		case op < 5:
+			x := r.Int()
+			v = v.Append(x)
+			s = append(s, x)
+		case op < 8 && len(s) > 0:
+			i, x := r.Intn(len(s)), r.Int()
+			v = v.With(i, x)
+			s[i] = x
+		case len(s) > 0:
+			v = v.Pop()
+			s = s[:len(s)-1]
+		}
+
+		if v.Len() != len(s) {
+			t.Fatalf("step %d: v.Len() = %d, expected %d", step, v.Len(), len(s))
+		}
+	}
+
+	check.DeepEqMsg(t, v.ToSlice(), s, "v.ToSlice()")
+}
@@ -0,0 +1,504 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package roaring
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const (
+	// chunkSize is the number of values covered by a single container.
+	chunkSize = 1 << 16
+
+	// bitmapWords is the number of uint64 words in a bitmapContainer.
+	bitmapWords = chunkSize / 64
+
+	// arrayMaxSize is the maximum cardinality of an arrayContainer;
+	// above that a bitmapContainer takes less memory.
+	arrayMaxSize = 4096
+)
+
+// container stores the low 16 bits of elements of a single 2^16-sized chunk.
+//
+// Mutating methods return the container which should replace the receiver -
+// the representation may change, e.g. when an array container grows too big.
+// Operations may return nil to signal an empty container.
+type container interface {
+	has(x uint16) bool
+	add(x uint16) container
+	remove(x uint16) container
+	cardinality() int
+
+	// next returns the smallest element not smaller than x.
+	next(x int) (uint16, bool)
+
+	// bitmap returns the contents of the container as a bitmap.
+	// The result must not be modified, as it may be shared with the container.
+	bitmap() *bitmapContainer
+
+	clone() container
+}
+
+// best returns the most compact representation of the provided bitmap;
+// or nil if the bitmap is empty.
+func best(b *bitmapContainer) container {
+	if b.card == 0 {
+		return nil
+	}
+
+	// Compare serialized sizes of every representation
+	runsSize := 2 + 4*b.numRuns()
+	switch {
+	case runsSize < 2*b.card && runsSize < 8*bitmapWords:
+		return b.toRuns()
+	case b.card <= arrayMaxSize:
+		return b.toArray()
+	default:
+		return b
+	}
+}
+
+// Array container
+
+// arrayContainer is a sorted list of values, used for sparse chunks.
+type arrayContainer struct {
+	values []uint16
+}
+
+func (a *arrayContainer) search(x uint16) (int, bool) {
+	idx := sort.Search(len(a.values), func(i int) bool { return a.values[i] >= x })
+	return idx, idx < len(a.values) && a.values[idx] == x
+}
+
+func (a *arrayContainer) has(x uint16) bool {
+	_, found := a.search(x)
+	return found
+}
+
+func (a *arrayContainer) add(x uint16) container {
+	idx, found := a.search(x)
+	if found {
+		return a
+	}
+
+	if len(a.values) >= arrayMaxSize {
+		b := a.bitmap()
+		b.add(x)
+		return b
+	}
+
+	a.values = append(a.values, 0)
+	copy(a.values[idx+1:], a.values[idx:])
+	a.values[idx] = x
+	return a
+}
+
+func (a *arrayContainer) remove(x uint16) container {
+	idx, found := a.search(x)
+	if !found {
+		return a
+	}
+
+	a.values = append(a.values[:idx], a.values[idx+1:]...)
+	if len(a.values) == 0 {
+		return nil
+	}
+	return a
+}
+
+func (a *arrayContainer) cardinality() int { return len(a.values) }
+
+func (a *arrayContainer) next(x int) (uint16, bool) {
+	idx := sort.Search(len(a.values), func(i int) bool { return int(a.values[i]) >= x })
+	if idx < len(a.values) {
+		return a.values[idx], true
+	}
+	return 0, false
+}
+
+func (a *arrayContainer) bitmap() *bitmapContainer {
+	b := &bitmapContainer{card: len(a.values)}
+	for _, x := range a.values {
+		b.words[x/64] |= 1 << (x % 64)
+	}
+	return b
+}
+
+func (a *arrayContainer) clone() container {
+	return &arrayContainer{values: append([]uint16(nil), a.values...)}
+}
+
+// filter returns a new array container with values for which keep returns true.
+func (a *arrayContainer) filter(keep func(x uint16) bool) container {
+	values := make([]uint16, 0, len(a.values))
+	for _, x := range a.values {
+		if keep(x) {
+			values = append(values, x)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return &arrayContainer{values: values}
+}
+
+// Bitmap container
+
+// bitmapContainer is a fixed-size bitset, used for dense chunks.
+type bitmapContainer struct {
+	words [bitmapWords]uint64
+	card  int
+}
+
+func (b *bitmapContainer) has(x uint16) bool { return b.words[x/64]&(1<<(x%64)) != 0 }
+
+func (b *bitmapContainer) add(x uint16) container {
+	if !b.has(x) {
+		b.words[x/64] |= 1 << (x % 64)
+		b.card++
+	}
+	return b
+}
+
+func (b *bitmapContainer) remove(x uint16) container {
+	if b.has(x) {
+		b.words[x/64] &^= 1 << (x % 64)
+		b.card--
+		if b.card <= arrayMaxSize {
+			return b.toArray()
+		}
+	}
+	return b
+}
+
+func (b *bitmapContainer) cardinality() int { return b.card }
+
+func (b *bitmapContainer) next(x int) (uint16, bool) {
+	if x >= chunkSize {
+		return 0, false
+	}
+
+	wordIdx := x / 64
+	if w := b.words[wordIdx] >> (x % 64); w != 0 {
+		return uint16(x + bits.TrailingZeros64(w)), true
+	}
+
+	for wordIdx++; wordIdx < bitmapWords; wordIdx++ {
+		if w := b.words[wordIdx]; w != 0 {
+			return uint16(64*wordIdx + bits.TrailingZeros64(w)), true
+		}
+	}
+	return 0, false
+}
+
+func (b *bitmapContainer) bitmap() *bitmapContainer { return b }
+
+func (b *bitmapContainer) clone() container {
+	c := *b
+	return &c
+}
+
+// recount recalculates the cardinality of the bitmap.
+func (b *bitmapContainer) recount() {
+	b.card = 0
+	for _, w := range b.words {
+		b.card += bits.OnesCount64(w)
+	}
+}
+
+// setRange sets all bits in range [lo, hi]. The cardinality is not updated.
+func (b *bitmapContainer) setRange(lo, hi int) {
+	loWord, hiWord := lo/64, hi/64
+	loMask := ^uint64(0) << (lo % 64)
+	hiMask := ^uint64(0) >> (63 - hi%64)
+
+	if loWord == hiWord {
+		b.words[loWord] |= loMask & hiMask
+		return
+	}
+
+	b.words[loWord] |= loMask
+	for i := loWord + 1; i < hiWord; i++ {
+		b.words[i] = ^uint64(0)
+	}
+	b.words[hiWord] |= hiMask
+}
+
+// numRuns returns the number of runs of consecutive set bits.
+func (b *bitmapContainer) numRuns() int {
+	n := 0
+	carry := uint64(0)
+	for _, w := range b.words {
+		// Count bits which are set, but whose predecessor is not
+		n += bits.OnesCount64(w &^ (w<<1 | carry))
+		carry = w >> 63
+	}
+	return n
+}
+
+func (b *bitmapContainer) toArray() *arrayContainer {
+	a := &arrayContainer{values: make([]uint16, 0, b.card)}
+	for wordIdx, w := range b.words {
+		for w != 0 {
+			a.values = append(a.values, uint16(64*wordIdx+bits.TrailingZeros64(w)))
+			w &= w - 1
+		}
+	}
+	return a
+}
+
+func (b *bitmapContainer) toRuns() *runContainer {
+	r := &runContainer{}
+	for x, ok := b.next(0); ok; {
+		// Find the end of the run
+		end := chunkSize
+		if x < chunkSize-1 {
+			end = b.nextClear(int(x) + 1)
+		}
+		r.runs = append(r.runs, run{x, uint16(end - 1)})
+
+		if end >= chunkSize {
+			break
+		}
+		x, ok = b.next(end)
+	}
+	return r
+}
+
+// nextClear returns the smallest number not in the bitmap and not smaller than x,
+// or chunkSize if there's no such number.
+func (b *bitmapContainer) nextClear(x int) int {
+	wordIdx := x / 64
+	if w := ^b.words[wordIdx] >> (x % 64); w != 0 {
+		return x + bits.TrailingZeros64(w)
+	}
+
+	for wordIdx++; wordIdx < bitmapWords; wordIdx++ {
+		if w := ^b.words[wordIdx]; w != 0 {
+			return 64*wordIdx + bits.TrailingZeros64(w)
+		}
+	}
+	return chunkSize
+}
+
+// Run container
+
+// run represents all numbers in range [start, last].
+type run struct{ start, last uint16 }
+
+// runContainer is a sorted list of disjoint ranges, used for chunks with
+// long sequences of consecutive values.
+type runContainer struct {
+	runs []run
+}
+
+// search returns the index of the first run which ends at or after x.
+func (r *runContainer) search(x int) int {
+	return sort.Search(len(r.runs), func(i int) bool { return int(r.runs[i].last) >= x })
+}
+
+func (r *runContainer) has(x uint16) bool {
+	idx := r.search(int(x))
+	return idx < len(r.runs) && r.runs[idx].start <= x
+}
+
+func (r *runContainer) add(x uint16) container {
+	idx := r.search(int(x))
+	if idx < len(r.runs) && r.runs[idx].start <= x {
+		return r
+	}
+
+	extendsPrev := idx > 0 && int(r.runs[idx-1].last)+1 == int(x)
+	extendsNext := idx < len(r.runs) && int(r.runs[idx].start)-1 == int(x)
+
+	switch {
+	case extendsPrev && extendsNext:
+		r.runs[idx-1].last = r.runs[idx].last
+		r.runs = append(r.runs[:idx], r.runs[idx+1:]...)
+	case extendsPrev:
+		r.runs[idx-1].last = x
+	case extendsNext:
+		r.runs[idx].start = x
+	default:
+		r.runs = append(r.runs, run{})
+		copy(r.runs[idx+1:], r.runs[idx:])
+		r.runs[idx] = run{x, x}
+		if 4*len(r.runs) > 8*bitmapWords {
+			return best(r.bitmap())
+		}
+	}
+	return r
+}
+
+func (r *runContainer) remove(x uint16) container {
+	idx := r.search(int(x))
+	if idx >= len(r.runs) || r.runs[idx].start > x {
+		return r
+	}
+
+	switch ru := r.runs[idx]; {
+	case ru.start == ru.last:
+		r.runs = append(r.runs[:idx], r.runs[idx+1:]...)
+		if len(r.runs) == 0 {
+			return nil
+		}
+	case ru.start == x:
+		r.runs[idx].start++
+	case ru.last == x:
+		r.runs[idx].last--
+	default:
+		r.runs = append(r.runs, run{})
+		copy(r.runs[idx+1:], r.runs[idx:])
+		r.runs[idx] = run{ru.start, x - 1}
+		r.runs[idx+1] = run{x + 1, ru.last}
+		if 4*len(r.runs) > 8*bitmapWords {
+			return best(r.bitmap())
+		}
+	}
+	return r
+}
+
+func (r *runContainer) cardinality() int {
+	n := 0
+	for _, ru := range r.runs {
+		n += int(ru.last) - int(ru.start) + 1
+	}
+	return n
+}
+
+func (r *runContainer) next(x int) (uint16, bool) {
+	idx := r.search(x)
+	if idx >= len(r.runs) {
+		return 0, false
+	}
+	if int(r.runs[idx].start) >= x {
+		return r.runs[idx].start, true
+	}
+	return uint16(x), true
+}
+
+func (r *runContainer) bitmap() *bitmapContainer {
+	b := &bitmapContainer{}
+	for _, ru := range r.runs {
+		b.setRange(int(ru.start), int(ru.last))
+	}
+	b.card = r.cardinality()
+	return b
+}
+
+func (r *runContainer) clone() container {
+	return &runContainer{runs: append([]run(nil), r.runs...)}
+}
+
+// Container algebra
+
+func union(c1, c2 container) container {
+	if a1, ok := c1.(*arrayContainer); ok {
+		if a2, ok := c2.(*arrayContainer); ok && len(a1.values)+len(a2.values) <= arrayMaxSize {
+			return mergeArrays(a1, a2)
+		}
+	}
+
+	return best(combineBitmaps(c1, c2, func(w1, w2 uint64) uint64 { return w1 | w2 }))
+}
+
+func intersection(c1, c2 container) container {
+	if a, ok := c1.(*arrayContainer); ok {
+		return a.filter(c2.has)
+	} else if a, ok := c2.(*arrayContainer); ok {
+		return a.filter(c1.has)
+	}
+	return best(combineBitmaps(c1, c2, func(w1, w2 uint64) uint64 { return w1 & w2 }))
+}
+
+func difference(c1, c2 container) container {
+	if a, ok := c1.(*arrayContainer); ok {
+		return a.filter(func(x uint16) bool { return !c2.has(x) })
+	}
+	return best(combineBitmaps(c1, c2, func(w1, w2 uint64) uint64 { return w1 &^ w2 }))
+}
+
+func symmetricDifference(c1, c2 container) container {
+	return best(combineBitmaps(c1, c2, func(w1, w2 uint64) uint64 { return w1 ^ w2 }))
+}
+
+// isSubset returns true if all elements of c1 are in c2.
+func isSubset(c1, c2 container) bool {
+	if c1.cardinality() > c2.cardinality() {
+		return false
+	}
+
+	if a, ok := c1.(*arrayContainer); ok {
+		for _, x := range a.values {
+			if !c2.has(x) {
+				return false
+			}
+		}
+		return true
+	}
+
+	b1, b2 := c1.bitmap(), c2.bitmap()
+	for i, w := range b1.words {
+		if w&^b2.words[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isDisjoint returns true if c1 and c2 have no elements in common.
+func isDisjoint(c1, c2 container) bool {
+	if a, ok := c1.(*arrayContainer); ok {
+		for _, x := range a.values {
+			if c2.has(x) {
+				return false
+			}
+		}
+		return true
+	} else if _, ok := c2.(*arrayContainer); ok {
+		return isDisjoint(c2, c1)
+	}
+
+	b1, b2 := c1.bitmap(), c2.bitmap()
+	for i, w := range b1.words {
+		if w&b2.words[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeArrays(a1, a2 *arrayContainer) *arrayContainer {
+	values := make([]uint16, 0, len(a1.values)+len(a2.values))
+	i, j := 0, 0
+	for i < len(a1.values) && j < len(a2.values) {
+		switch x, y := a1.values[i], a2.values[j]; {
+		case x < y:
+			values = append(values, x)
+			i++
+		case x > y:
+			values = append(values, y)
+			j++
+		default:
+			values = append(values, x)
+			i++
+			j++
+		}
+	}
+	values = append(values, a1.values[i:]...)
+	values = append(values, a2.values[j:]...)
+	return &arrayContainer{values: values}
+}
+
+// combineBitmaps returns a new bitmap created by applying op to every word of both containers.
+func combineBitmaps(c1, c2 container, op func(w1, w2 uint64) uint64) *bitmapContainer {
+	b1, b2 := c1.bitmap(), c2.bitmap()
+	result := &bitmapContainer{}
+	for i := range result.words {
+		result.words[i] = op(b1.words[i], b2.words[i])
+	}
+	result.recount()
+	return result
+}
@@ -0,0 +1,314 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// roaring contains an implementation of a [Roaring bitmap] - a compressed set of
+// 32-bit unsigned integers, efficient for both sparse and dense data.
+//
+// The universe of values is split into chunks of 2^16 elements, with every non-empty
+// chunk stored in one of 3 representations: a sorted array (for sparse chunks), a bitmap
+// (for dense chunks), or a list of runs (for chunks with long sequences of consecutive values).
+//
+// [Roaring bitmap]: https://roaringbitmap.org/
+package roaring
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/MKuranowski/go-extra-lib/iter"
+)
+
+// Bitmap is a compressed set of integers in range [0, 2^32).
+//
+// The zero value (`&Bitmap{}`) is a Bitmap containing no elements.
+//
+// Even though all operations accept `int` as an argument,
+// functions adding elements will panic if the provided number is outside of the [0, 2^32) range.
+//
+// Container representations are chosen automatically when performing set algebra;
+// after many calls to [Bitmap.Add], use [Bitmap.Optimize] to reclaim memory.
+type Bitmap struct {
+	keys       []uint16 // sorted high 16 bits of elements
+	containers []container
+}
+
+// Of returns a Bitmap containing all the provided elements.
+func Of(is ...int) *Bitmap {
+	b := &Bitmap{}
+	for _, i := range is {
+		b.Add(i)
+	}
+	return b
+}
+
+func split(i int) (hi, lo uint16) { return uint16(i >> 16), uint16(i) }
+
+func inRange(i int) bool { return i >= 0 && uint64(i) <= math.MaxUint32 }
+
+func checkRange(i int) {
+	if !inRange(i) {
+		panic(fmt.Sprintf("roaring: element out of range: %d", i))
+	}
+}
+
+// find returns the index of the container with the provided key,
+// and whether such container exists. If not, the returned index is
+// where such container should be inserted.
+func (b *Bitmap) find(key uint16) (int, bool) {
+	idx := sort.Search(len(b.keys), func(i int) bool { return b.keys[i] >= key })
+	return idx, idx < len(b.keys) && b.keys[idx] == key
+}
+
+func (b *Bitmap) insertAt(idx int, key uint16, c container) {
+	b.keys = append(b.keys, 0)
+	copy(b.keys[idx+1:], b.keys[idx:])
+	b.keys[idx] = key
+
+	b.containers = append(b.containers, nil)
+	copy(b.containers[idx+1:], b.containers[idx:])
+	b.containers[idx] = c
+}
+
+func (b *Bitmap) removeAt(idx int) {
+	b.keys = append(b.keys[:idx], b.keys[idx+1:]...)
+
+	copy(b.containers[idx:], b.containers[idx+1:])
+	b.containers[len(b.containers)-1] = nil
+	b.containers = b.containers[:len(b.containers)-1]
+}
+
+// set replaces the container at the provided index, removing it if c is nil.
+func (b *Bitmap) set(idx int, c container) {
+	if c == nil {
+		b.removeAt(idx)
+	} else {
+		b.containers[idx] = c
+	}
+}
+
+// Has returns true if the provided number is in the set.
+func (b *Bitmap) Has(i int) bool {
+	if !inRange(i) {
+		return false
+	}
+	hi, lo := split(i)
+	idx, found := b.find(hi)
+	return found && b.containers[idx].has(lo)
+}
+
+// Add ensures that the provided number is in the set.
+func (b *Bitmap) Add(i int) {
+	checkRange(i)
+	hi, lo := split(i)
+	if idx, found := b.find(hi); found {
+		b.containers[idx] = b.containers[idx].add(lo)
+	} else {
+		b.insertAt(idx, hi, &arrayContainer{values: []uint16{lo}})
+	}
+}
+
+// AddRange ensures that all numbers from range [lo, hi) are in the set.
+func (b *Bitmap) AddRange(lo, hi int) {
+	if lo >= hi {
+		return
+	}
+	checkRange(lo)
+	checkRange(hi - 1)
+
+	for start := lo; start < hi; {
+		key := uint16(start >> 16)
+		end := (int(key) + 1) << 16 // exclusive end of this chunk
+		if end > hi {
+			end = hi
+		}
+
+		c := &runContainer{runs: []run{{uint16(start), uint16(end - 1)}}}
+		if idx, found := b.find(key); found {
+			b.containers[idx] = union(b.containers[idx], c)
+		} else {
+			b.insertAt(idx, key, c)
+		}
+
+		start = end
+	}
+}
+
+// Remove ensures that the provided number is not in the set.
+func (b *Bitmap) Remove(i int) {
+	if !inRange(i) {
+		return
+	}
+	hi, lo := split(i)
+	if idx, found := b.find(hi); found {
+		b.set(idx, b.containers[idx].remove(lo))
+	}
+}
+
+// Len returns the number of elements in the set.
+func (b *Bitmap) Len() int {
+	n := 0
+	for _, c := range b.containers {
+		n += c.cardinality()
+	}
+	return n
+}
+
+// Clear ensures that no numbers are present in the set.
+func (b *Bitmap) Clear() {
+	b.keys = nil
+	b.containers = nil
+}
+
+// Clone returns a new set with the same elements.
+func (b *Bitmap) Clone() *Bitmap {
+	n := &Bitmap{
+		keys:       append([]uint16(nil), b.keys...),
+		containers: make([]container, len(b.containers)),
+	}
+	for i, c := range b.containers {
+		n.containers[i] = c.clone()
+	}
+	return n
+}
+
+// Optimize converts every container into its most compact representation.
+func (b *Bitmap) Optimize() {
+	for i, c := range b.containers {
+		b.containers[i] = best(c.bitmap())
+	}
+}
+
+// Equal returns true if b1 contains the same elements as b2.
+func (b1 *Bitmap) Equal(b2 *Bitmap) bool {
+	if len(b1.keys) != len(b2.keys) {
+		return false
+	}
+	for i, key := range b1.keys {
+		if key != b2.keys[i] {
+			return false
+		}
+		c1, c2 := b1.containers[i], b2.containers[i]
+		if c1.cardinality() != c2.cardinality() || !isSubset(c1, c2) {
+			return false
+		}
+	}
+	return true
+}
+
+// combine replaces b1 with a result of a set operation. op is called on containers
+// with the same keys; onlyLeft/onlyRight determine whether containers with keys
+// present in just one set are kept.
+func (b1 *Bitmap) combine(b2 *Bitmap, op func(c1, c2 container) container, onlyLeft, onlyRight bool) {
+	keys := make([]uint16, 0, len(b1.keys)+len(b2.keys))
+	containers := make([]container, 0, len(b1.keys)+len(b2.keys))
+
+	i, j := 0, 0
+	for i < len(b1.keys) || j < len(b2.keys) {
+		switch {
+		case j >= len(b2.keys) || (i < len(b1.keys) && b1.keys[i] < b2.keys[j]):
+			if onlyLeft {
+				keys = append(keys, b1.keys[i])
+				containers = append(containers, b1.containers[i])
+			}
+			i++
+
+		case i >= len(b1.keys) || b2.keys[j] < b1.keys[i]:
+			if onlyRight {
+				keys = append(keys, b2.keys[j])
+				containers = append(containers, b2.containers[j].clone())
+			}
+			j++
+
+		default:
+			if c := op(b1.containers[i], b2.containers[j]); c != nil {
+				keys = append(keys, b1.keys[i])
+				containers = append(containers, c)
+			}
+			i++
+			j++
+		}
+	}
+
+	b1.keys, b1.containers = keys, containers
+}
+
+// Union ensures b1 contains all elements from b2.
+func (b1 *Bitmap) Union(b2 *Bitmap) { b1.combine(b2, union, true, true) }
+
+// Intersection ensures b1 only contains elements that are present in both b1 and b2.
+func (b1 *Bitmap) Intersection(b2 *Bitmap) { b1.combine(b2, intersection, false, false) }
+
+// Difference ensures b1 does not contain any elements from b2.
+func (b1 *Bitmap) Difference(b2 *Bitmap) { b1.combine(b2, difference, true, false) }
+
+// SymmetricDifference ensures b1 contains elements present in exactly one of b1 and b2.
+func (b1 *Bitmap) SymmetricDifference(b2 *Bitmap) {
+	b1.combine(b2, symmetricDifference, true, true)
+}
+
+// IsDisjoint returns true if b1 and b2 have no elements in common.
+func (b1 *Bitmap) IsDisjoint(b2 *Bitmap) bool {
+	for i, key := range b1.keys {
+		if idx, found := b2.find(key); found && !isDisjoint(b1.containers[i], b2.containers[idx]) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubset returns true if every element of b1 is also present in b2.
+func (b1 *Bitmap) IsSubset(b2 *Bitmap) bool {
+	for i, key := range b1.keys {
+		idx, found := b2.find(key)
+		if !found || !isSubset(b1.containers[i], b2.containers[idx]) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if every element of b2 is also present in b1.
+func (b1 *Bitmap) IsSuperset(b2 *Bitmap) bool { return b2.IsSubset(b1) }
+
+type bitmapIterator struct {
+	b       *Bitmap
+	idx     int // index of the current container
+	from    int // lowest low 16 bits of the next element
+	current int
+}
+
+func (i *bitmapIterator) Next() bool {
+	for i.idx < len(i.b.containers) {
+		if lo, ok := i.b.containers[i.idx].next(i.from); ok {
+			i.current = int(i.b.keys[i.idx])<<16 | int(lo)
+			i.from = int(lo) + 1
+			return true
+		}
+		i.idx++
+		i.from = 0
+	}
+	return false
+}
+
+func (i *bitmapIterator) Get() int { return i.current }
+func (*bitmapIterator) Err() error { return nil }
+
+// Iter returns an iterator over the elements in the set, in ascending order.
+//
+// The set must not be modified during iteration.
+func (b *Bitmap) Iter() iter.Iterator[int] { return &bitmapIterator{b: b} }
+
+func (b *Bitmap) String() string {
+	s := strings.Builder{}
+	s.WriteByte('{')
+	for it := b.Iter(); it.Next(); {
+		if s.Len() > 1 {
+			s.WriteString(", ")
+		}
+		fmt.Fprint(&s, it.Get())
+	}
+	s.WriteByte('}')
+	return s.String()
+}
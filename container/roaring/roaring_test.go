@@ -0,0 +1,267 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package roaring_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/container/roaring"
+	"github.com/MKuranowski/go-extra-lib/container/set"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/assert"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+	"golang.org/x/exp/slices"
+)
+
+func TestBitmapAddHasLenRemove(t *testing.T) {
+	b := &roaring.Bitmap{}
+	check.EqMsg(t, b.Len(), 0, "b.Len(): empty set")
+
+	b.Add(2)
+	b.Add(70000)
+	b.Add(1 << 31)
+	b.Add(1<<32 - 1)
+
+	check.EqMsg(t, b.Len(), 4, "b.Len(): after adding")
+	check.TrueMsg(t, b.Has(2), "b.Has(2)")
+	check.TrueMsg(t, b.Has(70000), "b.Has(70000)")
+	check.TrueMsg(t, b.Has(1<<31), "b.Has(1<<31)")
+	check.TrueMsg(t, b.Has(1<<32-1), "b.Has(1<<32 - 1)")
+	check.FalseMsg(t, b.Has(3), "b.Has(3)")
+	check.FalseMsg(t, b.Has(-1), "b.Has(-1)")
+	check.FalseMsg(t, b.Has(1<<32), "b.Has(1<<32)")
+
+	b.Remove(70000)
+	b.Remove(70001)
+	check.EqMsg(t, b.Len(), 3, "b.Len(): after removing")
+	check.FalseMsg(t, b.Has(70000), "b.Has(70000): after removing")
+
+	check.DeepEqMsg(t, iter.IntoSlice(b.Iter()), []int{2, 1 << 31, 1<<32 - 1}, "b.Iter()")
+	check.EqMsg(t, b.String(), "{2, 2147483648, 4294967295}", "b.String()")
+}
+
+func TestBitmapDenseChunk(t *testing.T) {
+	b := &roaring.Bitmap{}
+	for i := 0; i < 10000; i += 2 {
+		b.Add(i)
+	}
+	check.EqMsg(t, b.Len(), 5000, "b.Len(): after adding")
+
+	for i := 0; i < 2000; i += 2 {
+		b.Remove(i)
+	}
+	check.EqMsg(t, b.Len(), 4000, "b.Len(): after removing")
+	check.FalseMsg(t, b.Has(1000), "b.Has(1000)")
+	check.TrueMsg(t, b.Has(2000), "b.Has(2000)")
+	check.FalseMsg(t, b.Has(2001), "b.Has(2001)")
+}
+
+func TestBitmapAddRange(t *testing.T) {
+	b := roaring.Of(5, 200000)
+	b.AddRange(65530, 131080)
+
+	check.EqMsg(t, b.Len(), 2+131080-65530, "b.Len()")
+	check.TrueMsg(t, b.Has(65530), "b.Has(65530)")
+	check.TrueMsg(t, b.Has(100000), "b.Has(100000)")
+	check.TrueMsg(t, b.Has(131079), "b.Has(131079)")
+	check.FalseMsg(t, b.Has(131080), "b.Has(131080)")
+	check.FalseMsg(t, b.Has(65529), "b.Has(65529)")
+
+	b.Remove(100000)
+	check.FalseMsg(t, b.Has(100000), "b.Has(100000): after removing")
+	check.TrueMsg(t, b.Has(100001), "b.Has(100001): after removing")
+	check.EqMsg(t, b.Len(), 1+131080-65530, "b.Len(): after removing")
+
+	b.Add(100000)
+	check.EqMsg(t, b.Len(), 2+131080-65530, "b.Len(): after re-adding")
+}
+
+func TestBitmapOptimize(t *testing.T) {
+	b := &roaring.Bitmap{}
+	for i := 0; i < 50000; i++ {
+		b.Add(i)
+	}
+	before, _ := b.MarshalBinary()
+
+	b.Optimize()
+	after, _ := b.MarshalBinary()
+
+	check.EqMsg(t, b.Len(), 50000, "b.Len()")
+	if len(after) >= len(before) {
+		t.Errorf("Optimize didn't reduce size: before %d bytes, after %d bytes", len(before), len(after))
+	}
+}
+
+func TestBitmapSetAlgebra(t *testing.T) {
+	a := func() *roaring.Bitmap { return roaring.Of(1, 2, 3, 100000) }
+	b := roaring.Of(2, 3, 4, 200000)
+
+	s := a()
+	s.Union(b)
+	check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), []int{1, 2, 3, 4, 100000, 200000}, "a ∪ b")
+
+	s = a()
+	s.Intersection(b)
+	check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), []int{2, 3}, "a ∩ b")
+
+	s = a()
+	s.Difference(b)
+	check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), []int{1, 100000}, "a - b")
+
+	s = a()
+	s.SymmetricDifference(b)
+	check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), []int{1, 4, 100000, 200000}, "a △ b")
+
+	check.TrueMsg(t, roaring.Of(1, 2).IsDisjoint(roaring.Of(3, 300000)), "{1, 2} disjoint {3, 300000}")
+	check.FalseMsg(t, roaring.Of(1, 300000).IsDisjoint(roaring.Of(3, 300000)), "{1, 300000} disjoint {3, 300000}")
+	check.TrueMsg(t, roaring.Of(2, 3).IsSubset(a()), "{2, 3} ⊆ a")
+	check.FalseMsg(t, roaring.Of(2, 300000).IsSubset(a()), "{2, 300000} ⊆ a")
+	check.TrueMsg(t, a().IsSuperset(roaring.Of(1, 100000)), "a ⊇ {1, 100000}")
+	check.FalseMsg(t, a().IsSuperset(b), "a ⊇ b")
+	check.TrueMsg(t, a().Equal(a()), "a == a")
+	check.FalseMsg(t, a().Equal(b), "a == b")
+}
+
+// randomBitmap generates a bitmap with a mix of sparse, dense and run-heavy chunks.
+func randomBitmap(r *rand.Rand) (*roaring.Bitmap, set.Set[int]) {
+	b := &roaring.Bitmap{}
+	s := make(set.Set[int])
+
+	for chunk := 0; chunk < 4; chunk++ {
+		base := chunk << 16
+		switch r.Intn(4) {
+		case 0:
+			// Sparse
+			for i := 0; i < 100; i++ {
+				x := base + r.Intn(1<<16)
+				b.Add(x)
+				s.Add(x)
+			}
+		case 1:
+			// Dense
+			for i := 0; i < 10000; i++ {
+				x := base + r.Intn(1<<14)
+				b.Add(x)
+				s.Add(x)
+			}
+		case 2:
+			// Runs
+			for i := 0; i < 5; i++ {
+				lo := base + r.Intn(1<<16)
+				hi := lo + r.Intn(2000)
+				if hi > base+1<<16 {
+					hi = base + 1<<16
+				}
+				b.AddRange(lo, hi)
+				for x := lo; x < hi; x++ {
+					s.Add(x)
+				}
+			}
+		}
+	}
+
+	if r.Intn(2) == 0 {
+		b.Optimize()
+	}
+	return b, s
+}
+
+func elements(s set.Set[int]) []int {
+	e := iter.IntoSlice(s.Iter())
+	slices.Sort(e)
+	return e
+}
+
+func TestBitmapRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 30; i++ {
+		a, aSet := randomBitmap(r)
+		b, bSet := randomBitmap(r)
+
+		check.DeepEqMsg(t, iter.IntoSlice(a.Iter()), elements(aSet), "a.Iter()")
+		check.EqMsg(t, a.Len(), aSet.Len(), "a.Len()")
+
+		s, expected := a.Clone(), aSet.Clone()
+		s.Union(b)
+		expected.Union(bSet)
+		check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), elements(expected), "a ∪ b")
+
+		s, expected = a.Clone(), aSet.Clone()
+		s.Intersection(b)
+		expected.Intersection(bSet)
+		check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), elements(expected), "a ∩ b")
+		check.EqMsg(t, a.IsDisjoint(b), expected.Len() == 0, "a.IsDisjoint(b)")
+
+		s, expected = a.Clone(), aSet.Clone()
+		s.Difference(b)
+		expected.Difference(bSet)
+		check.DeepEqMsg(t, iter.IntoSlice(s.Iter()), elements(expected), "a - b")
+		check.TrueMsg(t, s.IsSubset(a), "(a - b).IsSubset(a)")
+
+		s = a.Clone()
+		s.SymmetricDifference(b)
+		check.EqMsg(t, s.Len(), aSet.Len()+bSet.Len()-2*(aSet.Len()-expected.Len()), "(a △ b).Len()")
+
+		data, err := a.MarshalBinary()
+		assert.NoErr(t, err)
+		decoded := &roaring.Bitmap{}
+		assert.NoErr(t, decoded.UnmarshalBinary(data))
+		check.TrueMsg(t, decoded.Equal(a), "decoded.Equal(a)")
+	}
+}
+
+func TestBitmapMarshalBinary(t *testing.T) {
+	data, err := roaring.Of(1, 2, 3).MarshalBinary()
+	assert.NoErr(t, err)
+	check.DeepEqMsg(
+		t,
+		data,
+		[]byte{
+			0x3A, 0x30, 0, 0, // cookie
+			1, 0, 0, 0, // number of containers
+			0, 0, 2, 0, // key, cardinality - 1
+			16, 0, 0, 0, // offset
+			1, 0, 2, 0, 3, 0, // array container
+		},
+		"Of(1, 2, 3).MarshalBinary()",
+	)
+
+	b := &roaring.Bitmap{}
+	b.AddRange(10, 20)
+	data, err = b.MarshalBinary()
+	assert.NoErr(t, err)
+	check.DeepEqMsg(
+		t,
+		data,
+		[]byte{
+			0x3B, 0x30, 0, 0, // cookie, number of containers - 1
+			1,          // run flags
+			0, 0, 9, 0, // key, cardinality - 1
+			1, 0, 10, 0, 9, 0, // run container
+		},
+		"{[10, 20)}.MarshalBinary()",
+	)
+
+	data, err = (&roaring.Bitmap{}).MarshalBinary()
+	assert.NoErr(t, err)
+	decoded := roaring.Of(1)
+	assert.NoErr(t, decoded.UnmarshalBinary(data))
+	check.EqMsg(t, decoded.Len(), 0, "decoded empty bitmap: Len()")
+}
+
+func TestBitmapUnmarshalBinaryInvalid(t *testing.T) {
+	valid, _ := roaring.Of(1, 2, 3).MarshalBinary()
+	b := &roaring.Bitmap{}
+
+	check.SpecificErrMsg(t, b.UnmarshalBinary(nil), roaring.ErrInvalidData, "nil")
+	check.SpecificErrMsg(t, b.UnmarshalBinary([]byte{1, 2, 3, 4}), roaring.ErrInvalidData, "invalid cookie")
+	check.SpecificErrMsg(t, b.UnmarshalBinary(valid[:len(valid)-1]), roaring.ErrInvalidData, "truncated")
+	check.SpecificErrMsg(t, b.UnmarshalBinary(append(valid, 0)), roaring.ErrInvalidData, "trailing data")
+
+	unsorted := append([]byte(nil), valid...)
+	unsorted[len(unsorted)-2] = 1
+	check.SpecificErrMsg(t, b.UnmarshalBinary(unsorted), roaring.ErrInvalidData, "unsorted array")
+}
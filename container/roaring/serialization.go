@@ -0,0 +1,252 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package roaring
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrInvalidData is returned when unmarshaling malformed binary data.
+var ErrInvalidData = errors.New("roaring: invalid binary data")
+
+// Constants from the [Roaring format specification].
+//
+// [Roaring format specification]: https://github.com/RoaringBitmap/RoaringFormatSpec
+const (
+	serialCookieNoRuns = 12346
+	serialCookie       = 12347
+	noOffsetThreshold  = 4
+)
+
+// MarshalBinary implements [encoding.BinaryMarshaler].
+//
+// The output follows the portable [Roaring format specification], shared by
+// Roaring implementations in other languages.
+//
+// [Roaring format specification]: https://github.com/RoaringBitmap/RoaringFormatSpec
+func (b *Bitmap) MarshalBinary() ([]byte, error) {
+	n := len(b.containers)
+	hasRuns := false
+	for _, c := range b.containers {
+		if _, isRun := c.(*runContainer); isRun {
+			hasRuns = true
+			break
+		}
+	}
+
+	// Header
+	var data []byte
+	if hasRuns {
+		data = make([]byte, 4)
+		binary.LittleEndian.PutUint32(data, serialCookie|uint32(n-1)<<16)
+
+		runFlags := make([]byte, (n+7)/8)
+		for i, c := range b.containers {
+			if _, isRun := c.(*runContainer); isRun {
+				runFlags[i/8] |= 1 << (i % 8)
+			}
+		}
+		data = append(data, runFlags...)
+	} else {
+		data = make([]byte, 8)
+		binary.LittleEndian.PutUint32(data[0:4], serialCookieNoRuns)
+		binary.LittleEndian.PutUint32(data[4:8], uint32(n))
+	}
+
+	// Descriptive header
+	for i, c := range b.containers {
+		data = appendUint16(data, b.keys[i])
+		data = appendUint16(data, uint16(c.cardinality()-1))
+	}
+
+	// Offset header
+	hasOffsets := !hasRuns || n >= noOffsetThreshold
+	offsetsStart := len(data)
+	if hasOffsets {
+		data = append(data, make([]byte, 4*n)...)
+	}
+
+	// Containers
+	for i, c := range b.containers {
+		if hasOffsets {
+			binary.LittleEndian.PutUint32(data[offsetsStart+4*i:], uint32(len(data)))
+		}
+
+		switch c := c.(type) {
+		case *runContainer:
+			data = appendUint16(data, uint16(len(c.runs)))
+			for _, r := range c.runs {
+				data = appendUint16(data, r.start)
+				data = appendUint16(data, r.last-r.start)
+			}
+
+		default:
+			// Array and bitmap containers are distinguished by cardinality
+			if c.cardinality() <= arrayMaxSize {
+				for x, ok := c.next(0); ok; x, ok = c.next(int(x) + 1) {
+					data = appendUint16(data, x)
+				}
+			} else {
+				for _, w := range c.bitmap().words {
+					data = appendUint64(data, w)
+				}
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler],
+// reading data in the portable [Roaring format specification].
+//
+// Returns [ErrInvalidData] if the data is malformed.
+//
+// [Roaring format specification]: https://github.com/RoaringBitmap/RoaringFormatSpec
+func (b *Bitmap) UnmarshalBinary(data []byte) error {
+	r := reader{data: data}
+
+	// Header
+	var n int
+	var runFlags []byte
+	cookie := r.uint32()
+	switch {
+	case cookie&0xFFFF == serialCookie:
+		n = int(cookie>>16) + 1
+		runFlags = r.bytes((n + 7) / 8)
+	case cookie == serialCookieNoRuns:
+		n = int(r.uint32())
+	default:
+		return ErrInvalidData
+	}
+
+	if r.failed || n > chunkSize {
+		return ErrInvalidData
+	}
+
+	// Descriptive header
+	keys := make([]uint16, n)
+	cardinalities := make([]int, n)
+	for i := range keys {
+		keys[i] = r.uint16()
+		cardinalities[i] = int(r.uint16()) + 1
+		if i > 0 && keys[i] <= keys[i-1] {
+			return ErrInvalidData
+		}
+	}
+
+	// Offset header - ignored, as containers are read sequentially
+	if runFlags == nil || n >= noOffsetThreshold {
+		r.bytes(4 * n)
+	}
+
+	// Containers
+	containers := make([]container, n)
+	for i := range containers {
+		var c container
+		isRun := runFlags != nil && runFlags[i/8]&(1<<(i%8)) != 0
+
+		switch {
+		case isRun:
+			c = r.runContainer()
+		case cardinalities[i] <= arrayMaxSize:
+			c = r.arrayContainer(cardinalities[i])
+		default:
+			c = r.bitmapContainer()
+		}
+
+		if r.failed || c.cardinality() != cardinalities[i] {
+			return ErrInvalidData
+		}
+		containers[i] = c
+	}
+
+	if r.failed || len(r.data) != 0 {
+		return ErrInvalidData
+	}
+
+	b.keys, b.containers = keys, containers
+	return nil
+}
+
+func appendUint16(b []byte, x uint16) []byte {
+	return append(b, byte(x), byte(x>>8))
+}
+
+func appendUint64(b []byte, x uint64) []byte {
+	return append(b, byte(x), byte(x>>8), byte(x>>16), byte(x>>24),
+		byte(x>>32), byte(x>>40), byte(x>>48), byte(x>>56))
+}
+
+// reader helps with parsing little-endian binary data,
+// remembering whether any read was out-of-bounds.
+type reader struct {
+	data   []byte
+	failed bool
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.failed || len(r.data) < n {
+		r.failed = true
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) uint16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) uint32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) uint64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *reader) arrayContainer(card int) container {
+	a := &arrayContainer{values: make([]uint16, card)}
+	for i := range a.values {
+		a.values[i] = r.uint16()
+		if i > 0 && a.values[i] <= a.values[i-1] {
+			r.failed = true
+		}
+	}
+	return a
+}
+
+func (r *reader) bitmapContainer() container {
+	b := &bitmapContainer{}
+	for i := range b.words {
+		b.words[i] = r.uint64()
+	}
+	b.recount()
+	return b
+}
+
+func (r *reader) runContainer() container {
+	c := &runContainer{runs: make([]run, r.uint16())}
+	for i := range c.runs {
+		start := r.uint16()
+		length := r.uint16()
+		if int(start)+int(length) >= chunkSize || (i > 0 && int(start) <= int(c.runs[i-1].last)+1) {
+			r.failed = true
+		}
+		c.runs[i] = run{start, start + length}
+	}
+	return c
+}
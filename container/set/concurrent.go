@@ -0,0 +1,156 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package set
+
+import (
+	"github.com/MKuranowski/go-extra-lib/container/syncmap"
+	"github.com/MKuranowski/go-extra-lib/iter"
+)
+
+// ConcurrentSet is an unordered collection of elements, which is safe for concurrent use
+// by multiple goroutines. Its methods mirror those of [Set].
+//
+// The representation uses a [syncmap.ConcurrentMap], with elements spread between
+// independently-locked shards.
+//
+// The zero value is an empty set ready to use. A ConcurrentSet must not be copied after first use.
+//
+// Operations on a single element are atomic. Operations involving every element
+// lock one shard at a time, and are not atomic if the set is concurrently modified.
+type ConcurrentSet[T comparable] struct {
+	m syncmap.ConcurrentMap[T, struct{}]
+}
+
+// NewConcurrent returns a [ConcurrentSet] with the provided elements.
+func NewConcurrent[T comparable](elements ...T) *ConcurrentSet[T] {
+	s := &ConcurrentSet[T]{}
+	for _, x := range elements {
+		s.Add(x)
+	}
+	return s
+}
+
+// Has returns true if the provided element is in the set.
+//
+// Average complexity: constant
+func (s *ConcurrentSet[T]) Has(x T) bool { return s.m.Has(x) }
+
+// Add ensures given element is in the set.
+//
+// Average complexity: constant
+func (s *ConcurrentSet[T]) Add(x T) { s.m.Store(x, struct{}{}) }
+
+// AddIfAbsent adds the element to the set, and returns true
+// if it wasn't already present. Exactly one of concurrent calls to AddIfAbsent
+// with the same element returns true.
+//
+// Average complexity: constant
+func (s *ConcurrentSet[T]) AddIfAbsent(x T) bool {
+	_, loaded := s.m.LoadOrStore(x, struct{}{})
+	return !loaded
+}
+
+// Remove ensures given element is not in the set.
+//
+// Average complexity: constant
+func (s *ConcurrentSet[T]) Remove(x T) { s.m.Delete(x) }
+
+// RemoveIfPresent removes the element from the set, and returns true
+// if it was present. Exactly one of concurrent calls to RemoveIfPresent
+// with the same element returns true.
+//
+// Average complexity: constant
+func (s *ConcurrentSet[T]) RemoveIfPresent(x T) bool {
+	_, loaded := s.m.LoadAndDelete(x)
+	return loaded
+}
+
+// Len returns the number of elements in the set.
+//
+// Average complexity: linear in terms of the number of shards
+func (s *ConcurrentSet[T]) Len() int { return s.m.Len() }
+
+// Clear ensures no elements are presents in the set.
+//
+// Average complexity: linear
+func (s *ConcurrentSet[T]) Clear() { s.m.Clear() }
+
+// Clone returns a shallow copy of the set.
+//
+// Average complexity: linear
+func (s *ConcurrentSet[T]) Clone() *ConcurrentSet[T] {
+	n := &ConcurrentSet[T]{}
+	for x := range s.m.Snapshot() {
+		n.Add(x)
+	}
+	return n
+}
+
+// Snapshot returns a copy of the set's elements as a [Set].
+//
+// Average complexity: linear
+func (s *ConcurrentSet[T]) Snapshot() Set[T] { return s.m.Snapshot() }
+
+// Equal returns true if s1 and s2 contain the same elements.
+//
+// Average complexity: linear
+func (s1 *ConcurrentSet[T]) Equal(s2 Set[T]) bool { return s1.Snapshot().Equal(s2) }
+
+// Union ensures every element from s2 is also present in s1.
+//
+// Average complexity: linear in terms of len(s2).
+func (s1 *ConcurrentSet[T]) Union(s2 Set[T]) {
+	for x := range s2 {
+		s1.Add(x)
+	}
+}
+
+// Intersection ensures s1 only contains elements also present in s2.
+//
+// Average complexity: linear in terms of s1.Len().
+func (s1 *ConcurrentSet[T]) Intersection(s2 Set[T]) {
+	s1.m.DeleteFunc(func(x T, _ struct{}) bool { return !s2.Has(x) })
+}
+
+// Difference ensures s1 only contains elements not present in s2.
+//
+// Average complexity: linear in terms of len(s2).
+func (s1 *ConcurrentSet[T]) Difference(s2 Set[T]) {
+	for x := range s2 {
+		s1.Remove(x)
+	}
+}
+
+// IsDisjoint returns true if s1 and s2 have no elements in common.
+//
+// Average complexity: linear in terms of len(s2).
+func (s1 *ConcurrentSet[T]) IsDisjoint(s2 Set[T]) bool {
+	for x := range s2 {
+		if s1.Has(x) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubset returns true if s2 contains every element from s1.
+//
+// Average complexity: linear in terms of s1.Len().
+func (s1 *ConcurrentSet[T]) IsSubset(s2 Set[T]) bool { return s1.Snapshot().IsSubset(s2) }
+
+// IsSuperset returns true if s1 contains every element of s2.
+//
+// Average complexity: linear in terms of len(s2).
+func (s1 *ConcurrentSet[T]) IsSuperset(s2 Set[T]) bool {
+	for x := range s2 {
+		if !s1.Has(x) {
+			return false
+		}
+	}
+	return true
+}
+
+// Iter returns an [iter.Iterator] over a [ConcurrentSet.Snapshot] of the set.
+// Modifications made to the set after Iter returns are not visible through the iterator.
+func (s *ConcurrentSet[T]) Iter() iter.Iterator[T] { return s.m.Keys() }
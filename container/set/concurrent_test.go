@@ -0,0 +1,98 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package set_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/MKuranowski/go-extra-lib/container/set"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+	"golang.org/x/exp/slices"
+)
+
+func TestConcurrentSetAddHasLenRemove(t *testing.T) {
+	s := &ConcurrentSet[int]{}
+	check.EqMsg(t, s.Len(), 0, "s.Len(): empty set")
+
+	s.Add(2)
+	s.Add(3)
+	check.TrueMsg(t, s.AddIfAbsent(5), "s.AddIfAbsent(5)")
+	check.FalseMsg(t, s.AddIfAbsent(5), "s.AddIfAbsent(5): duplicate")
+
+	check.EqMsg(t, s.Len(), 3, "s.Len(): after adding")
+	check.TrueMsg(t, s.Has(2), "s.Has(2)")
+	check.FalseMsg(t, s.Has(4), "s.Has(4)")
+
+	s.Remove(2)
+	check.TrueMsg(t, s.RemoveIfPresent(3), "s.RemoveIfPresent(3)")
+	check.FalseMsg(t, s.RemoveIfPresent(3), "s.RemoveIfPresent(3): already removed")
+	check.TrueMsg(t, s.Equal(Set[int]{5: {}}), "s.Equal({5})")
+
+	s.Clear()
+	check.EqMsg(t, s.Len(), 0, "s.Len(): after clearing")
+}
+
+func TestConcurrentSetOperations(t *testing.T) {
+	a := func() *ConcurrentSet[int] { return NewConcurrent(1, 2, 3, 4) }
+	b := Set[int]{3: {}, 4: {}, 5: {}}
+
+	s := a()
+	s.Union(b)
+	check.TrueMsg(t, s.Equal(Set[int]{1: {}, 2: {}, 3: {}, 4: {}, 5: {}}), "a ∪ b")
+
+	s = a()
+	s.Intersection(b)
+	check.TrueMsg(t, s.Equal(Set[int]{3: {}, 4: {}}), "a ∩ b")
+
+	s = a()
+	s.Difference(b)
+	check.TrueMsg(t, s.Equal(Set[int]{1: {}, 2: {}}), "a - b")
+
+	check.FalseMsg(t, a().IsDisjoint(b), "a.IsDisjoint(b)")
+	check.TrueMsg(t, a().IsDisjoint(Set[int]{7: {}}), "a.IsDisjoint({7})")
+	check.TrueMsg(t, NewConcurrent(3, 4).IsSubset(b), "{3, 4} ⊆ b")
+	check.FalseMsg(t, a().IsSubset(b), "a ⊆ b")
+	check.TrueMsg(t, a().IsSuperset(Set[int]{1: {}, 4: {}}), "a ⊇ {1, 4}")
+	check.FalseMsg(t, a().IsSuperset(b), "a ⊇ b")
+
+	original := a()
+	clone := original.Clone()
+	clone.Add(10)
+	check.FalseMsg(t, original.Has(10), "original.Has(10): after adding to clone")
+
+	it := original.Iter()
+	original.Add(20)
+	elements := iter.IntoSlice(it)
+	slices.Sort(elements)
+	check.DeepEqMsg(t, elements, []int{1, 2, 3, 4}, "original.Iter()")
+}
+
+func TestConcurrentSetConcurrentAddIfAbsent(t *testing.T) {
+	const goroutines = 8
+	const elements = 1000
+
+	s := &ConcurrentSet[int]{}
+	var added int64
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+
+	for g := 0; g < goroutines; g++ {
+		go func() {
+			defer wg.Done()
+			for i := 0; i < elements; i++ {
+				if s.AddIfAbsent(i) {
+					atomic.AddInt64(&added, 1)
+				}
+				s.Has(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	check.EqMsg(t, added, int64(elements), "successful AddIfAbsent calls")
+	check.EqMsg(t, s.Len(), elements, "s.Len()")
+}
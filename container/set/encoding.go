@@ -0,0 +1,163 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package set
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// compareValues compares two elements of the same type. Numbers, strings and booleans
+// are compared naturally; other values are compared by their encoded form.
+func compareValues(a, b reflect.Value, aEncoded, bEncoded []byte) int {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return compareOrdered(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return compareOrdered(a.Float(), b.Float())
+	case reflect.String:
+		return compareOrdered(a.String(), b.String())
+	case reflect.Bool:
+		return compareOrdered(boolToInt(a.Bool()), boolToInt(b.Bool()))
+	default:
+		return bytes.Compare(aEncoded, bEncoded)
+	}
+}
+
+func compareOrdered[T constraints.Ordered](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type encodedElement[T any] struct {
+	value   T
+	encoded []byte
+}
+
+// encodeSorted encodes every element with the provided function,
+// and returns the elements in a deterministic order.
+func (s Set[T]) encodeSorted(encode func(T) ([]byte, error)) ([]encodedElement[T], error) {
+	elements := make([]encodedElement[T], 0, len(s))
+	for x := range s {
+		encoded, err := encode(x)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, encodedElement[T]{x, encoded})
+	}
+
+	slices.SortFunc(elements, func(a, b encodedElement[T]) bool {
+		return compareValues(reflect.ValueOf(&a.value).Elem(), reflect.ValueOf(&b.value).Elem(), a.encoded, b.encoded) < 0
+	})
+	return elements, nil
+}
+
+// MarshalJSON implements [json.Marshaler]. Sets are encoded as JSON arrays.
+//
+// To ensure deterministic output, elements are sorted - numbers, strings and booleans
+// are sorted by their values, while other elements are sorted by their JSON representation.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+
+	elements, err := s.encodeSorted(func(x T) ([]byte, error) { return json.Marshal(x) })
+	if err != nil {
+		return nil, err
+	}
+
+	b := []byte{'['}
+	for i, e := range elements {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, e.encoded...)
+	}
+	return append(b, ']'), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. Sets are decoded from JSON arrays,
+// and decoded elements are added to the set. A null value sets the set to nil.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		*s = nil
+		return nil
+	}
+
+	var elements []T
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+
+	if *s == nil {
+		*s = make(Set[T], len(elements))
+	}
+	for _, x := range elements {
+		s.Add(x)
+	}
+	return nil
+}
+
+// GobEncode implements [gob.GobEncoder]. Sets are encoded as a slice of elements.
+//
+// To ensure deterministic output, elements are sorted - numbers, strings and booleans
+// are sorted by their values, while other elements are sorted by their gob encoding.
+// As gob follows pointers, this also holds for pointers and structs containing pointers.
+func (s Set[T]) GobEncode() ([]byte, error) {
+	elements, err := s.encodeSorted(func(x T) ([]byte, error) {
+		var b bytes.Buffer
+		err := gob.NewEncoder(&b).Encode(x)
+		return b.Bytes(), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]T, len(elements))
+	for i, e := range elements {
+		values[i] = e.value
+	}
+
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(values); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// GobDecode implements [gob.GobDecoder]. Decoded elements are added to the set.
+func (s *Set[T]) GobDecode(data []byte) error {
+	var elements []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&elements); err != nil {
+		return err
+	}
+
+	if *s == nil {
+		*s = make(Set[T], len(elements))
+	}
+	for _, x := range elements {
+		s.Add(x)
+	}
+	return nil
+}
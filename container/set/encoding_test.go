@@ -0,0 +1,100 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package set_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/container/set"
+	"github.com/MKuranowski/go-extra-lib/testing2/assert"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+type point struct {
+	X, Y int
+}
+
+func TestSetMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(set.Set[int]{10: {}, 9: {}, -1: {}, 100: {}})
+	assert.NoErr(t, err)
+	check.EqMsg(t, string(data), "[-1,9,10,100]", "json.Marshal(Set[int])")
+
+	data, err = json.Marshal(set.Set[string]{"b": {}, "a": {}, "c": {}})
+	assert.NoErr(t, err)
+	check.EqMsg(t, string(data), `["a","b","c"]`, "json.Marshal(Set[string])")
+
+	data, err = json.Marshal(set.Set[point]{{2, 1}: {}, {1, 2}: {}})
+	assert.NoErr(t, err)
+	check.EqMsg(t, string(data), `[{"X":1,"Y":2},{"X":2,"Y":1}]`, "json.Marshal(Set[point])")
+
+	data, err = json.Marshal(make(set.Set[int]))
+	assert.NoErr(t, err)
+	check.EqMsg(t, string(data), "[]", "json.Marshal(empty set)")
+
+	data, err = json.Marshal(struct{ S set.Set[int] }{})
+	assert.NoErr(t, err)
+	check.EqMsg(t, string(data), `{"S":null}`, "json.Marshal(nil set)")
+}
+
+func TestSetUnmarshalJSON(t *testing.T) {
+	var s set.Set[int]
+	assert.NoErr(t, json.Unmarshal([]byte("[3, 1, 2, 1]"), &s))
+	check.TrueMsg(t, s.Equal(set.Set[int]{1: {}, 2: {}, 3: {}}), "json.Unmarshal([3, 1, 2, 1])")
+
+	assert.NoErr(t, json.Unmarshal([]byte("null"), &s))
+	check.TrueMsg(t, s == nil, "json.Unmarshal(null)")
+
+	check.ErrMsg(t, json.Unmarshal([]byte(`["a"]`), &s), `json.Unmarshal(["a"])`)
+
+	var points set.Set[point]
+	assert.NoErr(t, json.Unmarshal([]byte(`[{"X":1,"Y":2}]`), &points))
+	check.TrueMsg(t, points.Has(point{1, 2}), "points.Has({1, 2})")
+}
+
+func TestSetGob(t *testing.T) {
+	original := set.Set[string]{"foo": {}, "bar": {}, "baz": {}}
+
+	var buf bytes.Buffer
+	assert.NoErr(t, gob.NewEncoder(&buf).Encode(original))
+	encoded := append([]byte(nil), buf.Bytes()...)
+
+	var decoded set.Set[string]
+	assert.NoErr(t, gob.NewDecoder(&buf).Decode(&decoded))
+	check.TrueMsg(t, decoded.Equal(original), "gob round trip")
+
+	// Encoding is deterministic
+	for i := 0; i < 10; i++ {
+		buf.Reset()
+		assert.NoErr(t, gob.NewEncoder(&buf).Encode(original.Clone()))
+		check.DeepEqMsg(t, buf.Bytes(), encoded, "gob encoding")
+	}
+
+	type wrapper struct{ S set.Set[int] }
+	buf.Reset()
+	assert.NoErr(t, gob.NewEncoder(&buf).Encode(wrapper{set.Set[int]{1: {}, 2: {}}}))
+	var w wrapper
+	assert.NoErr(t, gob.NewDecoder(&buf).Decode(&w))
+	check.TrueMsg(t, w.S.Equal(set.Set[int]{1: {}, 2: {}}), "gob round trip in a struct")
+}
+
+func TestSetGobPointersDeterministic(t *testing.T) {
+	encode := func() []byte {
+		s := set.Set[*point]{}
+		for i := 0; i < 20; i++ {
+			s.Add(&point{i, -i})
+		}
+
+		var buf bytes.Buffer
+		assert.NoErr(t, gob.NewEncoder(&buf).Encode(s))
+		return buf.Bytes()
+	}
+
+	expected := encode()
+	for i := 0; i < 10; i++ {
+		check.DeepEqMsg(t, encode(), expected, "gob encoding of *point set")
+	}
+}
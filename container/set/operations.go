@@ -0,0 +1,208 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package set
+
+import (
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// Of returns a set containing all the provided elements.
+// To create a set from a slice, use Of(slice...).
+func Of[T comparable](xs ...T) Set[T] {
+	s := make(Set[T], len(xs))
+	for _, x := range xs {
+		s[x] = struct{}{}
+	}
+	return s
+}
+
+// FromIter returns a set with all elements generated by an iterator.
+// Returns nil and the error if the iterator fails.
+//
+//	FromIter(iter.OverString("hello")) → {'h', 'e', 'l', 'o'}
+func FromIter[T comparable](i iter.Iterator[T]) (Set[T], error) {
+	s := make(Set[T])
+	for i.Next() {
+		s[i.Get()] = struct{}{}
+	}
+	if err := i.Err(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Sorted returns all elements of the set in a slice, in ascending order.
+//
+// Average complexity: O(n log n)
+func Sorted[T constraints.Ordered](s Set[T]) []T {
+	r := make([]T, 0, len(s))
+	for x := range s {
+		r = append(r, x)
+	}
+	slices.Sort(r)
+	return r
+}
+
+// SortedFunc returns all elements of the set in a slice, ordered by the provided less function.
+//
+// Average complexity: O(n log n)
+func SortedFunc[T comparable](s Set[T], less func(a, b T) bool) []T {
+	r := make([]T, 0, len(s))
+	for x := range s {
+		r = append(r, x)
+	}
+	slices.SortFunc(r, less)
+	return r
+}
+
+// smallest returns the index of the set with the fewest elements.
+func smallest[T comparable](sets []Set[T]) int {
+	idx := 0
+	for i, s := range sets {
+		if len(s) < len(sets[idx]) {
+			idx = i
+		}
+	}
+	return idx
+}
+
+// inAll returns true if x is present in every set, except for the one at index skip.
+func inAll[T comparable](sets []Set[T], skip int, x T) bool {
+	for i, s := range sets {
+		if i != skip && !s.Has(x) {
+			return false
+		}
+	}
+	return true
+}
+
+// inAny returns true if x is present in any of the provided sets.
+func inAny[T comparable](sets []Set[T], x T) bool {
+	for _, s := range sets {
+		if s.Has(x) {
+			return true
+		}
+	}
+	return false
+}
+
+// countIn returns the number of sets containing x.
+func countIn[T comparable](sets []Set[T], x T) (n int) {
+	for _, s := range sets {
+		if s.Has(x) {
+			n++
+		}
+	}
+	return
+}
+
+// Union returns a new set with elements present in any of the provided sets.
+//
+// Average complexity: linear in terms of the total number of elements.
+func Union[T comparable](sets ...Set[T]) Set[T] {
+	r := make(Set[T])
+	for _, s := range sets {
+		r.Union(s)
+	}
+	return r
+}
+
+// Intersect returns a new set with elements present in all of the provided sets.
+// Returns an empty set if no sets are provided.
+//
+// Average complexity: O(k * m), where k is the number of sets and m the size of the smallest set.
+func Intersect[T comparable](sets ...Set[T]) Set[T] {
+	r := make(Set[T])
+	if len(sets) == 0 {
+		return r
+	}
+
+	idx := smallest(sets)
+	for x := range sets[idx] {
+		if inAll(sets, idx, x) {
+			r.Add(x)
+		}
+	}
+	return r
+}
+
+// Difference returns a new set with elements from s which are not present in any of the other sets.
+//
+// Average complexity: O(k * len(s)), where k is the number of other sets.
+func Difference[T comparable](s Set[T], others ...Set[T]) Set[T] {
+	r := make(Set[T])
+	for x := range s {
+		if !inAny(others, x) {
+			r.Add(x)
+		}
+	}
+	return r
+}
+
+// SymmetricDifference returns a new set with elements present in an odd number of the provided sets.
+// For two sets, these are the elements present in exactly one of them.
+//
+// Average complexity: O(k * n), where k is the number of sets and n is the total number of elements.
+func SymmetricDifference[T comparable](sets ...Set[T]) Set[T] {
+	r := make(Set[T])
+	for _, s := range sets {
+		for x := range s {
+			if countIn(sets, x)%2 == 1 {
+				r.Add(x)
+			}
+		}
+	}
+	return r
+}
+
+// IterUnion returns an iterator over elements present in any of the provided sets,
+// without allocating the resulting set. Every element is generated exactly once.
+//
+// The sets must not be modified while the iterator is in use.
+func IterUnion[T comparable](sets ...Set[T]) iter.Iterator[T] {
+	its := make([]iter.Iterator[T], len(sets))
+	for i, s := range sets {
+		earlier := sets[:i]
+		its[i] = iter.Filter(s.Iter(), func(x T) bool { return !inAny(earlier, x) })
+	}
+	return iter.Chain(its...)
+}
+
+// IterIntersect returns an iterator over elements present in all of the provided sets,
+// without allocating the resulting set.
+//
+// The sets must not be modified while the iterator is in use.
+func IterIntersect[T comparable](sets ...Set[T]) iter.Iterator[T] {
+	if len(sets) == 0 {
+		return iter.Empty[T]()
+	}
+
+	idx := smallest(sets)
+	return iter.Filter(sets[idx].Iter(), func(x T) bool { return inAll(sets, idx, x) })
+}
+
+// IterDifference returns an iterator over elements of s which are not present
+// in any of the other sets, without allocating the resulting set.
+//
+// The sets must not be modified while the iterator is in use.
+func IterDifference[T comparable](s Set[T], others ...Set[T]) iter.Iterator[T] {
+	return iter.Filter(s.Iter(), func(x T) bool { return !inAny(others, x) })
+}
+
+// IterSymmetricDifference returns an iterator over elements present in an odd number
+// of the provided sets, without allocating the resulting set. Every element is generated exactly once.
+//
+// The sets must not be modified while the iterator is in use.
+func IterSymmetricDifference[T comparable](sets ...Set[T]) iter.Iterator[T] {
+	its := make([]iter.Iterator[T], len(sets))
+	for i, s := range sets {
+		earlier := sets[:i]
+		its[i] = iter.Filter(s.Iter(), func(x T) bool {
+			return !inAny(earlier, x) && countIn(sets, x)%2 == 1
+		})
+	}
+	return iter.Chain(its...)
+}
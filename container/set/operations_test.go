@@ -0,0 +1,83 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package set_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/MKuranowski/go-extra-lib/container/set"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/assert"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func TestOfAndFromIter(t *testing.T) {
+	check.DeepEqMsg(t, Of(1, 2, 2, 3), Set[int]{1: {}, 2: {}, 3: {}}, "Of(1, 2, 2, 3)")
+	check.DeepEqMsg(t, Of([]string{"a", "b"}...), Set[string]{"a": {}, "b": {}}, "Of(slice...)")
+
+	s, err := FromIter(iter.OverString("hello"))
+	assert.NoErr(t, err)
+	check.DeepEqMsg(t, s, Of('h', 'e', 'l', 'o'), "FromIter(hello)")
+
+	testErr := errors.New("test error")
+	s, err = FromIter(iter.Error[rune](testErr))
+	check.SpecificErrMsg(t, err, testErr, "FromIter(error)")
+	check.TrueMsg(t, s == nil, "FromIter(error): nil set")
+}
+
+func TestSorted(t *testing.T) {
+	check.DeepEqMsg(t, Sorted(Of(10, -1, 5, 3)), []int{-1, 3, 5, 10}, "Sorted(ints)")
+	check.DeepEqMsg(t, Sorted(Of("b", "c", "a")), []string{"a", "b", "c"}, "Sorted(strings)")
+	check.DeepEqMsg(t, Sorted(Set[int]{}), []int{}, "Sorted(empty)")
+	check.DeepEqMsg(
+		t,
+		SortedFunc(Of(10, -1, 5, 3), func(a, b int) bool { return a > b }),
+		[]int{10, 5, 3, -1},
+		"SortedFunc(ints, greater)",
+	)
+}
+
+func TestNaryOperations(t *testing.T) {
+	a := Of(1, 2, 3, 4)
+	b := Of(3, 4, 5, 6)
+	c := Of(4, 6, 7)
+
+	check.DeepEqMsg(t, Sorted(Union(a, b, c)), []int{1, 2, 3, 4, 5, 6, 7}, "Union(a, b, c)")
+	check.DeepEqMsg(t, Sorted(Union[int]()), []int{}, "Union()")
+	check.DeepEqMsg(t, Sorted(Intersect(a, b, c)), []int{4}, "Intersect(a, b, c)")
+	check.DeepEqMsg(t, Sorted(Intersect(a, b)), []int{3, 4}, "Intersect(a, b)")
+	check.DeepEqMsg(t, Sorted(Intersect[int]()), []int{}, "Intersect()")
+	check.DeepEqMsg(t, Sorted(Difference(a, b)), []int{1, 2}, "Difference(a, b)")
+	check.DeepEqMsg(t, Sorted(Difference(b, a, c)), []int{5}, "Difference(b, a, c)")
+	check.DeepEqMsg(t, Sorted(SymmetricDifference(a, b)), []int{1, 2, 5, 6}, "SymmetricDifference(a, b)")
+	check.DeepEqMsg(t, Sorted(SymmetricDifference(a, b, c)), []int{1, 2, 4, 5, 7}, "SymmetricDifference(a, b, c)")
+
+	// Arguments are not modified
+	check.DeepEqMsg(t, Sorted(a), []int{1, 2, 3, 4}, "a: after operations")
+	check.DeepEqMsg(t, Sorted(b), []int{3, 4, 5, 6}, "b: after operations")
+}
+
+func TestLazyOperations(t *testing.T) {
+	a := Of(1, 2, 3, 4)
+	b := Of(3, 4, 5, 6)
+	c := Of(4, 6, 7)
+
+	collect := func(it iter.Iterator[int]) []int {
+		s := iter.IntoSlice(it)
+		check.EqMsg(t, len(Of(s...)), len(s), "lazy operation generated duplicates")
+		return Sorted(Of(s...))
+	}
+
+	check.DeepEqMsg(t, collect(IterUnion(a, b, c)), []int{1, 2, 3, 4, 5, 6, 7}, "IterUnion(a, b, c)")
+	check.DeepEqMsg(t, collect(IterIntersect(a, b, c)), []int{4}, "IterIntersect(a, b, c)")
+	check.DeepEqMsg(t, collect(IterIntersect[int]()), []int{}, "IterIntersect()")
+	check.DeepEqMsg(t, collect(IterDifference(b, a, c)), []int{5}, "IterDifference(b, a, c)")
+	check.DeepEqMsg(
+		t,
+		collect(IterSymmetricDifference(a, b, c)),
+		[]int{1, 2, 4, 5, 7},
+		"IterSymmetricDifference(a, b, c)",
+	)
+}
@@ -2,7 +2,7 @@
 // SPDX-License-Identifier: MIT
 
 // set contains an implementation of an unordered collection of elements
-// in a `map[T]struct{}`
+// in a `map[T]struct{}`, and a variant safe for concurrent use.
 package set
 
 import "github.com/MKuranowski/go-extra-lib/iter"
@@ -14,9 +14,10 @@ import "github.com/MKuranowski/go-extra-lib/iter"
 //
 // To make an empty, non-nil set use make(Set[T]).
 //
-// To make a non-empty set, use map literals:
+// To make a non-empty set, use map literals, or the [Of] and [FromIter] functions:
 //
 //	numbers := Set[int]{1: {}, 2: {}, 3: {}}
+//	letters := Of('a', 'b', 'c')
 //
 // Sets can be iterated with a range loop,
 //
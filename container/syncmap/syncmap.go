@@ -0,0 +1,251 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// syncmap contains a generic map safe for concurrent use,
+// split into independently-locked shards to reduce contention.
+package syncmap
+
+import (
+	"hash/maphash"
+	"sync"
+
+	"github.com/MKuranowski/go-extra-lib/internal/keyhash"
+	"github.com/MKuranowski/go-extra-lib/iter"
+)
+
+// DefaultShards is the number of shards used by a [ConcurrentMap] with Shards unset.
+const DefaultShards = 32
+
+type shard[K comparable, V any] struct {
+	sync.RWMutex
+	m map[K]V
+}
+
+// ConcurrentMap is a map which is safe for concurrent use by multiple goroutines.
+//
+// Keys are distributed between a fixed number of shards, each guarded by its own lock,
+// so operations on keys in different shards don't contend with each other.
+//
+// The zero value is an empty map ready to use. A ConcurrentMap must not be copied after first use.
+//
+// Operations on a single key are atomic. Operations on the whole map (Len, Clear, Snapshot, Iter)
+// lock one shard at a time - they observe a consistent state of every shard,
+// but not necessarily of the whole map, if it's concurrently modified.
+//
+// Given operation complexity assumes that element access, insertion and removal
+// of a map is on average constant. Hashing arbitrary (non-string and non-integer) keys
+// uses reflection and is considerably slower than a built-in map.
+type ConcurrentMap[K comparable, V any] struct {
+	// Shards is the number of independently-locked parts of the map,
+	// rounded up to a power of two. If zero, DefaultShards is used.
+	//
+	// Shards must not be changed after the map was first used.
+	Shards int
+
+	once   sync.Once
+	seed   maphash.Seed
+	shards []shard[K, V]
+}
+
+func (m *ConcurrentMap[K, V]) ensureInit() {
+	m.once.Do(func() {
+		n := 1
+		for n < m.Shards {
+			n <<= 1
+		}
+		if m.Shards <= 0 {
+			n = DefaultShards
+		}
+
+		m.seed = maphash.MakeSeed()
+		m.shards = make([]shard[K, V], n)
+		for i := range m.shards {
+			m.shards[i].m = make(map[K]V)
+		}
+	})
+}
+
+func (m *ConcurrentMap[K, V]) shardFor(key K) *shard[K, V] {
+	m.ensureInit()
+	h := keyhash.Of(m.seed, key)
+	return &m.shards[h&uint64(len(m.shards)-1)]
+}
+
+// Load returns the value associated with the key.
+// The second return value is false if the key is not in the map.
+//
+// Average complexity: constant
+func (m *ConcurrentMap[K, V]) Load(key K) (value V, ok bool) {
+	s := m.shardFor(key)
+	s.RLock()
+	defer s.RUnlock()
+	value, ok = s.m[key]
+	return
+}
+
+// Has returns true if the key is in the map.
+//
+// Average complexity: constant
+func (m *ConcurrentMap[K, V]) Has(key K) bool {
+	_, ok := m.Load(key)
+	return ok
+}
+
+// Store associates the key with the provided value.
+//
+// Average complexity: constant
+func (m *ConcurrentMap[K, V]) Store(key K, value V) {
+	s := m.shardFor(key)
+	s.Lock()
+	defer s.Unlock()
+	s.m[key] = value
+}
+
+// LoadOrStore returns the existing value associated with the key, if present
+// (and loaded is true). Otherwise, associates the key with the provided value,
+// and returns it (and loaded is false).
+//
+// Average complexity: constant
+func (m *ConcurrentMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	s := m.shardFor(key)
+	s.Lock()
+	defer s.Unlock()
+
+	if actual, loaded = s.m[key]; loaded {
+		return
+	}
+	s.m[key] = value
+	return value, false
+}
+
+// Swap associates the key with the provided value and returns the previous value, if any.
+// loaded is true if the key was present in the map.
+//
+// Average complexity: constant
+func (m *ConcurrentMap[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	s := m.shardFor(key)
+	s.Lock()
+	defer s.Unlock()
+	previous, loaded = s.m[key]
+	s.m[key] = value
+	return
+}
+
+// LoadAndDelete removes the key from the map, returning the previous value, if any.
+// loaded is true if the key was present in the map.
+//
+// Average complexity: constant
+func (m *ConcurrentMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	s := m.shardFor(key)
+	s.Lock()
+	defer s.Unlock()
+	value, loaded = s.m[key]
+	delete(s.m, key)
+	return
+}
+
+// Delete ensures the key is not in the map.
+//
+// Average complexity: constant
+func (m *ConcurrentMap[K, V]) Delete(key K) {
+	m.LoadAndDelete(key)
+}
+
+// Compute atomically updates the value associated with the key.
+//
+// f is called with the current value (and loaded set to true), or the zero value (and loaded
+// set to false) if the key is not in the map. If keep is true, the returned value is associated
+// with the key; otherwise the key is removed from the map.
+// Compute returns the new value and keep.
+//
+// f is called while the shard containing the key is locked,
+// and therefore must not call any methods of the map.
+//
+// Average complexity: constant, plus the complexity of f
+func (m *ConcurrentMap[K, V]) Compute(key K, f func(old V, loaded bool) (new V, keep bool)) (V, bool) {
+	s := m.shardFor(key)
+	s.Lock()
+	defer s.Unlock()
+
+	old, loaded := s.m[key]
+	value, keep := f(old, loaded)
+	if keep {
+		s.m[key] = value
+	} else {
+		delete(s.m, key)
+	}
+	return value, keep
+}
+
+// DeleteFunc removes all entries for which f returns true.
+//
+// f is called while a shard is locked, and therefore must not call any methods of the map.
+//
+// Average complexity: linear
+func (m *ConcurrentMap[K, V]) DeleteFunc(f func(key K, value V) bool) {
+	m.ensureInit()
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.Lock()
+		for k, v := range s.m {
+			if f(k, v) {
+				delete(s.m, k)
+			}
+		}
+		s.Unlock()
+	}
+}
+
+// Len returns the number of entries in the map.
+//
+// Average complexity: linear in terms of the number of shards
+func (m *ConcurrentMap[K, V]) Len() (n int) {
+	m.ensureInit()
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.RLock()
+		n += len(s.m)
+		s.RUnlock()
+	}
+	return
+}
+
+// Clear removes all entries from the map.
+//
+// Average complexity: linear
+func (m *ConcurrentMap[K, V]) Clear() {
+	m.DeleteFunc(func(K, V) bool { return true })
+}
+
+// Snapshot returns a copy of the map's contents as a built-in map.
+//
+// Average complexity: linear
+func (m *ConcurrentMap[K, V]) Snapshot() map[K]V {
+	m.ensureInit()
+	snapshot := make(map[K]V)
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.RLock()
+		for k, v := range s.m {
+			snapshot[k] = v
+		}
+		s.RUnlock()
+	}
+	return snapshot
+}
+
+// Iter returns an [iter.Iterator] over a [ConcurrentMap.Snapshot] of the map.
+// Modifications made to the map after Iter returns are not visible through the iterator.
+func (m *ConcurrentMap[K, V]) Iter() iter.Iterator[iter.Pair[K, V]] {
+	return iter.OverMap(m.Snapshot())
+}
+
+// Keys returns an [iter.Iterator] over the keys of a [ConcurrentMap.Snapshot] of the map.
+func (m *ConcurrentMap[K, V]) Keys() iter.Iterator[K] {
+	return iter.OverMapKeys(m.Snapshot())
+}
+
+// Values returns an [iter.Iterator] over the values of a [ConcurrentMap.Snapshot] of the map.
+func (m *ConcurrentMap[K, V]) Values() iter.Iterator[V] {
+	return iter.OverMapValues(m.Snapshot())
+}
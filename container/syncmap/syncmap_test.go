@@ -0,0 +1,168 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package syncmap_test
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	. "github.com/MKuranowski/go-extra-lib/container/syncmap"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+func TestConcurrentMapBasic(t *testing.T) {
+	m := &ConcurrentMap[string, int]{}
+	check.EqMsg(t, m.Len(), 0, "m.Len(): empty map")
+
+	m.Store("a", 1)
+	m.Store("b", 2)
+	m.Store("c", 3)
+	check.EqMsg(t, m.Len(), 3, "m.Len(): after storing")
+
+	v, ok := m.Load("b")
+	check.TrueMsg(t, ok, "m.Load(b): ok")
+	check.EqMsg(t, v, 2, "m.Load(b): value")
+
+	_, ok = m.Load("d")
+	check.FalseMsg(t, ok, "m.Load(d): ok")
+	check.FalseMsg(t, m.Has("d"), "m.Has(d)")
+
+	v, loaded := m.LoadOrStore("a", 10)
+	check.TrueMsg(t, loaded, "m.LoadOrStore(a): loaded")
+	check.EqMsg(t, v, 1, "m.LoadOrStore(a): value")
+
+	v, loaded = m.LoadOrStore("d", 4)
+	check.FalseMsg(t, loaded, "m.LoadOrStore(d): loaded")
+	check.EqMsg(t, v, 4, "m.LoadOrStore(d): value")
+
+	v, loaded = m.Swap("a", 5)
+	check.TrueMsg(t, loaded, "m.Swap(a): loaded")
+	check.EqMsg(t, v, 1, "m.Swap(a): previous")
+
+	v, loaded = m.LoadAndDelete("c")
+	check.TrueMsg(t, loaded, "m.LoadAndDelete(c): loaded")
+	check.EqMsg(t, v, 3, "m.LoadAndDelete(c): value")
+
+	m.Delete("b")
+	m.Delete("nonexistent")
+
+	check.DeepEqMsg(t, m.Snapshot(), map[string]int{"a": 5, "d": 4}, "m.Snapshot()")
+
+	m.Clear()
+	check.EqMsg(t, m.Len(), 0, "m.Len(): after clearing")
+}
+
+func TestConcurrentMapCompute(t *testing.T) {
+	m := &ConcurrentMap[string, int]{}
+	increment := func(old int, _ bool) (int, bool) { return old + 1, true }
+
+	v, ok := m.Compute("a", increment)
+	check.EqMsg(t, v, 1, "m.Compute(a, increment): value")
+	check.TrueMsg(t, ok, "m.Compute(a, increment): keep")
+
+	m.Compute("a", increment)
+	v, _ = m.Load("a")
+	check.EqMsg(t, v, 2, "m.Load(a): after incrementing twice")
+
+	m.Compute("a", func(old int, loaded bool) (int, bool) {
+		check.TrueMsg(t, loaded, "m.Compute(a, delete): loaded")
+		return 0, false
+	})
+	check.FalseMsg(t, m.Has("a"), "m.Has(a): after deleting with Compute")
+}
+
+func TestConcurrentMapIter(t *testing.T) {
+	m := &ConcurrentMap[int, string]{Shards: 3}
+	m.Store(1, "one")
+	m.Store(2, "two")
+	m.Store(3, "three")
+
+	it := m.Iter()
+	m.Store(4, "four") // not visible in the snapshot
+
+	pairs := iter.IntoSlice(it)
+	slices.SortFunc(pairs, func(a, b iter.Pair[int, string]) bool { return a.First < b.First })
+	check.DeepEqMsg(
+		t,
+		pairs,
+		[]iter.Pair[int, string]{{First: 1, Second: "one"}, {First: 2, Second: "two"}, {First: 3, Second: "three"}},
+		"m.Iter()",
+	)
+
+	keys := iter.IntoSlice(m.Keys())
+	slices.Sort(keys)
+	check.DeepEqMsg(t, keys, []int{1, 2, 3, 4}, "m.Keys()")
+
+	m.DeleteFunc(func(k int, _ string) bool { return k%2 == 0 })
+	values := iter.IntoSlice(m.Values())
+	slices.Sort(values)
+	check.DeepEqMsg(t, values, []string{"one", "three"}, "m.Values(): after DeleteFunc")
+}
+
+type compositeKey struct {
+	Name  string
+	Index int
+	Ptr   *int
+	Arr   [2]bool
+	F     float64
+}
+
+func TestConcurrentMapCompositeKeys(t *testing.T) {
+	m := &ConcurrentMap[compositeKey, int]{}
+	x := 42
+
+	m.Store(compositeKey{"a", 1, &x, [2]bool{}, 0}, 1)
+	m.Store(compositeKey{"a", 1, nil, [2]bool{true, false}, 1.5}, 2)
+	m.Store(compositeKey{"b", 2, nil, [2]bool{}, 0}, 3)
+
+	v, _ := m.Load(compositeKey{"a", 1, &x, [2]bool{}, math.Copysign(0, -1)})
+	check.EqMsg(t, v, 1, "m.Load(...): with pointer and -0")
+	v, _ = m.Load(compositeKey{"a", 1, nil, [2]bool{true, false}, 1.5})
+	check.EqMsg(t, v, 2, "m.Load(...): with array")
+	v, _ = m.Load(compositeKey{"b", 2, nil, [2]bool{}, 0})
+	check.EqMsg(t, v, 3, "m.Load(...): with zero values")
+	check.FalseMsg(t, m.Has(compositeKey{"a", 1, nil, [2]bool{false, true}, 1.5}), "m.Has(...): different array")
+	check.EqMsg(t, m.Len(), 3, "m.Len()")
+}
+
+func TestConcurrentMapConcurrentAccess(t *testing.T) {
+	const goroutines = 8
+	const keys = 1000
+
+	m := &ConcurrentMap[int, int]{}
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+
+	for g := 0; g < goroutines; g++ {
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < keys; i++ {
+				m.Compute(i, func(old int, _ bool) (int, bool) { return old + 1, true })
+				m.LoadOrStore(keys+g, g)
+				m.Load(i)
+				if i%100 == 0 {
+					m.Len()
+					m.Snapshot()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	snapshot := m.Snapshot()
+	check.EqMsg(t, len(snapshot), keys+goroutines, "len(m.Snapshot())")
+	for i := 0; i < keys; i++ {
+		if snapshot[i] != goroutines {
+			t.Errorf("m[%d] = %d, expected %d", i, snapshot[i], goroutines)
+		}
+	}
+
+	others := maps.Keys(snapshot)
+	slices.Sort(others)
+	check.DeepEqMsg(t, others[keys:], []int{1000, 1001, 1002, 1003, 1004, 1005, 1006, 1007}, "per-goroutine keys")
+}
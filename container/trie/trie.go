@@ -0,0 +1,303 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// trie contains an implementation of a radix tree (compressed trie),
+// a map from strings which supports efficient prefix queries.
+package trie
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/MKuranowski/go-extra-lib/iter"
+)
+
+type node[V any] struct {
+	// prefix is the label of the edge from the parent node
+	prefix   string
+	value    V
+	hasValue bool
+
+	// children are sorted by the first byte of their prefix,
+	// and no two children share the first byte.
+	children []*node[V]
+}
+
+// child returns the index of the child whose prefix starts with b,
+// and whether such child exists. If not, the returned index is
+// where such child should be inserted.
+func (n *node[V]) child(b byte) (int, bool) {
+	idx := sort.Search(len(n.children), func(i int) bool { return n.children[i].prefix[0] >= b })
+	return idx, idx < len(n.children) && n.children[idx].prefix[0] == b
+}
+
+// mergeWithChild merges a node, which must have no value, with its only child.
+func (n *node[V]) mergeWithChild() {
+	c := n.children[0]
+	n.prefix += c.prefix
+	n.value, n.hasValue = c.value, c.hasValue
+	n.children = c.children
+}
+
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// Trie is a map from strings to values of type V, implemented as a radix tree.
+//
+// Apart from the usual map operations, Trie supports finding the longest key
+// being a prefix of a string, and iterating over all keys starting with a prefix.
+//
+// Keys are compared byte-wise; iteration happens in lexicographic order of bytes,
+// which for valid UTF-8 strings is the same as the order of codepoints.
+//
+// The zero value (`&Trie[V]{}`) is an empty Trie.
+//
+// Given operation complexity is in terms of the length of the key (k),
+// and the size of the alphabet (σ, at most 256).
+type Trie[V any] struct {
+	root node[V]
+	size int
+}
+
+// Len returns the number of keys in the Trie.
+func (t *Trie[V]) Len() int { return t.size }
+
+// Insert associates a value with a key. Returns true if the key was not already in the Trie.
+//
+// Complexity: O(k log σ)
+func (t *Trie[V]) Insert(key string, value V) bool {
+	n := &t.root
+	for {
+		if key == "" {
+			added := !n.hasValue
+			n.value, n.hasValue = value, true
+			if added {
+				t.size++
+			}
+			return added
+		}
+
+		idx, found := n.child(key[0])
+		if !found {
+			leaf := &node[V]{prefix: key, value: value, hasValue: true}
+			n.children = append(n.children, nil)
+			copy(n.children[idx+1:], n.children[idx:])
+			n.children[idx] = leaf
+			t.size++
+			return true
+		}
+
+		c := n.children[idx]
+		common := commonPrefixLen(c.prefix, key)
+		if common < len(c.prefix) {
+			// Split the edge leading to c
+			split := &node[V]{prefix: c.prefix[:common], children: []*node[V]{c}}
+			c.prefix = c.prefix[common:]
+			n.children[idx] = split
+			c = split
+		}
+
+		n, key = c, key[common:]
+	}
+}
+
+// find returns the node corresponding exactly to the provided key, or nil.
+func (t *Trie[V]) find(key string) *node[V] {
+	n := &t.root
+	for key != "" {
+		idx, found := n.child(key[0])
+		if !found {
+			return nil
+		}
+
+		c := n.children[idx]
+		if !strings.HasPrefix(key, c.prefix) {
+			return nil
+		}
+		n, key = c, key[len(c.prefix):]
+	}
+	return n
+}
+
+// Get returns the value associated with the provided key.
+//
+// Complexity: O(k log σ)
+func (t *Trie[V]) Get(key string) (value V, ok bool) {
+	if n := t.find(key); n != nil && n.hasValue {
+		return n.value, true
+	}
+	return
+}
+
+// Has returns true if the provided key is in the Trie.
+//
+// Complexity: O(k log σ)
+func (t *Trie[V]) Has(key string) bool {
+	n := t.find(key)
+	return n != nil && n.hasValue
+}
+
+// Delete ensures the provided key is not in the Trie. Returns true if the key was removed.
+//
+// Complexity: O(k log σ + σ)
+func (t *Trie[V]) Delete(key string) bool {
+	var parent *node[V]
+	var idxInParent int
+	n := &t.root
+
+	for key != "" {
+		idx, found := n.child(key[0])
+		if !found {
+			return false
+		}
+
+		c := n.children[idx]
+		if !strings.HasPrefix(key, c.prefix) {
+			return false
+		}
+		parent, idxInParent, n, key = n, idx, c, key[len(c.prefix):]
+	}
+
+	if !n.hasValue {
+		return false
+	}
+
+	var zero V
+	n.value, n.hasValue = zero, false
+	t.size--
+
+	// Restore the invariant that non-root nodes without values have at least 2 children
+	if parent == nil {
+		return true
+	}
+
+	switch len(n.children) {
+	case 0:
+		copy(parent.children[idxInParent:], parent.children[idxInParent+1:])
+		parent.children[len(parent.children)-1] = nil
+		parent.children = parent.children[:len(parent.children)-1]
+
+		if parent != &t.root && !parent.hasValue && len(parent.children) == 1 {
+			parent.mergeWithChild()
+		}
+
+	case 1:
+		n.mergeWithChild()
+	}
+
+	return true
+}
+
+// LongestPrefix finds the longest key in the Trie which is a prefix of s.
+//
+//	t := {"a": 1, "ab": 2, "abcd": 3}
+//	t.LongestPrefix("abc") → ("ab", 2, true)
+//	t.LongestPrefix("b") → ("", 0, false)
+//
+// Complexity: O(k log σ), where k = len(s)
+func (t *Trie[V]) LongestPrefix(s string) (key string, value V, ok bool) {
+	n := &t.root
+	consumed := 0
+
+	for {
+		if n.hasValue {
+			key, value, ok = s[:consumed], n.value, true
+		}
+
+		if consumed == len(s) {
+			return
+		}
+
+		idx, found := n.child(s[consumed])
+		if !found {
+			return
+		}
+
+		c := n.children[idx]
+		if !strings.HasPrefix(s[consumed:], c.prefix) {
+			return
+		}
+		n, consumed = c, consumed+len(c.prefix)
+	}
+}
+
+type stackEntry[V any] struct {
+	n   *node[V]
+	key string
+}
+
+type trieIterator[V any] struct {
+	stack   []stackEntry[V]
+	current iter.Pair[string, V]
+}
+
+func (i *trieIterator[V]) Next() bool {
+	for len(i.stack) > 0 {
+		top := i.stack[len(i.stack)-1]
+		i.stack[len(i.stack)-1] = stackEntry[V]{}
+		i.stack = i.stack[:len(i.stack)-1]
+
+		// Push children in reverse order, so that they're popped in lexicographic order
+		for j := len(top.n.children) - 1; j >= 0; j-- {
+			c := top.n.children[j]
+			i.stack = append(i.stack, stackEntry[V]{c, top.key + c.prefix})
+		}
+
+		if top.n.hasValue {
+			i.current = iter.Pair[string, V]{First: top.key, Second: top.n.value}
+			return true
+		}
+	}
+	return false
+}
+
+func (i *trieIterator[V]) Get() iter.Pair[string, V] { return i.current }
+func (i *trieIterator[V]) Err() error                { return nil }
+
+// WithPrefix returns an iterator over all key-value pairs whose keys start with p,
+// in lexicographic order of keys.
+//
+// The Trie must not be modified during iteration.
+//
+//	t := {"car": 1, "cart": 2, "cat": 3, "dog": 4}
+//	t.WithPrefix("car") → [{"car" 1} {"cart" 2}]
+//
+// The Err() method always returns nil.
+func (t *Trie[V]) WithPrefix(p string) iter.Iterator[iter.Pair[string, V]] {
+	n := &t.root
+	key := ""
+
+	for rest := p; rest != ""; {
+		idx, found := n.child(rest[0])
+		if !found {
+			return iter.Empty[iter.Pair[string, V]]()
+		}
+
+		c := n.children[idx]
+		if strings.HasPrefix(c.prefix, rest) {
+			// p ends in the middle of the edge leading to c
+			n, key, rest = c, key+c.prefix, ""
+		} else if strings.HasPrefix(rest, c.prefix) {
+			n, key, rest = c, key+c.prefix, rest[len(c.prefix):]
+		} else {
+			return iter.Empty[iter.Pair[string, V]]()
+		}
+	}
+
+	return &trieIterator[V]{stack: []stackEntry[V]{{n, key}}}
+}
+
+// Iter returns an iterator over all key-value pairs in the Trie,
+// in lexicographic order of keys.
+//
+// The Trie must not be modified during iteration.
+//
+// The Err() method always returns nil.
+func (t *Trie[V]) Iter() iter.Iterator[iter.Pair[string, V]] {
+	return &trieIterator[V]{stack: []stackEntry[V]{{&t.root, ""}}}
+}
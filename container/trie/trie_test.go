@@ -0,0 +1,144 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package trie_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/container/trie"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+func keys(i iter.Iterator[iter.Pair[string, int]]) []string {
+	return iter.IntoSlice(iter.Map(i, func(p iter.Pair[string, int]) string { return p.First }))
+}
+
+func exampleTrie() *trie.Trie[int] {
+	t := &trie.Trie[int]{}
+	t.Insert("car", 1)
+	t.Insert("cart", 2)
+	t.Insert("cat", 3)
+	t.Insert("dog", 4)
+	t.Insert("do", 5)
+	return t
+}
+
+func TestTrieInsertGet(t *testing.T) {
+	tr := exampleTrie()
+
+	check.EqMsg(t, tr.Len(), 5, "tr.Len()")
+
+	for key, expected := range map[string]int{"car": 1, "cart": 2, "cat": 3, "dog": 4, "do": 5} {
+		v, ok := tr.Get(key)
+		check.TrueMsg(t, ok, "tr.Get("+key+"): ok")
+		check.EqMsg(t, v, expected, "tr.Get("+key+")")
+	}
+
+	for _, key := range []string{"", "c", "ca", "carts", "d", "dogs", "x"} {
+		check.FalseMsg(t, tr.Has(key), "tr.Has("+key+")")
+	}
+
+	check.FalseMsg(t, tr.Insert("cat", 30), "tr.Insert(cat, 30)")
+	v, _ := tr.Get("cat")
+	check.EqMsg(t, v, 30, "tr.Get(cat): after overwrite")
+	check.EqMsg(t, tr.Len(), 5, "tr.Len(): after overwrite")
+
+	check.TrueMsg(t, tr.Insert("", 0), "tr.Insert(\"\", 0)")
+	check.TrueMsg(t, tr.Has(""), "tr.Has(\"\")")
+}
+
+func TestTrieDelete(t *testing.T) {
+	tr := exampleTrie()
+
+	check.TrueMsg(t, tr.Delete("car"), "tr.Delete(car)")
+	check.FalseMsg(t, tr.Delete("car"), "tr.Delete(car): second time")
+	check.FalseMsg(t, tr.Delete("ca"), "tr.Delete(ca)")
+	check.FalseMsg(t, tr.Has("car"), "tr.Has(car)")
+	check.TrueMsg(t, tr.Has("cart"), "tr.Has(cart)")
+
+	check.TrueMsg(t, tr.Delete("cart"), "tr.Delete(cart)")
+	check.TrueMsg(t, tr.Delete("do"), "tr.Delete(do)")
+
+	check.EqMsg(t, tr.Len(), 2, "tr.Len()")
+	check.DeepEqMsg(t, keys(tr.Iter()), []string{"cat", "dog"}, "tr.Iter()")
+}
+
+func TestTrieLongestPrefix(t *testing.T) {
+	tr := &trie.Trie[int]{}
+	tr.Insert("a", 1)
+	tr.Insert("ab", 2)
+	tr.Insert("abcd", 3)
+
+	key, value, ok := tr.LongestPrefix("abc")
+	check.TrueMsg(t, ok, "tr.LongestPrefix(abc): ok")
+	check.EqMsg(t, key, "ab", "tr.LongestPrefix(abc): key")
+	check.EqMsg(t, value, 2, "tr.LongestPrefix(abc): value")
+
+	key, _, _ = tr.LongestPrefix("abcdef")
+	check.EqMsg(t, key, "abcd", "tr.LongestPrefix(abcdef): key")
+
+	key, _, _ = tr.LongestPrefix("a")
+	check.EqMsg(t, key, "a", "tr.LongestPrefix(a): key")
+
+	_, _, ok = tr.LongestPrefix("b")
+	check.FalseMsg(t, ok, "tr.LongestPrefix(b): ok")
+}
+
+func TestTrieWithPrefix(t *testing.T) {
+	tr := exampleTrie()
+
+	check.DeepEqMsg(t, keys(tr.WithPrefix("car")), []string{"car", "cart"}, "tr.WithPrefix(car)")
+	check.DeepEqMsg(t, keys(tr.WithPrefix("ca")), []string{"car", "cart", "cat"}, "tr.WithPrefix(ca)")
+	check.DeepEqMsg(t, keys(tr.WithPrefix("c")), []string{"car", "cart", "cat"}, "tr.WithPrefix(c)")
+	check.DeepEqMsg(t, keys(tr.WithPrefix("d")), []string{"do", "dog"}, "tr.WithPrefix(d)")
+	check.DeepEqMsg(t, keys(tr.WithPrefix("cx")), []string{}, "tr.WithPrefix(cx)")
+	check.DeepEqMsg(t, keys(tr.WithPrefix("carts")), []string{}, "tr.WithPrefix(carts)")
+	check.DeepEqMsg(
+		t,
+		keys(tr.WithPrefix("")),
+		[]string{"car", "cart", "cat", "do", "dog"},
+		"tr.WithPrefix(\"\")",
+	)
+}
+
+func TestTrieRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	tr := &trie.Trie[int]{}
+	m := make(map[string]int)
+
+	randomKey := func() string {
+		b := make([]byte, r.Intn(6))
+		for i := range b {
+			b[i] = "abc"[r.Intn(3)]
+		}
+		return string(b)
+	}
+
+	for i := 0; i < 2000; i++ {
+		k := randomKey()
+		if r.Intn(3) == 0 {
+			_, inMap := m[k]
+			check.EqMsg(t, tr.Delete(k), inMap, "tr.Delete("+k+")")
+			delete(m, k)
+		} else {
+			_, inMap := m[k]
+			check.EqMsg(t, tr.Insert(k, i), !inMap, "tr.Insert("+k+")")
+			m[k] = i
+		}
+	}
+
+	expectedKeys := maps.Keys(m)
+	slices.Sort(expectedKeys)
+
+	check.EqMsg(t, tr.Len(), len(m), "tr.Len()")
+	check.DeepEqMsg(t, keys(tr.Iter()), expectedKeys, "tr.Iter()")
+	for k, v := range m {
+		got, _ := tr.Get(k)
+		check.EqMsg(t, got, v, "tr.Get("+k+")")
+	}
+}
@@ -4,6 +4,8 @@
 // mcsv is a wrapper around the built-in [encoding/csv] package,
 // parsing [RFC 4180] CSV files into map[string]string rows.
 //
+// Rows can also be mapped onto structs, see [Unmarshal], [Marshal] and [TypedReader].
+//
 // [RFC 4180]: https://rfc-editor.org/rfc/rfc4180.html
 package mcsv
 
@@ -0,0 +1,413 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package mcsv
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+var (
+	// ErrUnsupportedType is returned when trying to decode into or encode from
+	// a type which is not a struct, or a struct with a field of an unsupported type.
+	ErrUnsupportedType = errors.New("mcsv: unsupported type")
+
+	// ErrMissingColumn is returned when decoding a record without a column
+	// required by a struct field.
+	ErrMissingColumn = errors.New("mcsv: missing column")
+)
+
+// FieldError is returned when a value of a particular column can't be
+// decoded into (or encoded from) a struct field.
+type FieldError struct {
+	// Row is the 1-based line number of the offending field in the CSV file,
+	// or zero if unknown (e.g. when using [Unmarshal] or [Marshal] directly).
+	Row int
+
+	// Column is the name of the offending column.
+	Column string
+
+	// Err is the underlying error, e.g. a [strconv.NumError].
+	Err error
+}
+
+func (e FieldError) Error() string {
+	if e.Row > 0 {
+		return fmt.Sprintf("mcsv: row %d, column %q: %v", e.Row, e.Column, e.Err)
+	}
+	return fmt.Sprintf("mcsv: column %q: %v", e.Column, e.Err)
+}
+
+func (e FieldError) Unwrap() error { return e.Err }
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// fieldInfo describes how a struct field maps onto a CSV column.
+type fieldInfo struct {
+	index     []int
+	column    string
+	omitEmpty bool
+	optional  bool
+	layout    string
+}
+
+// structInfoCache maps reflect.Type to *structInfo
+var structInfoCache sync.Map
+
+type structInfo struct {
+	fields []fieldInfo
+}
+
+// getStructInfo returns the cached description of a struct type,
+// or an error if t is not a struct or has fields of unsupported types.
+func getStructInfo(t reflect.Type) (*structInfo, error) {
+	if cached, ok := structInfoCache.Load(t); ok {
+		return cached.(*structInfo), nil
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: expected a struct, got %s", ErrUnsupportedType, t)
+	}
+
+	info := &structInfo{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := f.Tag.Get("csv")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+
+		field := fieldInfo{
+			index:     f.Index,
+			column:    name,
+			omitEmpty: slices.Contains(strings.Split(opts, ","), "omitempty"),
+			optional:  f.Type.Kind() == reflect.Pointer,
+			layout:    f.Tag.Get("layout"),
+		}
+		if field.layout == "" {
+			field.layout = time.RFC3339
+		}
+
+		if !isSupported(f.Type) {
+			return nil, fmt.Errorf("%w: field %s of %s has type %s", ErrUnsupportedType, f.Name, t, f.Type)
+		}
+
+		info.fields = append(info.fields, field)
+	}
+
+	cached, _ := structInfoCache.LoadOrStore(t, info)
+	return cached.(*structInfo), nil
+}
+
+func isSupported(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// StructHeader returns the column names used by a struct (or a pointer to a struct),
+// in the order of struct fields. This can be used as the header of a [Writer].
+func StructHeader(v any) ([]string, error) {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil, fmt.Errorf("%w: expected a struct, got nil", ErrUnsupportedType)
+	}
+
+	info, err := getStructInfo(t)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]string, len(info.fields))
+	for i, f := range info.fields {
+		header[i] = f.column
+	}
+	return header, nil
+}
+
+// Unmarshal decodes a record into a struct pointed to by v.
+//
+// Every exported struct field is mapped onto a column, named after the field,
+// unless overridden with a tag: `csv:"column_name"`. Fields with a `csv:"-"` tag are ignored.
+//
+// The following field types are supported:
+//   - string,
+//   - bool (see [strconv.ParseBool]),
+//   - signed and unsigned integers (see [strconv.ParseInt]),
+//   - floats (see [strconv.ParseFloat]),
+//   - [time.Time], parsed with the layout from the `layout:"..."` tag (defaults to [time.RFC3339]),
+//   - types implementing [encoding.TextUnmarshaler],
+//   - pointers to any of the above - an empty value or a missing column leaves such fields nil.
+//
+// With the omitempty option (`csv:"column_name,omitempty"`), an empty value
+// or a missing column sets the field to its zero value.
+//
+// Otherwise, a missing column results in a [FieldError] wrapping [ErrMissingColumn].
+// Values which can't be parsed also result in a [FieldError].
+func Unmarshal(record map[string]string, v any) error {
+	return unmarshal(record, v, func(string) int { return 0 })
+}
+
+// unmarshal decodes a record into v, using rowOf to fill FieldError.Row.
+func unmarshal(record map[string]string, v any, rowOf func(column string) int) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("%w: expected a non-nil pointer to a struct, got %T", ErrUnsupportedType, v)
+	}
+	rv = rv.Elem()
+
+	info, err := getStructInfo(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, f := range info.fields {
+		fv := rv.FieldByIndex(f.index)
+		s, ok := record[f.column]
+
+		if !ok && !f.optional && !f.omitEmpty {
+			return FieldError{Row: rowOf(f.column), Column: f.column, Err: ErrMissingColumn}
+		}
+
+		if err := decodeField(fv, s, f); err != nil {
+			return FieldError{Row: rowOf(f.column), Column: f.column, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func decodeField(v reflect.Value, s string, f fieldInfo) error {
+	if v.Kind() == reflect.Pointer {
+		if s == "" {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeField(elem.Elem(), s, f); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	if s == "" && f.omitEmpty {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	if v.Type() == timeType {
+		t, err := time.Parse(f.layout, s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(x)
+
+	default:
+		panic("mcsv: decodeField called on an unsupported type " + v.Type().String())
+	}
+
+	return nil
+}
+
+// Marshal encodes a struct (or a pointer to a struct) into a record.
+// This is the inverse of [Unmarshal].
+//
+// Nil pointers are encoded as empty strings, as are zero values of fields
+// with the omitempty option. Floats are encoded using the shortest representation
+// which round-trips (see [strconv.FormatFloat] with the 'g' format and -1 precision).
+func Marshal(v any) (map[string]string, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: expected a struct, got %T", ErrUnsupportedType, v)
+	}
+
+	info, err := getStructInfo(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure fields are addressable, so that TextMarshalers with pointer receivers are found
+	if !rv.CanAddr() {
+		addressable := reflect.New(rv.Type()).Elem()
+		addressable.Set(rv)
+		rv = addressable
+	}
+
+	record := make(map[string]string, len(info.fields))
+	for _, f := range info.fields {
+		s, err := encodeField(rv.FieldByIndex(f.index), f)
+		if err != nil {
+			return nil, FieldError{Column: f.column, Err: err}
+		}
+		record[f.column] = s
+	}
+	return record, nil
+}
+
+func encodeField(v reflect.Value, f fieldInfo) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	if f.omitEmpty && v.IsZero() {
+		return "", nil
+	}
+
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(f.layout), nil
+	}
+
+	if v.Type().Implements(textMarshalerType) || reflect.PointerTo(v.Type()).Implements(textMarshalerType) {
+		var m encoding.TextMarshaler
+		if v.CanAddr() {
+			m = v.Addr().Interface().(encoding.TextMarshaler)
+		} else {
+			m = v.Interface().(encoding.TextMarshaler)
+		}
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedType, v.Type())
+	}
+}
+
+// Decode reads the next record from the CSV file and decodes it into
+// a struct pointed to by v. See [Unmarshal] for details of the decoding.
+//
+// Errors encountered while decoding values are reported as [FieldError]s,
+// with the Row set. If there are no more records to read, returns io.EOF.
+func (r *Reader) Decode(v any) error {
+	record, err := r.Read()
+	if err != nil {
+		return err
+	}
+
+	return unmarshal(record, v, func(column string) int {
+		// Columns missing from the Header are reported at the start of the record
+		i := slices.Index(r.Header, column)
+		if i < 0 {
+			i = 0
+		}
+		line, _ := r.Reader.FieldPos(i)
+		return line
+	})
+}
+
+// Encode encodes a struct (or a pointer to a struct) and writes it to the CSV file.
+// See [Marshal] for details of the encoding.
+//
+// Columns of the struct not present in the Header are ignored,
+// and columns of the Header without a corresponding struct field are left empty.
+//
+// All writes to the underlying io.Writer are buffered and some data
+// may not be actually written unless Flush() is called.
+func (w *Writer) Encode(v any) error {
+	record, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+	return w.Write(record)
+}
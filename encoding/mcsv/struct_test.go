@@ -0,0 +1,206 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package mcsv_test
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MKuranowski/go-extra-lib/encoding/mcsv"
+	"github.com/MKuranowski/go-extra-lib/testing2/assert"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+type server struct {
+	Name     string    `csv:"name"`
+	Port     uint16    `csv:"port"`
+	Load     float64   `csv:"load,omitempty"`
+	Active   bool      `csv:"active"`
+	Since    time.Time `csv:"since" layout:"2006-01-02"`
+	Address  net.IP    `csv:"address"`
+	Priority *int      `csv:"priority"`
+	Comment  string    `csv:"-"`
+	internal int
+}
+
+func intPtr(x int) *int { return &x }
+
+func TestUnmarshal(t *testing.T) {
+	var s server
+	err := mcsv.Unmarshal(
+		map[string]string{
+			"name":     "alpha",
+			"port":     "8080",
+			"load":     "0.75",
+			"active":   "true",
+			"since":    "2023-04-05",
+			"address":  "192.0.2.1",
+			"priority": "3",
+		},
+		&s,
+	)
+	assert.NoErr(t, err)
+
+	check.EqMsg(t, s.Name, "alpha", "s.Name")
+	check.EqMsg(t, s.Port, uint16(8080), "s.Port")
+	check.EqMsg(t, s.Load, 0.75, "s.Load")
+	check.TrueMsg(t, s.Active, "s.Active")
+	check.TrueMsg(t, s.Since.Equal(time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC)), "s.Since")
+	check.EqMsg(t, s.Address.String(), "192.0.2.1", "s.Address")
+	check.DeepEqMsg(t, s.Priority, intPtr(3), "s.Priority")
+}
+
+func TestUnmarshalOptional(t *testing.T) {
+	s := server{Load: 1, Priority: intPtr(1)}
+	err := mcsv.Unmarshal(
+		map[string]string{
+			"name":     "beta",
+			"port":     "80",
+			"load":     "",
+			"active":   "0",
+			"since":    "2023-01-01",
+			"address":  "::1",
+			"priority": "",
+		},
+		&s,
+	)
+	assert.NoErr(t, err)
+	check.EqMsg(t, s.Load, 0.0, "s.Load")
+	check.TrueMsg(t, s.Priority == nil, "s.Priority == nil")
+
+	// Columns of omitempty and pointer fields may be missing altogether
+	err = mcsv.Unmarshal(
+		map[string]string{"name": "gamma", "port": "80", "active": "1", "since": "2023-01-01", "address": "::1"},
+		&s,
+	)
+	assert.NoErr(t, err)
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var s server
+
+	err := mcsv.Unmarshal(map[string]string{"name": "alpha"}, &s)
+	check.SpecificErrMsg(t, err, mcsv.ErrMissingColumn, "Unmarshal(missing column)")
+	var missingErr mcsv.FieldError
+	check.TrueMsg(t, errors.As(err, &missingErr), "errors.As(missing column, &FieldError)")
+	check.EqMsg(t, missingErr.Column, "port", "missingErr.Column")
+	check.EqMsg(t, missingErr.Row, 0, "missingErr.Row")
+
+	err = mcsv.Unmarshal(
+		map[string]string{"name": "a", "port": "99999", "active": "1", "since": "2023-01-01", "address": "::1"},
+		&s,
+	)
+	var fieldErr mcsv.FieldError
+	check.TrueMsg(t, errors.As(err, &fieldErr), "errors.As(err, &FieldError)")
+	check.EqMsg(t, fieldErr.Column, "port", "fieldErr.Column")
+	check.SpecificErrMsg(t, err, strconv.ErrRange, "Unmarshal(port out of range)")
+
+	check.SpecificErrMsg(t, mcsv.Unmarshal(nil, s), mcsv.ErrUnsupportedType, "Unmarshal(non-pointer)")
+	check.SpecificErrMsg(t, mcsv.Unmarshal(nil, new(int)), mcsv.ErrUnsupportedType, "Unmarshal(*int)")
+	check.SpecificErrMsg(
+		t,
+		mcsv.Unmarshal(nil, &struct{ X []int }{}),
+		mcsv.ErrUnsupportedType,
+		"Unmarshal(unsupported field)",
+	)
+}
+
+func TestMarshal(t *testing.T) {
+	record, err := mcsv.Marshal(server{
+		Name:    "alpha",
+		Port:    8080,
+		Active:  true,
+		Since:   time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC),
+		Address: net.IPv4(192, 0, 2, 1),
+		Comment: "ignored",
+	})
+	assert.NoErr(t, err)
+	check.DeepEqMsg(
+		t,
+		record,
+		map[string]string{
+			"name":     "alpha",
+			"port":     "8080",
+			"load":     "",
+			"active":   "true",
+			"since":    "2023-04-05",
+			"address":  "192.0.2.1",
+			"priority": "",
+		},
+		"Marshal",
+	)
+
+	_, err = mcsv.Marshal(42)
+	check.SpecificErrMsg(t, err, mcsv.ErrUnsupportedType, "Marshal(42)")
+}
+
+func TestStructHeader(t *testing.T) {
+	header, err := mcsv.StructHeader(&server{})
+	assert.NoErr(t, err)
+	check.DeepEqMsg(
+		t,
+		header,
+		[]string{"name", "port", "load", "active", "since", "address", "priority"},
+		"StructHeader",
+	)
+}
+
+type point struct {
+	X, Y float64
+}
+
+func TestReaderDecode(t *testing.T) {
+	r := mcsv.NewReader(strings.NewReader("X,Y\n1,2\n\"3\n\",4\n"))
+
+	var p point
+	assert.NoErr(t, r.Decode(&p))
+	check.DeepEqMsg(t, p, point{1, 2}, "first point")
+
+	err := r.Decode(&p)
+	var fieldErr mcsv.FieldError
+	check.TrueMsg(t, errors.As(err, &fieldErr), "errors.As(err, &FieldError)")
+	check.EqMsg(t, fieldErr.Row, 3, "fieldErr.Row")
+	check.EqMsg(t, fieldErr.Column, "X", "fieldErr.Column")
+	check.EqMsg(t, err.Error(), `mcsv: row 3, column "X": strconv.ParseFloat: parsing "3\n": invalid syntax`, "err.Error()")
+
+	check.SpecificErrMsg(t, r.Decode(&p), io.EOF, "Decode at EOF")
+}
+
+func TestReaderDecodeMissingColumn(t *testing.T) {
+	r := mcsv.NewReader(strings.NewReader("X,Z\n1,2\n\"3\n\",4\n"))
+
+	var p point
+	err := r.Decode(&p)
+	check.SpecificErrMsg(t, err, mcsv.ErrMissingColumn, "Decode(missing column)")
+
+	var fieldErr mcsv.FieldError
+	check.TrueMsg(t, errors.As(err, &fieldErr), "errors.As(err, &FieldError)")
+	check.EqMsg(t, fieldErr.Row, 2, "fieldErr.Row")
+	check.EqMsg(t, fieldErr.Column, "Y", "fieldErr.Column")
+	check.EqMsg(t, err.Error(), `mcsv: row 2, column "Y": mcsv: missing column`, "err.Error()")
+
+	err = r.Decode(&p)
+	check.TrueMsg(t, errors.As(err, &fieldErr), "errors.As(err, &FieldError)")
+	check.EqMsg(t, fieldErr.Row, 3, "fieldErr.Row (second record)")
+}
+
+func TestWriterEncode(t *testing.T) {
+	b := &strings.Builder{}
+	header, err := mcsv.StructHeader(point{})
+	assert.NoErr(t, err)
+
+	w := mcsv.NewWriter(b, header)
+	assert.NoErr(t, w.WriteHeader())
+	assert.NoErr(t, w.Encode(point{1, 2.5}))
+	assert.NoErr(t, w.Encode(&point{-3, 1e21}))
+	w.Flush()
+	assert.NoErr(t, w.Error())
+
+	check.EqMsg(t, b.String(), "X,Y\n1,2.5\n-3,1e+21\n", "written CSV")
+}
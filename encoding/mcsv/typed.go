@@ -0,0 +1,92 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package mcsv
+
+import (
+	"errors"
+	"io"
+	"reflect"
+
+	"github.com/MKuranowski/go-extra-lib/iter"
+)
+
+// TypedReader reads records from a CSV io.Reader, decoding them into structs of type T.
+// See [Unmarshal] for details of the decoding.
+//
+// TypedReader implements [iter.IOReader], and can be easily wrapped into an [iter.Iterator]
+// using the Iter method.
+type TypedReader[T any] struct {
+	// TypedReader.Reader is the [Reader] actually used for parsing the CSV file.
+	//
+	// All options of the [Reader] (including those of the underlying [csv.Reader])
+	// are available and can be set before the first call to Read / ReadAll,
+	// with the exception of ReuseRecord, which has no effect.
+	*Reader
+}
+
+// NewTypedReader returns a TypedReader pulling CSV records from r.
+// T must be a struct type.
+//
+// The first row is assumed to be the header row.
+func NewTypedReader[T any](r io.Reader) *TypedReader[T] {
+	n := &TypedReader[T]{Reader: NewReader(r)}
+	n.Reader.ReuseRecord = true
+	return n
+}
+
+// NewTypedReaderWithHeader returns a TypedReader pulling CSV records from r.
+// T must be a struct type.
+//
+// Assumes that r does not contain a header row; instead header is
+// used as the column names. All rows in the CSV file must have len(header) fields.
+// See also [StructHeader].
+func NewTypedReaderWithHeader[T any](r io.Reader, header []string) *TypedReader[T] {
+	n := &TypedReader[T]{Reader: NewReaderWithHeader(r, header)}
+	n.Reader.ReuseRecord = true
+	return n
+}
+
+// Read reads a record from the CSV file and decodes it.
+// If there are no more records to read, returns (zero T, io.EOF).
+//
+// If T is not a struct, or has fields of unsupported types, returns an error wrapping
+// [ErrUnsupportedType] without reading anything.
+func (r *TypedReader[T]) Read() (record T, err error) {
+	if _, err = getStructInfo(reflect.TypeOf(&record).Elem()); err != nil {
+		return
+	}
+
+	var v T
+	if err = r.Reader.Decode(&v); err == nil {
+		record = v
+	}
+	return
+}
+
+// ReadAll repeatedly calls Read to read all remaining records from a file.
+// If an error occurs, returns all records read up to the error and the error itself.
+// Successful ReadAll call returns (records, nil), as io.EOF is not deemed an error in this context.
+func (r *TypedReader[T]) ReadAll() (records []T, err error) {
+	for {
+		var record T
+		record, err = r.Read()
+
+		if errors.Is(err, io.EOF) {
+			err = nil
+			break
+		} else if err != nil {
+			break
+		}
+
+		records = append(records, record)
+	}
+
+	return
+}
+
+// Iter returns an iterator over all remaining records of the CSV file.
+// Any errors are reported by the iterator's Err method.
+func (r *TypedReader[T]) Iter() iter.Iterator[T] {
+	return iter.OverIOReader[T](r)
+}
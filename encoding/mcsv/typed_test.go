@@ -0,0 +1,97 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package mcsv_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/encoding/mcsv"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/assert"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+type city struct {
+	Name       string `csv:"City"`
+	Country    string
+	Population *int `csv:"Population"`
+}
+
+const citiesData = `City,Country,Population
+Berlin,Germany,3677472
+Madrid,Spain,3223334
+Rome,Italy,
+Paris,France,2165423
+`
+
+func ExampleTypedReader() {
+	r := mcsv.NewTypedReader[city](strings.NewReader(citiesData))
+
+	big := iter.Filter(r.Iter(), func(c city) bool {
+		return c.Population != nil && *c.Population > 3000000
+	})
+	for big.Next() {
+		fmt.Println(big.Get().Name)
+	}
+	if err := big.Err(); err != nil {
+		panic(err)
+	}
+
+	// Output:
+	// Berlin
+	// Madrid
+}
+
+func TestTypedReaderRead(t *testing.T) {
+	r := mcsv.NewTypedReader[city](strings.NewReader(citiesData))
+
+	c, err := r.Read()
+	assert.NoErr(t, err)
+	check.EqMsg(t, c.Name, "Berlin", "c.Name")
+	check.EqMsg(t, c.Country, "Germany", "c.Country")
+	check.EqMsg(t, *c.Population, 3677472, "c.Population")
+
+	all, err := r.ReadAll()
+	assert.NoErr(t, err)
+	check.EqMsg(t, len(all), 3, "len(all)")
+	check.EqMsg(t, all[1].Name, "Rome", "all[1].Name")
+	check.TrueMsg(t, all[1].Population == nil, "all[1].Population == nil")
+
+	_, err = r.Read()
+	check.SpecificErrMsg(t, err, io.EOF, "Read at EOF")
+}
+
+func TestTypedReaderWithHeader(t *testing.T) {
+	r := mcsv.NewTypedReaderWithHeader[point](strings.NewReader("1,2\n3,4\n"), []string{"X", "Y"})
+	points, err := r.ReadAll()
+	assert.NoErr(t, err)
+	check.DeepEqMsg(t, points, []point{{1, 2}, {3, 4}}, "points")
+}
+
+func TestTypedReaderIterError(t *testing.T) {
+	r := mcsv.NewTypedReader[point](strings.NewReader("X,Y\n1,2\nfoo,4\n5,6\n"))
+	it := r.Iter()
+
+	check.DeepEqMsg(t, iter.IntoSlice(it), []point{{1, 2}}, "points before the error")
+
+	var fieldErr mcsv.FieldError
+	check.TrueMsg(t, errors.As(it.Err(), &fieldErr), "errors.As(it.Err(), &FieldError)")
+	check.EqMsg(t, fieldErr.Row, 3, "fieldErr.Row")
+	check.EqMsg(t, fieldErr.Column, "X", "fieldErr.Column")
+}
+
+func TestTypedReaderUnsupportedType(t *testing.T) {
+	r := mcsv.NewTypedReader[int](strings.NewReader("X,Y\n1,2\n"))
+	_, err := r.Read()
+	check.SpecificErrMsg(t, err, mcsv.ErrUnsupportedType, "Read")
+
+	// Nothing should have been consumed
+	record, err := r.Reader.Read()
+	assert.NoErr(t, err)
+	check.DeepEqMsg(t, record, map[string]string{"X": "1", "Y": "2"}, "first record")
+}
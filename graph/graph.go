@@ -0,0 +1,147 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// graph is a package with generic graph algorithms,
+// whose results are exposed as lazy iterators.
+//
+// Graphs can be defined explicitly, with an [Adjacency] structure,
+// or implicitly - by a function returning neighbors of a node ([Func] and [WeightedFunc]).
+// Implicit graphs are never fully explored, unless the algorithm requires it;
+// which allows traversing huge (or even infinite) search spaces.
+package graph
+
+import (
+	"github.com/MKuranowski/go-extra-lib/iter"
+)
+
+// Graph is a directed graph, whose nodes are of type T.
+type Graph[T comparable] interface {
+	// Neighbors returns an iterator over all nodes directly reachable from the provided node.
+	Neighbors(node T) iter.Iterator[T]
+}
+
+// Func is an implicit Graph, defined by a function returning neighbors of a node.
+type Func[T comparable] func(node T) iter.Iterator[T]
+
+// Neighbors returns f(node).
+func (f Func[T]) Neighbors(node T) iter.Iterator[T] { return f(node) }
+
+// Edge is a directed connection between two nodes with a specific weight (or cost).
+type Edge[T any, W iter.NumericComparable] struct {
+	From   T
+	To     T
+	Weight W
+}
+
+// WeightedGraph is a directed graph, whose nodes are of type T,
+// and whose edges have a weight of type W.
+//
+// Algorithms operating on a WeightedGraph assume that weights are non-negative.
+type WeightedGraph[T comparable, W iter.NumericComparable] interface {
+	// Edges returns an iterator over all edges starting at the provided node.
+	Edges(node T) iter.Iterator[Edge[T, W]]
+}
+
+// WeightedFunc is an implicit WeightedGraph, defined by a function returning
+// edges starting at a node.
+type WeightedFunc[T comparable, W iter.NumericComparable] func(node T) iter.Iterator[Edge[T, W]]
+
+// Edges returns f(node).
+func (f WeightedFunc[T, W]) Edges(node T) iter.Iterator[Edge[T, W]] { return f(node) }
+
+type unweighted[T comparable, W iter.NumericComparable] struct {
+	g WeightedGraph[T, W]
+}
+
+func (u unweighted[T, W]) Neighbors(node T) iter.Iterator[T] {
+	return iter.Map(u.g.Edges(node), func(e Edge[T, W]) T { return e.To })
+}
+
+// Unweighted returns a Graph view over a WeightedGraph, ignoring edge weights.
+func Unweighted[T comparable, W iter.NumericComparable](g WeightedGraph[T, W]) Graph[T] {
+	return unweighted[T, W]{g}
+}
+
+// Adjacency is an explicit, directed, weighted graph, represented as
+// a map from nodes to outgoing edges.
+//
+// Adjacency implements both [Graph] and [WeightedGraph] interfaces.
+// Unweighted graphs can simply use a weight of 1 for every edge.
+//
+// The zero value (`&Adjacency[T, W]{}`) is an empty graph.
+//
+// Nodes are kept in insertion order, making the results of algorithms on an Adjacency deterministic.
+type Adjacency[T comparable, W iter.NumericComparable] struct {
+	nodes []T
+	edges map[T][]Edge[T, W]
+}
+
+// AddNode ensures that the provided node is in the graph.
+// Returns true if the node was added.
+func (a *Adjacency[T, W]) AddNode(node T) bool {
+	if a.edges == nil {
+		a.edges = make(map[T][]Edge[T, W])
+	}
+
+	if _, has := a.edges[node]; has {
+		return false
+	}
+
+	a.nodes = append(a.nodes, node)
+	a.edges[node] = []Edge[T, W]{}
+	return true
+}
+
+// AddEdge adds a directed edge between two nodes, adding the nodes if necessary.
+//
+// Multiple edges between the same pair of nodes are allowed.
+func (a *Adjacency[T, W]) AddEdge(from, to T, weight W) {
+	a.AddNode(from)
+	a.AddNode(to)
+	a.edges[from] = append(a.edges[from], Edge[T, W]{From: from, To: to, Weight: weight})
+}
+
+// AddUndirectedEdge adds two directed edges: from a to b and from b to a.
+func (a *Adjacency[T, W]) AddUndirectedEdge(x, y T, weight W) {
+	a.AddEdge(x, y, weight)
+	a.AddEdge(y, x, weight)
+}
+
+// HasNode returns true if the provided node is in the graph.
+func (a *Adjacency[T, W]) HasNode(node T) bool {
+	_, has := a.edges[node]
+	return has
+}
+
+// HasEdge returns true if there's at least one edge from one node to another.
+//
+// Complexity: linear in terms of the number of edges starting at `from`.
+func (a *Adjacency[T, W]) HasEdge(from, to T) bool {
+	for _, e := range a.edges[from] {
+		if e.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of nodes in the graph.
+func (a *Adjacency[T, W]) Len() int { return len(a.nodes) }
+
+// Nodes returns an iterator over all nodes of the graph, in insertion order.
+func (a *Adjacency[T, W]) Nodes() iter.Iterator[T] { return iter.OverSlice(a.nodes) }
+
+// Neighbors returns an iterator over all nodes directly reachable from the provided node.
+func (a *Adjacency[T, W]) Neighbors(node T) iter.Iterator[T] {
+	return iter.Map(iter.OverSlice(a.edges[node]), func(e Edge[T, W]) T { return e.To })
+}
+
+// Edges returns an iterator over all edges starting at the provided node.
+func (a *Adjacency[T, W]) Edges(node T) iter.Iterator[Edge[T, W]] {
+	return iter.OverSlice(a.edges[node])
+}
+
+// AllEdges returns an iterator over all edges of the graph.
+func (a *Adjacency[T, W]) AllEdges() iter.Iterator[Edge[T, W]] {
+	return iter.ChainMap(a.Nodes(), a.Edges)
+}
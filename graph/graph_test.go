@@ -0,0 +1,75 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package graph_test
+
+import (
+	"testing"
+
+	. "github.com/MKuranowski/go-extra-lib/graph"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+// exampleGraph returns the following graph:
+//
+//	1 → 2 → 4
+//	↓   ↓
+//	3 → 5
+func exampleGraph() *Adjacency[int, int] {
+	g := &Adjacency[int, int]{}
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(1, 3, 1)
+	g.AddEdge(2, 4, 1)
+	g.AddEdge(2, 5, 1)
+	g.AddEdge(3, 5, 1)
+	return g
+}
+
+// collatz is an infinite, implicit graph
+var collatz = Func[int](func(n int) iter.Iterator[int] {
+	if n%2 == 0 {
+		return iter.Over(n / 2)
+	}
+	return iter.Over(3*n + 1)
+})
+
+func TestAdjacency(t *testing.T) {
+	g := exampleGraph()
+
+	check.EqMsg(t, g.Len(), 5, "g.Len()")
+	check.TrueMsg(t, g.HasNode(4), "g.HasNode(4)")
+	check.FalseMsg(t, g.HasNode(6), "g.HasNode(6)")
+	check.TrueMsg(t, g.HasEdge(1, 2), "g.HasEdge(1, 2)")
+	check.FalseMsg(t, g.HasEdge(2, 1), "g.HasEdge(2, 1)")
+
+	check.DeepEqMsg(t, iter.IntoSlice(g.Nodes()), []int{1, 2, 3, 4, 5}, "g.Nodes()")
+	check.DeepEqMsg(t, iter.IntoSlice(g.Neighbors(2)), []int{4, 5}, "g.Neighbors(2)")
+	check.DeepEqMsg(t, iter.IntoSlice(g.Neighbors(4)), []int{}, "g.Neighbors(4)")
+	check.EqMsg(t, iter.Count(g.AllEdges()), 5, "len(g.AllEdges())")
+
+	check.FalseMsg(t, g.AddNode(1), "g.AddNode(1)")
+	check.TrueMsg(t, g.AddNode(6), "g.AddNode(6)")
+}
+
+func TestAdjacencyUndirected(t *testing.T) {
+	g := &Adjacency[string, float64]{}
+	g.AddUndirectedEdge("a", "b", 1.5)
+
+	check.TrueMsg(t, g.HasEdge("a", "b"), "g.HasEdge(a, b)")
+	check.TrueMsg(t, g.HasEdge("b", "a"), "g.HasEdge(b, a)")
+	check.DeepEqMsg(
+		t,
+		iter.IntoSlice(g.Edges("b")),
+		[]Edge[string, float64]{{From: "b", To: "a", Weight: 1.5}},
+		"g.Edges(b)",
+	)
+}
+
+func TestUnweighted(t *testing.T) {
+	g := WeightedFunc[int, int](func(n int) iter.Iterator[Edge[int, int]] {
+		return iter.Over(Edge[int, int]{From: n, To: n + 1, Weight: 5})
+	})
+
+	check.DeepEqMsg(t, iter.IntoSlice(Unweighted[int, int](g).Neighbors(1)), []int{2}, "Neighbors(1)")
+}
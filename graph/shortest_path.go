@@ -0,0 +1,181 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package graph
+
+import (
+	"errors"
+
+	"github.com/MKuranowski/go-extra-lib/container/gheap"
+	"github.com/MKuranowski/go-extra-lib/container/set"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/slices2"
+)
+
+// ErrNoPath is returned by [ShortestPath] and [AStar] if the destination
+// node is not reachable from the source node.
+var ErrNoPath = errors.New("graph: no path")
+
+// Reached describes a node reached by [Dijkstra].
+type Reached[T comparable, W iter.NumericComparable] struct {
+	// Node is the reached node.
+	Node T
+
+	// Distance is the cost of the shortest path to Node.
+	Distance W
+
+	// Previous is the node before Node on the shortest path.
+	// For start nodes, Previous is equal to Node.
+	Previous T
+}
+
+type dijkstraIterator[T comparable, W iter.NumericComparable] struct {
+	g        WeightedGraph[T, W]
+	queue    *gheap.Heap[Reached[T, W]]
+	settled  set.Set[T]
+	current  Reached[T, W]
+	expand   bool
+	finished bool
+	err      error
+}
+
+func (i *dijkstraIterator[T, W]) Next() bool {
+	if i.finished {
+		return false
+	}
+
+	// Relax edges of the previously-generated node
+	if i.expand {
+		i.expand = false
+		edges := i.g.Edges(i.current.Node)
+		for edges.Next() {
+			e := edges.Get()
+			if !i.settled.Has(e.To) {
+				i.queue.Push(Reached[T, W]{Node: e.To, Distance: i.current.Distance + e.Weight, Previous: e.From})
+			}
+		}
+		if i.err = edges.Err(); i.err != nil {
+			i.finished = true
+			return false
+		}
+	}
+
+	// Pop the closest node which wasn't generated yet
+	for i.queue.Len() > 0 {
+		r := i.queue.Pop()
+		if !i.settled.Has(r.Node) {
+			i.settled.Add(r.Node)
+			i.current = r
+			i.expand = true
+			return true
+		}
+	}
+
+	i.finished = true
+	return false
+}
+
+func (i *dijkstraIterator[T, W]) Get() Reached[T, W] { return i.current }
+func (i *dijkstraIterator[T, W]) Err() error         { return i.err }
+
+func byDistance[T comparable, W iter.NumericComparable](a, b Reached[T, W]) bool {
+	return a.Distance < b.Distance
+}
+
+// Dijkstra returns an iterator over all nodes reachable from the start nodes,
+// in order of increasing distance, using Dijkstra's algorithm.
+//
+// Edge weights must not be negative.
+//
+// Neighbors of a node are only requested after the node has been generated
+// and the iterator is advanced, so Dijkstra can be used on infinite implicit graphs.
+//
+// Any errors returned by the edge iterators are forwarded to Err().
+func Dijkstra[T comparable, W iter.NumericComparable](g WeightedGraph[T, W], start ...T) iter.Iterator[Reached[T, W]] {
+	queue := make([]Reached[T, W], len(start))
+	for idx, s := range start {
+		queue[idx] = Reached[T, W]{Node: s, Previous: s}
+	}
+
+	return &dijkstraIterator[T, W]{
+		g:       g,
+		queue:   gheap.NewFunc(byDistance[T, W], queue...),
+		settled: make(set.Set[T]),
+	}
+}
+
+type aStarItem[T comparable, W iter.NumericComparable] struct {
+	node     T
+	distance W
+	estimate W // distance + heuristic(node)
+}
+
+// ShortestPath finds the shortest path between two nodes using Dijkstra's algorithm.
+//
+// Returns the path (including from and to) and its total weight,
+// or [ErrNoPath] if `to` is not reachable from `from`.
+//
+// Edge weights must not be negative. Any errors returned by the edge iterators are returned.
+func ShortestPath[T comparable, W iter.NumericComparable](g WeightedGraph[T, W], from, to T) (path []T, distance W, err error) {
+	return AStar(g, from, to, func(T) W { return 0 })
+}
+
+// AStar finds the shortest path between two nodes using the A* algorithm.
+//
+// heuristic must return an estimate of the distance from a node to the destination.
+// In order for the result to be correct, the heuristic must never overestimate the distance
+// and must be consistent (for every edge u → v: heuristic(u) <= weight(u → v) + heuristic(v)).
+//
+// Returns the path (including from and to) and its total weight,
+// or [ErrNoPath] if `to` is not reachable from `from`.
+//
+// Edge weights must not be negative. Any errors returned by the edge iterators are returned.
+func AStar[T comparable, W iter.NumericComparable](g WeightedGraph[T, W], from, to T, heuristic func(T) W) (path []T, distance W, err error) {
+	queue := gheap.NewFunc(
+		func(a, b aStarItem[T, W]) bool { return a.estimate < b.estimate },
+		aStarItem[T, W]{node: from, estimate: heuristic(from)},
+	)
+	best := map[T]W{from: 0}
+	previous := map[T]T{}
+	closed := make(set.Set[T])
+
+	for queue.Len() > 0 {
+		item := queue.Pop()
+		if closed.Has(item.node) {
+			continue
+		}
+		closed.Add(item.node)
+
+		if item.node == to {
+			// Reconstruct the path
+			path = append(path, to)
+			for n := to; n != from; {
+				n = previous[n]
+				path = append(path, n)
+			}
+			slices2.Reverse(path)
+			return path, item.distance, nil
+		}
+
+		edges := g.Edges(item.node)
+		for edges.Next() {
+			e := edges.Get()
+			if closed.Has(e.To) {
+				continue
+			}
+
+			d := item.distance + e.Weight
+			if old, seen := best[e.To]; !seen || d < old {
+				best[e.To] = d
+				previous[e.To] = item.node
+				queue.Push(aStarItem[T, W]{node: e.To, distance: d, estimate: d + heuristic(e.To)})
+			}
+		}
+		if err = edges.Err(); err != nil {
+			return
+		}
+	}
+
+	err = ErrNoPath
+	return
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package graph_test
+
+import (
+	"testing"
+
+	. "github.com/MKuranowski/go-extra-lib/graph"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/assert"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+// weightedGraph returns the following undirected graph:
+//
+//	a ─7─ b ─10─ c
+//	│     │      │
+//	9     2      1
+//	│     │      │
+//	d ─3─ e ──5─ f
+func weightedGraph() *Adjacency[string, int] {
+	g := &Adjacency[string, int]{}
+	g.AddUndirectedEdge("a", "b", 7)
+	g.AddUndirectedEdge("b", "c", 10)
+	g.AddUndirectedEdge("a", "d", 9)
+	g.AddUndirectedEdge("b", "e", 2)
+	g.AddUndirectedEdge("c", "f", 1)
+	g.AddUndirectedEdge("d", "e", 3)
+	g.AddUndirectedEdge("e", "f", 5)
+	return g
+}
+
+type point struct{ x, y int }
+
+// grid is an infinite, implicit graph of points, where moving to
+// an adjacent point costs 1, except for a "wall" at x = 2, y < 5.
+var grid = WeightedFunc[point, int](func(p point) iter.Iterator[Edge[point, int]] {
+	edges := make([]Edge[point, int], 0, 4)
+	for _, d := range []point{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		n := point{p.x + d.x, p.y + d.y}
+		if n.x == 2 && n.y < 5 {
+			continue
+		}
+		edges = append(edges, Edge[point, int]{From: p, To: n, Weight: 1})
+	}
+	return iter.OverSlice(edges)
+})
+
+func TestDijkstra(t *testing.T) {
+	it := Dijkstra[string, int](weightedGraph(), "a")
+	got := iter.IntoSlice(it)
+	check.NoErr(t, it.Err())
+
+	check.DeepEqMsg(
+		t,
+		got,
+		[]Reached[string, int]{
+			{Node: "a", Distance: 0, Previous: "a"},
+			{Node: "b", Distance: 7, Previous: "a"},
+			{Node: "d", Distance: 9, Previous: "a"},
+			{Node: "e", Distance: 9, Previous: "b"},
+			{Node: "f", Distance: 14, Previous: "e"},
+			{Node: "c", Distance: 15, Previous: "f"},
+		},
+		"Dijkstra(g, a)",
+	)
+}
+
+func TestShortestPath(t *testing.T) {
+	g := weightedGraph()
+
+	path, dist, err := ShortestPath[string, int](g, "a", "c")
+	assert.NoErr(t, err)
+	check.DeepEqMsg(t, path, []string{"a", "b", "e", "f", "c"}, "ShortestPath(g, a, c): path")
+	check.EqMsg(t, dist, 15, "ShortestPath(g, a, c): distance")
+
+	path, dist, err = ShortestPath[string, int](g, "d", "d")
+	assert.NoErr(t, err)
+	check.DeepEqMsg(t, path, []string{"d"}, "ShortestPath(g, d, d): path")
+	check.EqMsg(t, dist, 0, "ShortestPath(g, d, d): distance")
+
+	g.AddNode("z")
+	_, _, err = ShortestPath[string, int](g, "a", "z")
+	check.SpecificErr(t, err, ErrNoPath)
+}
+
+func TestAStar(t *testing.T) {
+	manhattan := func(p point) int {
+		dx, dy := p.x-4, p.y
+		if dx < 0 {
+			dx = -dx
+		}
+		if dy < 0 {
+			dy = -dy
+		}
+		return dx + dy
+	}
+
+	path, dist, err := AStar[point, int](grid, point{0, 0}, point{4, 0}, manhattan)
+	assert.NoErr(t, err)
+	check.EqMsg(t, dist, 14, "AStar(grid): distance")
+	check.EqMsg(t, len(path), 15, "AStar(grid): len(path)")
+	check.EqMsg(t, path[0], point{0, 0}, "AStar(grid): path[0]")
+	check.EqMsg(t, path[14], point{4, 0}, "AStar(grid): path[14]")
+}
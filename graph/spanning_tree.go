@@ -0,0 +1,124 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package graph
+
+import (
+	"github.com/MKuranowski/go-extra-lib/container/disjointset"
+	"github.com/MKuranowski/go-extra-lib/container/gheap"
+	"github.com/MKuranowski/go-extra-lib/container/set"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"golang.org/x/exp/slices"
+)
+
+type kruskalIterator[T comparable, W iter.NumericComparable] struct {
+	edges      []Edge[T, W]
+	components disjointset.DisjointSet[T]
+	current    Edge[T, W]
+}
+
+func (i *kruskalIterator[T, W]) Next() bool {
+	for len(i.edges) > 0 {
+		e := i.edges[0]
+		i.edges = i.edges[1:]
+		if i.components.Union(e.From, e.To) {
+			i.current = e
+			return true
+		}
+	}
+	return false
+}
+
+func (i *kruskalIterator[T, W]) Get() Edge[T, W] { return i.current }
+func (i *kruskalIterator[T, W]) Err() error      { return nil }
+
+// Kruskal returns an iterator over edges of the minimum spanning forest
+// of an undirected graph, using Kruskal's algorithm.
+//
+// Edges are treated as undirected. Every edge of the graph must be provided at least once;
+// providing both directions of an edge is allowed.
+//
+// All edges are collected and sorted on the call to Kruskal; edges of the
+// spanning forest are then generated lazily, in order of increasing weight.
+// If the provided iterator fails, the returned iterator generates no elements and
+// forwards the error.
+//
+// See [Prim] for an algorithm working on (possibly implicit) connected graphs.
+func Kruskal[T comparable, W iter.NumericComparable](edges iter.Iterator[Edge[T, W]]) iter.Iterator[Edge[T, W]] {
+	all := make([]Edge[T, W], 0)
+	for edges.Next() {
+		all = append(all, edges.Get())
+	}
+	if err := edges.Err(); err != nil {
+		return iter.Error[Edge[T, W]](err)
+	}
+
+	slices.SortStableFunc(all, func(a, b Edge[T, W]) bool { return a.Weight < b.Weight })
+	return &kruskalIterator[T, W]{edges: all}
+}
+
+type primIterator[T comparable, W iter.NumericComparable] struct {
+	g        WeightedGraph[T, W]
+	queue    *gheap.Heap[Edge[T, W]]
+	inTree   set.Set[T]
+	expand   T
+	current  Edge[T, W]
+	finished bool
+	err      error
+}
+
+func (i *primIterator[T, W]) Next() bool {
+	if i.finished {
+		return false
+	}
+
+	// Add edges of the recently added node
+	edges := i.g.Edges(i.expand)
+	for edges.Next() {
+		e := edges.Get()
+		if !i.inTree.Has(e.To) {
+			i.queue.Push(e)
+		}
+	}
+	if i.err = edges.Err(); i.err != nil {
+		i.finished = true
+		return false
+	}
+
+	// Find the cheapest edge leading outside of the tree
+	for i.queue.Len() > 0 {
+		e := i.queue.Pop()
+		if !i.inTree.Has(e.To) {
+			i.inTree.Add(e.To)
+			i.current, i.expand = e, e.To
+			return true
+		}
+	}
+
+	i.finished = true
+	return false
+}
+
+func (i *primIterator[T, W]) Get() Edge[T, W] { return i.current }
+func (i *primIterator[T, W]) Err() error      { return i.err }
+
+// Prim returns an iterator over edges of the minimum spanning tree of
+// the part of the graph reachable from the start node, using Prim's algorithm.
+//
+// The graph must be undirected - for every edge u → v, there must be an edge v → u
+// with the same weight.
+//
+// The graph is explored lazily - edges of a node are only requested after
+// the node is added to the tree and the iterator is advanced.
+//
+// Any errors returned by the edge iterators are forwarded to Err().
+//
+// See [Kruskal] for an algorithm working on disconnected graphs.
+func Prim[T comparable, W iter.NumericComparable](g WeightedGraph[T, W], start T) iter.Iterator[Edge[T, W]] {
+	return &primIterator[T, W]{
+		g:      g,
+		queue:  gheap.NewFunc(func(a, b Edge[T, W]) bool { return a.Weight < b.Weight }),
+		inTree: set.Set[T]{start: {}},
+		expand: start,
+	}
+}
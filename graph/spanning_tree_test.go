@@ -0,0 +1,58 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package graph_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/MKuranowski/go-extra-lib/graph"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func totalWeight(edges []Edge[string, int]) int {
+	return iter.Sum(iter.Map(iter.OverSlice(edges), func(e Edge[string, int]) int { return e.Weight }))
+}
+
+func TestKruskal(t *testing.T) {
+	edges := iter.IntoSlice(Kruskal(weightedGraph().AllEdges()))
+
+	check.EqMsg(t, len(edges), 5, "len(Kruskal(g))")
+	check.EqMsg(t, totalWeight(edges), 18, "weight of Kruskal(g)")
+	check.DeepEqMsg(t, edges[0], Edge[string, int]{From: "c", To: "f", Weight: 1}, "Kruskal(g)[0]")
+}
+
+func TestKruskalForest(t *testing.T) {
+	g := weightedGraph()
+	g.AddUndirectedEdge("x", "y", 4)
+
+	check.EqMsg(t, iter.Count(Kruskal(g.AllEdges())), 6, "len(Kruskal(g))")
+}
+
+func TestKruskalErr(t *testing.T) {
+	errTest := errors.New("test")
+	it := Kruskal(iter.Error[Edge[string, int]](errTest))
+	check.FalseMsg(t, it.Next(), "it.Next()")
+	check.SpecificErr(t, it.Err(), errTest)
+}
+
+func TestPrim(t *testing.T) {
+	it := Prim[string, int](weightedGraph(), "a")
+	edges := iter.IntoSlice(it)
+	check.NoErr(t, it.Err())
+
+	check.DeepEqMsg(
+		t,
+		edges,
+		[]Edge[string, int]{
+			{From: "a", To: "b", Weight: 7},
+			{From: "b", To: "e", Weight: 2},
+			{From: "e", To: "d", Weight: 3},
+			{From: "e", To: "f", Weight: 5},
+			{From: "f", To: "c", Weight: 1},
+		},
+		"Prim(g, a)",
+	)
+}
@@ -0,0 +1,353 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package graph
+
+import (
+	"errors"
+
+	"github.com/MKuranowski/go-extra-lib/container/set"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/slices2"
+)
+
+// ErrCycle is returned by the Err() method of the [TopologicalSort] iterator,
+// if the graph contains a cycle.
+var ErrCycle = errors.New("graph: cycle detected")
+
+type bfsIterator[T comparable] struct {
+	g        Graph[T]
+	queue    []T
+	seen     set.Set[T]
+	current  T
+	expand   bool
+	finished bool
+	err      error
+}
+
+func (i *bfsIterator[T]) Next() bool {
+	if i.finished {
+		return false
+	}
+
+	// Enqueue neighbors of the previously-generated node
+	if i.expand {
+		i.expand = false
+		neighbors := i.g.Neighbors(i.current)
+		for neighbors.Next() {
+			n := neighbors.Get()
+			if !i.seen.Has(n) {
+				i.seen.Add(n)
+				i.queue = append(i.queue, n)
+			}
+		}
+		if i.err = neighbors.Err(); i.err != nil {
+			i.finished = true
+			return false
+		}
+	}
+
+	if len(i.queue) == 0 {
+		i.finished = true
+		return false
+	}
+
+	var zero T
+	i.current, i.queue[0], i.queue = i.queue[0], zero, i.queue[1:]
+	i.expand = true
+	return true
+}
+
+func (i *bfsIterator[T]) Get() T     { return i.current }
+func (i *bfsIterator[T]) Err() error { return i.err }
+
+// BFS returns an iterator over all nodes reachable from the start nodes,
+// in breadth-first order. Start nodes are generated first.
+//
+// Neighbors of a node are only requested after the node has been generated
+// and the iterator is advanced, so BFS can be used on infinite implicit graphs.
+//
+//	// 1 → 2 → 4
+//	// ↓   ↓
+//	// 3 → 5
+//	BFS(g, 1) → [1 2 3 4 5]
+//
+// Any errors returned by the neighbor iterators are forwarded to Err().
+func BFS[T comparable](g Graph[T], start ...T) iter.Iterator[T] {
+	i := &bfsIterator[T]{g: g, seen: make(set.Set[T])}
+	for _, s := range start {
+		if !i.seen.Has(s) {
+			i.seen.Add(s)
+			i.queue = append(i.queue, s)
+		}
+	}
+	return i
+}
+
+type dfsIterator[T comparable] struct {
+	g        Graph[T]
+	stack    []iter.Iterator[T]
+	seen     set.Set[T]
+	current  T
+	expand   bool
+	finished bool
+	err      error
+}
+
+func (i *dfsIterator[T]) Next() bool {
+	if i.finished {
+		return false
+	}
+
+	// Descend into the previously-generated node
+	if i.expand {
+		i.expand = false
+		i.stack = append(i.stack, i.g.Neighbors(i.current))
+	}
+
+	for len(i.stack) > 0 {
+		top := i.stack[len(i.stack)-1]
+
+		if top.Next() {
+			n := top.Get()
+			if !i.seen.Has(n) {
+				i.seen.Add(n)
+				i.current = n
+				i.expand = true
+				return true
+			}
+			continue
+		}
+
+		if i.err = top.Err(); i.err != nil {
+			break
+		}
+		i.stack = slices2.DeleteAndSetToZero(i.stack, len(i.stack)-1, len(i.stack))
+	}
+
+	i.finished = true
+	return false
+}
+
+func (i *dfsIterator[T]) Get() T     { return i.current }
+func (i *dfsIterator[T]) Err() error { return i.err }
+
+// DFS returns an iterator over all nodes reachable from the start nodes,
+// in depth-first pre-order.
+//
+// Neighbors of a node are only requested after the node has been generated
+// and the iterator is advanced, and only a single neighbor iterator per depth level
+// is kept in memory.
+//
+//	// 1 → 2 → 4
+//	// ↓   ↓
+//	// 3 → 5
+//	DFS(g, 1) → [1 2 4 5 3]
+//
+// Any errors returned by the neighbor iterators are forwarded to Err().
+func DFS[T comparable](g Graph[T], start ...T) iter.Iterator[T] {
+	return &dfsIterator[T]{g: g, stack: []iter.Iterator[T]{iter.OverSlice(start)}, seen: make(set.Set[T])}
+}
+
+type dfsFrame[T comparable] struct {
+	node      T
+	neighbors iter.Iterator[T]
+}
+
+const (
+	unvisited uint8 = iota
+	inProgress
+	visited
+)
+
+func topologicalOrder[T comparable](g Graph[T], nodes []T) ([]T, error) {
+	state := make(map[T]uint8)
+	order := make([]T, 0)
+
+	for _, start := range nodes {
+		if state[start] != unvisited {
+			continue
+		}
+
+		state[start] = inProgress
+		stack := []dfsFrame[T]{{start, g.Neighbors(start)}}
+
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+
+			if top.neighbors.Next() {
+				n := top.neighbors.Get()
+				switch state[n] {
+				case unvisited:
+					state[n] = inProgress
+					stack = append(stack, dfsFrame[T]{n, g.Neighbors(n)})
+				case inProgress:
+					return nil, ErrCycle
+				}
+				continue
+			}
+
+			if err := top.neighbors.Err(); err != nil {
+				return nil, err
+			}
+
+			state[top.node] = visited
+			order = append(order, top.node)
+			stack = slices2.DeleteAndSetToZero(stack, len(stack)-1, len(stack))
+		}
+	}
+
+	slices2.Reverse(order)
+	return order, nil
+}
+
+type topologicalSortIterator[T comparable] struct {
+	g       Graph[T]
+	nodes   []T
+	order   iter.Iterator[T]
+	err     error
+	started bool
+}
+
+func (i *topologicalSortIterator[T]) Next() bool {
+	if !i.started {
+		i.started = true
+		order, err := topologicalOrder(i.g, i.nodes)
+		i.order, i.err = iter.OverSlice(order), err
+	}
+	return i.err == nil && i.order.Next()
+}
+
+func (i *topologicalSortIterator[T]) Get() T     { return i.order.Get() }
+func (i *topologicalSortIterator[T]) Err() error { return i.err }
+
+// TopologicalSort returns an iterator over all nodes reachable from the provided nodes,
+// ordered in such a way that for every edge u → v, u comes before v.
+//
+// The whole reachable part of the graph is explored on the first call to Next().
+//
+// If the graph contains a cycle, no elements are generated and the Err() method
+// returns [ErrCycle]. Any errors returned by the neighbor iterators are also forwarded to Err().
+//
+//	// shirt → tie → jacket
+//	//   ↓              ↑
+//	// belt ────────────┘
+//	TopologicalSort(g, "shirt") → ["shirt" "belt" "tie" "jacket"]
+func TopologicalSort[T comparable](g Graph[T], nodes ...T) iter.Iterator[T] {
+	return &topologicalSortIterator[T]{g: g, nodes: nodes}
+}
+
+type sccIterator[T comparable] struct {
+	g     Graph[T]
+	roots []T
+
+	counter int
+	index   map[T]int
+	low     map[T]int
+	stack   []T
+	onStack set.Set[T]
+	calls   []dfsFrame[T]
+
+	component []T
+	err       error
+}
+
+func (i *sccIterator[T]) visit(node T) {
+	i.index[node] = i.counter
+	i.low[node] = i.counter
+	i.counter++
+	i.stack = append(i.stack, node)
+	i.onStack.Add(node)
+	i.calls = append(i.calls, dfsFrame[T]{node, i.g.Neighbors(node)})
+}
+
+func (i *sccIterator[T]) Next() bool {
+	if i.err != nil {
+		return false
+	}
+
+	for {
+		// Start a new DFS from the next unvisited root
+		if len(i.calls) == 0 {
+			for len(i.roots) > 0 {
+				root := i.roots[0]
+				i.roots = i.roots[1:]
+				if _, visited := i.index[root]; !visited {
+					i.visit(root)
+					break
+				}
+			}
+			if len(i.calls) == 0 {
+				return false
+			}
+		}
+
+		top := i.calls[len(i.calls)-1]
+
+		if top.neighbors.Next() {
+			n := top.neighbors.Get()
+			if _, visited := i.index[n]; !visited {
+				i.visit(n)
+			} else if i.onStack.Has(n) && i.index[n] < i.low[top.node] {
+				i.low[top.node] = i.index[n]
+			}
+			continue
+		}
+
+		if i.err = top.neighbors.Err(); i.err != nil {
+			return false
+		}
+
+		// Return from the recursive call
+		i.calls = slices2.DeleteAndSetToZero(i.calls, len(i.calls)-1, len(i.calls))
+		if len(i.calls) > 0 {
+			parent := i.calls[len(i.calls)-1].node
+			if i.low[top.node] < i.low[parent] {
+				i.low[parent] = i.low[top.node]
+			}
+		}
+
+		// Pop a strongly connected component
+		if i.low[top.node] == i.index[top.node] {
+			j := len(i.stack) - 1
+			for i.stack[j] != top.node {
+				j--
+			}
+			i.component = make([]T, len(i.stack)-j)
+			copy(i.component, i.stack[j:])
+			for _, n := range i.component {
+				i.onStack.Remove(n)
+			}
+			i.stack = slices2.DeleteAndSetToZero(i.stack, j, len(i.stack))
+			return true
+		}
+	}
+}
+
+func (i *sccIterator[T]) Get() []T   { return i.component }
+func (i *sccIterator[T]) Err() error { return i.err }
+
+// StronglyConnectedComponents returns an iterator over strongly connected components
+// of the part of the graph reachable from the provided nodes, using Tarjan's algorithm.
+//
+// A strongly connected component is a maximal set of nodes, where every node is
+// reachable from every other node. Components are generated in reverse topological order -
+// if there's an edge from component A to component B, B is generated before A.
+//
+// The graph is explored lazily, just enough to find the next component.
+//
+//	// 1 → 2 → 3 → 4
+//	//     ↑   ↓
+//	//     └── 5
+//	StronglyConnectedComponents(g, 1) → [[4] [2 3 5] [1]]
+//
+// Any errors returned by the neighbor iterators are forwarded to Err().
+func StronglyConnectedComponents[T comparable](g Graph[T], nodes ...T) iter.Iterator[[]T] {
+	return &sccIterator[T]{
+		g:       g,
+		roots:   nodes,
+		index:   make(map[T]int),
+		low:     make(map[T]int),
+		onStack: make(set.Set[T]),
+	}
+}
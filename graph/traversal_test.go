@@ -0,0 +1,117 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package graph_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/MKuranowski/go-extra-lib/graph"
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func TestBFS(t *testing.T) {
+	g := exampleGraph()
+
+	check.DeepEqMsg(t, iter.IntoSlice(BFS[int](g, 1)), []int{1, 2, 3, 4, 5}, "BFS(g, 1)")
+	check.DeepEqMsg(t, iter.IntoSlice(BFS[int](g, 2)), []int{2, 4, 5}, "BFS(g, 2)")
+	check.DeepEqMsg(t, iter.IntoSlice(BFS[int](g, 3, 2)), []int{3, 2, 5, 4}, "BFS(g, 3, 2)")
+	check.DeepEqMsg(t, iter.IntoSlice(BFS[int](g)), []int{}, "BFS(g)")
+}
+
+func TestBFSInfinite(t *testing.T) {
+	check.DeepEqMsg(
+		t,
+		iter.IntoSlice(iter.TakeWhile(BFS[int](collatz, 6), func(n int) bool { return n != 1 })),
+		[]int{6, 3, 10, 5, 16, 8, 4, 2},
+		"BFS(collatz, 6)",
+	)
+}
+
+func TestDFS(t *testing.T) {
+	g := exampleGraph()
+
+	check.DeepEqMsg(t, iter.IntoSlice(DFS[int](g, 1)), []int{1, 2, 4, 5, 3}, "DFS(g, 1)")
+	check.DeepEqMsg(t, iter.IntoSlice(DFS[int](g, 3, 2)), []int{3, 5, 2, 4}, "DFS(g, 3, 2)")
+	check.DeepEqMsg(t, iter.IntoSlice(DFS[int](g)), []int{}, "DFS(g)")
+}
+
+func TestDFSInfinite(t *testing.T) {
+	check.DeepEqMsg(
+		t,
+		iter.IntoSlice(iter.Limit(DFS[int](collatz, 7), 5)),
+		[]int{7, 22, 11, 34, 17},
+		"DFS(collatz, 7)",
+	)
+}
+
+func TestTraversalErr(t *testing.T) {
+	errTest := errors.New("test")
+	g := Func[int](func(n int) iter.Iterator[int] {
+		if n == 2 {
+			return iter.Error[int](errTest)
+		}
+		return iter.Over(n + 1)
+	})
+
+	bfs := BFS[int](g, 0)
+	check.DeepEqMsg(t, iter.IntoSlice(bfs), []int{0, 1, 2}, "BFS(g, 0)")
+	check.SpecificErr(t, bfs.Err(), errTest)
+
+	dfs := DFS[int](g, 0)
+	check.DeepEqMsg(t, iter.IntoSlice(dfs), []int{0, 1, 2}, "DFS(g, 0)")
+	check.SpecificErr(t, dfs.Err(), errTest)
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := &Adjacency[string, int]{}
+	g.AddEdge("shirt", "tie", 1)
+	g.AddEdge("tie", "jacket", 1)
+	g.AddEdge("shirt", "belt", 1)
+	g.AddEdge("belt", "jacket", 1)
+	g.AddEdge("trousers", "belt", 1)
+	g.AddEdge("trousers", "shoes", 1)
+	g.AddEdge("socks", "shoes", 1)
+
+	it := TopologicalSort[string](g, iter.IntoSlice(g.Nodes())...)
+	order := iter.IntoSlice(it)
+	check.NoErr(t, it.Err())
+
+	check.DeepEqMsg(
+		t,
+		order,
+		[]string{"socks", "trousers", "shoes", "shirt", "belt", "tie", "jacket"},
+		"TopologicalSort(g)",
+	)
+}
+
+func TestTopologicalSortCycle(t *testing.T) {
+	g := exampleGraph()
+	g.AddEdge(5, 1, 1)
+
+	it := TopologicalSort[int](g, 1)
+	check.DeepEqMsg(t, iter.IntoSlice(it), []int{}, "TopologicalSort(g, 1)")
+	check.SpecificErr(t, it.Err(), ErrCycle)
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	g := &Adjacency[int, int]{}
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(2, 3, 1)
+	g.AddEdge(3, 4, 1)
+	g.AddEdge(3, 5, 1)
+	g.AddEdge(5, 2, 1)
+	g.AddEdge(6, 6, 1)
+	g.AddEdge(6, 4, 1)
+
+	it := StronglyConnectedComponents[int](g, iter.IntoSlice(g.Nodes())...)
+	check.DeepEqMsg(
+		t,
+		iter.IntoSlice(it),
+		[][]int{{4}, {2, 3, 5}, {1}, {6}},
+		"StronglyConnectedComponents(g)",
+	)
+	check.NoErr(t, it.Err())
+}
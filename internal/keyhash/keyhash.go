@@ -0,0 +1,113 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// keyhash computes hashes of arbitrary comparable values,
+// for use in hash-based containers.
+package keyhash
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+)
+
+// Of computes a hash of any comparable value, consistent with the == operator:
+// equal keys always have equal hashes.
+func Of[K comparable](seed maphash.Seed, key K) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+
+	// Fast paths for the most common key types
+	switch k := any(key).(type) {
+	case string:
+		h.WriteString(k)
+	case int:
+		writeUint64(&h, uint64(k))
+	case int64:
+		writeUint64(&h, uint64(k))
+	case uint64:
+		writeUint64(&h, k)
+	default:
+		hashValue(&h, reflect.ValueOf(&key).Elem())
+	}
+
+	return h.Sum64()
+}
+
+func writeUint64(h *maphash.Hash, x uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], x)
+	h.Write(b[:])
+}
+
+func writeFloat64(h *maphash.Hash, x float64) {
+	switch {
+	case x == 0:
+		// +0 and -0 are equal
+		writeUint64(h, 0)
+	case x != x:
+		// NaN is never equal to anything, any hash will do
+		writeUint64(h, math.Float64bits(math.NaN()))
+	default:
+		writeUint64(h, math.Float64bits(x))
+	}
+}
+
+// hashValue feeds a comparable value into the hash.
+func hashValue(h *maphash.Hash, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(h, uint64(v.Int()))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(h, v.Uint())
+
+	case reflect.Float32, reflect.Float64:
+		writeFloat64(h, v.Float())
+
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeFloat64(h, real(c))
+		writeFloat64(h, imag(c))
+
+	case reflect.String:
+		h.WriteString(v.String())
+
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint64(h, uint64(v.Pointer()))
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			hashValue(h, v.Index(i))
+		}
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			// Blank fields are ignored by ==
+			if v.Type().Field(i).Name != "_" {
+				hashValue(h, v.Field(i))
+			}
+		}
+
+	case reflect.Interface:
+		if v.IsNil() {
+			h.WriteByte(0)
+		} else {
+			// Values of different dynamic types may collide,
+			// which is fine, as they're never equal
+			h.WriteByte(1)
+			hashValue(h, v.Elem())
+		}
+
+	default:
+		panic("keyhash: unhashable key of kind " + v.Kind().String())
+	}
+}
@@ -6,6 +6,7 @@ package iter
 import (
 	"fmt"
 
+	"github.com/MKuranowski/go-extra-lib/container/gheap"
 	"golang.org/x/exp/constraints"
 )
 
@@ -188,6 +189,34 @@ func AllFunc[T any](i Iterator[T], f func(T) bool) bool {
 	return true
 }
 
+// BottomK returns the k smallest elements from the iterator, in ascending order,
+// as by the `<` operator. If the iterator has fewer than k elements, returns all of them.
+//
+// Only k elements are kept in memory at once (in a bounded heap),
+// making this function suitable for streams of arbitrary length.
+//
+//	BottomK([5 1 4 2 3], 2) → [1 2]
+//	BottomK([5 1], 3) → [1 5]
+//	BottomK([], 3) → []
+//
+// Complexity: O(n log k)
+func BottomK[T constraints.Ordered](i Iterator[T], k int) []T {
+	return BottomKFunc(i, k, func(a, b T) bool { return a < b })
+}
+
+// BottomKFunc returns the k smallest elements from the iterator, ordered by less.
+// If the iterator has fewer than k elements, returns all of them.
+//
+// Only k elements are kept in memory at once (in a bounded heap),
+// making this function suitable for streams of arbitrary length.
+//
+// If the provided iterator implements [VolatileIterator], uses GetCopy() instead of Get().
+//
+// Complexity: O(n log k)
+func BottomKFunc[T any](i Iterator[T], k int, less func(T, T) bool) []T {
+	return TopKFunc(i, k, func(a, b T) bool { return less(b, a) })
+}
+
 // Count exhausts the iterator and returns the number of elements encountered.
 //
 //	Count([1 2 3]) → 3
@@ -718,3 +747,51 @@ func (i *takeWhileIterator[T]) Err() error { return i.i.Err() }
 func TakeWhile[T any](i Iterator[T], pred func(T) bool) Iterator[T] {
 	return &takeWhileIterator[T]{i: i, pred: pred}
 }
+
+// TopK returns the k greatest elements from the iterator, in descending order,
+// as by the `<` operator. If the iterator has fewer than k elements, returns all of them.
+//
+// Only k elements are kept in memory at once (in a bounded heap),
+// making this function suitable for streams of arbitrary length.
+//
+//	TopK([5 1 4 2 3], 2) → [5 4]
+//	TopK([1 5], 3) → [5 1]
+//	TopK([], 3) → []
+//
+// Complexity: O(n log k)
+func TopK[T constraints.Ordered](i Iterator[T], k int) []T {
+	return TopKFunc(i, k, func(a, b T) bool { return a < b })
+}
+
+// TopKFunc returns the k greatest elements from the iterator, ordered by less,
+// in descending order. If the iterator has fewer than k elements, returns all of them.
+//
+// Only k elements are kept in memory at once (in a bounded heap),
+// making this function suitable for streams of arbitrary length.
+//
+// If the provided iterator implements [VolatileIterator], uses GetCopy() instead of Get().
+//
+// Complexity: O(n log k)
+func TopKFunc[T any](i Iterator[T], k int, less func(T, T) bool) []T {
+	if k <= 0 {
+		return []T{}
+	}
+
+	i = ToNonVolatile(i)
+
+	// h is a min-heap, with the smallest of the k greatest elements at the top
+	h := gheap.NewFunc(less)
+	for i.Next() {
+		if h.Len() < k {
+			h.Push(i.Get())
+		} else {
+			h.PushPop(i.Get())
+		}
+	}
+
+	r := make([]T, h.Len())
+	for j := len(r) - 1; j >= 0; j-- {
+		r[j] = h.Pop()
+	}
+	return r
+}
@@ -216,6 +216,38 @@ func TestAllFunc(t *testing.T) {
 	)
 }
 
+func TestBottomK(t *testing.T) {
+	check.DeepEqMsg(t, BottomK(Over(5, 1, 4, 2, 3), 2), []int{1, 2}, "BottomK([5 1 4 2 3], 2)")
+	check.DeepEqMsg(t, BottomK(Over(5, 1), 3), []int{1, 5}, "BottomK([5 1], 3)")
+	check.DeepEqMsg(t, BottomK(Empty[int](), 3), []int{}, "BottomK([], 3)")
+}
+
+func TestBottomKFunc(t *testing.T) {
+	check.DeepEqMsg(
+		t,
+		BottomKFunc(
+			Over(
+				person{"Alice", 30},
+				person{"Bob", 25},
+				person{"Charlie", 41},
+			),
+			2,
+			younger,
+		),
+		[]person{{"Bob", 25}, {"Alice", 30}},
+		"BottomKFunc([{\"Alice\", 30}, {\"Bob\", 25}, {\"Charlie\", 41}], 2, younger)",
+	)
+}
+
+func TestBottomKFuncVolatile(t *testing.T) {
+	check.DeepEqMsg(
+		t,
+		BottomKFunc(Permutations(2, 1, 2, 3), 2, lexicographicLess),
+		[][]int{{1, 2}, {1, 3}},
+		"BottomKFunc(Permutations(2, 1, 2, 3), 2, lexicographicLess)",
+	)
+}
+
 func TestCount(t *testing.T) {
 	check.EqMsg(t, Count(Over(1, 2, 3)), 3, "Count([1 2 3])")
 	check.EqMsg(t, Count(Empty[int]()), 0, "Count([])")
@@ -596,3 +628,47 @@ func TestTakeWhile(t *testing.T) {
 		"TakeWhile([3 2 1], x => x < 3)",
 	)
 }
+
+func TestTopK(t *testing.T) {
+	check.DeepEqMsg(t, TopK(Over(5, 1, 4, 2, 3), 2), []int{5, 4}, "TopK([5 1 4 2 3], 2)")
+	check.DeepEqMsg(t, TopK(Over(1, 5), 3), []int{5, 1}, "TopK([1 5], 3)")
+	check.DeepEqMsg(t, TopK(Empty[int](), 3), []int{}, "TopK([], 3)")
+	check.DeepEqMsg(t, TopK(Over(1, 2, 3), 0), []int{}, "TopK([1 2 3], 0)")
+	check.DeepEqMsg(t, TopK(Range(1000), 3), []int{999, 998, 997}, "TopK(Range(1000), 3)")
+}
+
+// lexicographicLess compares two int slices lexicographically.
+func lexicographicLess(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func TestTopKFuncVolatile(t *testing.T) {
+	check.DeepEqMsg(
+		t,
+		TopKFunc(Permutations(2, 1, 2, 3), 2, lexicographicLess),
+		[][]int{{3, 2}, {3, 1}},
+		"TopKFunc(Permutations(2, 1, 2, 3), 2, lexicographicLess)",
+	)
+}
+
+func TestTopKFunc(t *testing.T) {
+	check.DeepEqMsg(
+		t,
+		TopKFunc(
+			Over(
+				person{"Alice", 30},
+				person{"Bob", 25},
+				person{"Charlie", 41},
+			),
+			2,
+			younger,
+		),
+		[]person{{"Charlie", 41}, {"Alice", 30}},
+		"TopKFunc([{\"Alice\", 30}, {\"Bob\", 25}, {\"Charlie\", 41}], 2, younger)",
+	)
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package maps2
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// SliceStrategy describes how [DeepMerge] combines two []any values under the same key.
+type SliceStrategy uint8
+
+const (
+	// SliceReplace makes the slice from the later map replace the earlier one.
+	SliceReplace SliceStrategy = iota
+
+	// SliceAppend concatenates both slices.
+	SliceAppend
+
+	// SliceUnion appends elements of the later slice which are not (reflect.DeepEqual)
+	// present in the earlier one.
+	SliceUnion
+)
+
+// DeepMerge merges nested map[string]any structures, like the ones produced by
+// unmarshaling JSON into an `any`. Later maps take precedence over earlier ones.
+//
+// If both values under a key are map[string]any, they are merged recursively.
+// If both values are []any, they are combined according to the provided strategy.
+// Otherwise, the value from the later map replaces the earlier one.
+//
+// Nested maps and slices are copied, so the result never aliases the arguments.
+//
+// Example:
+//
+//	DeepMerge(
+//		SliceAppend,
+//		map[string]any{"db": map[string]any{"host": "localhost", "port": 5432}, "tags": []any{"a"}},
+//		map[string]any{"db": map[string]any{"host": "example.com"}, "tags": []any{"b"}},
+//	)
+//	// → map[db:map[host:example.com port:5432] tags:[a b]]
+func DeepMerge(strategy SliceStrategy, layers ...map[string]any) map[string]any {
+	r := make(map[string]any)
+	for _, layer := range layers {
+		deepMergeInto(r, layer, strategy)
+	}
+	return r
+}
+
+func deepMergeInto(dst, src map[string]any, strategy SliceStrategy) {
+	for k, v := range src {
+		if existing, ok := dst[k]; ok {
+			dst[k] = deepMergeValues(existing, v, strategy)
+		} else {
+			dst[k] = deepCopy(v)
+		}
+	}
+}
+
+func deepMergeValues(a, b any, strategy SliceStrategy) any {
+	switch a := a.(type) {
+	case map[string]any:
+		if b, ok := b.(map[string]any); ok {
+			// a has already been copied into the result, safe to modify
+			deepMergeInto(a, b, strategy)
+			return a
+		}
+
+	case []any:
+		if b, ok := b.([]any); ok {
+			switch strategy {
+			case SliceReplace:
+				return deepCopy(b)
+
+			case SliceAppend:
+				for _, x := range b {
+					a = append(a, deepCopy(x))
+				}
+				return a
+
+			case SliceUnion:
+				for _, x := range b {
+					if !slices.ContainsFunc(a, func(y any) bool { return reflect.DeepEqual(x, y) }) {
+						a = append(a, deepCopy(x))
+					}
+				}
+				return a
+
+			default:
+				panic(fmt.Sprintf("maps2: invalid SliceStrategy: %d", strategy))
+			}
+		}
+	}
+
+	return deepCopy(b)
+}
+
+func deepCopy(x any) any {
+	switch x := x.(type) {
+	case map[string]any:
+		r := make(map[string]any, len(x))
+		for k, v := range x {
+			r[k] = deepCopy(v)
+		}
+		return r
+
+	case []any:
+		r := make([]any, len(x))
+		for i, v := range x {
+			r[i] = deepCopy(v)
+		}
+		return r
+
+	default:
+		return x
+	}
+}
+
+// ChangeKind describes the type of a [PathChange].
+type ChangeKind uint8
+
+const (
+	// ChangeAdded means a key is present only in the new map.
+	ChangeAdded ChangeKind = iota
+
+	// ChangeRemoved means a key is present only in the old map.
+	ChangeRemoved
+
+	// ChangeChanged means a key is present in both maps, but with different values.
+	ChangeChanged
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeChanged:
+		return "changed"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", k)
+	}
+}
+
+// PathChange describes a single difference between two nested map[string]any structures.
+type PathChange struct {
+	// Path contains keys leading to the changed value, starting from the outermost map.
+	Path []string
+
+	Kind ChangeKind
+
+	// Old is the value before the change, nil if Kind == ChangeAdded.
+	Old any
+
+	// New is the value after the change, nil if Kind == ChangeRemoved.
+	New any
+}
+
+// String returns a human-readable representation of the change,
+// e.g. "changed db.port: 5432 → 5433".
+func (c PathChange) String() string {
+	path := strings.Join(c.Path, ".")
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("added %s: %v", path, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("removed %s: %v", path, c.Old)
+	default:
+		return fmt.Sprintf("%s %s: %v → %v", c.Kind, path, c.Old, c.New)
+	}
+}
+
+// DeepDiff returns all differences between nested map[string]any structures,
+// ordered by path.
+//
+// Maps are compared key-by-key, recursively. All other values (including slices)
+// are compared as a whole, using reflect.DeepEqual.
+//
+// This is useful for reporting what has changed after reloading a configuration file,
+// e.g. after [github.com/MKuranowski/go-extra-lib/resource.Interface] reports a change.
+//
+// Example:
+//
+//	DeepDiff(
+//		map[string]any{"db": map[string]any{"host": "localhost", "port": 5432}},
+//		map[string]any{"db": map[string]any{"host": "localhost", "port": 5433}, "debug": true},
+//	)
+//	// → [added debug: true, changed db.port: 5432 → 5433]
+func DeepDiff(old, new map[string]any) []PathChange {
+	var changes []PathChange
+	deepDiff(nil, old, new, &changes)
+	return changes
+}
+
+func deepDiff(prefix []string, old, new map[string]any, changes *[]PathChange) {
+	keys := make([]string, 0, len(old)+len(new))
+	for k := range old {
+		keys = append(keys, k)
+	}
+	for k := range new {
+		if _, inOld := old[k]; !inOld {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		path := append(prefix[:len(prefix):len(prefix)], k)
+		oldV, inOld := old[k]
+		newV, inNew := new[k]
+
+		switch {
+		case !inOld:
+			*changes = append(*changes, PathChange{Path: path, Kind: ChangeAdded, New: newV})
+
+		case !inNew:
+			*changes = append(*changes, PathChange{Path: path, Kind: ChangeRemoved, Old: oldV})
+
+		default:
+			oldM, oldIsMap := oldV.(map[string]any)
+			newM, newIsMap := newV.(map[string]any)
+			if oldIsMap && newIsMap {
+				deepDiff(path, oldM, newM, changes)
+			} else if !reflect.DeepEqual(oldV, newV) {
+				*changes = append(*changes, PathChange{Path: path, Kind: ChangeChanged, Old: oldV, New: newV})
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package maps2_test
+
+import (
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/maps2"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func TestDeepMerge(t *testing.T) {
+	defaults := map[string]any{
+		"db":    map[string]any{"host": "localhost", "port": 5432},
+		"tags":  []any{"a", "b"},
+		"debug": false,
+	}
+	file := map[string]any{
+		"db":   map[string]any{"host": "example.com", "options": map[string]any{"ssl": true}},
+		"tags": []any{"b", "c"},
+	}
+	env := map[string]any{
+		"debug": true,
+	}
+
+	check.DeepEqMsg(
+		t,
+		maps2.DeepMerge(maps2.SliceReplace, defaults, file, env),
+		map[string]any{
+			"db":    map[string]any{"host": "example.com", "port": 5432, "options": map[string]any{"ssl": true}},
+			"tags":  []any{"b", "c"},
+			"debug": true,
+		},
+		"DeepMerge(SliceReplace)",
+	)
+
+	check.DeepEqMsg(
+		t,
+		maps2.DeepMerge(maps2.SliceAppend, defaults, file)["tags"],
+		[]any{"a", "b", "b", "c"},
+		"DeepMerge(SliceAppend)[tags]",
+	)
+
+	check.DeepEqMsg(
+		t,
+		maps2.DeepMerge(maps2.SliceUnion, defaults, file)["tags"],
+		[]any{"a", "b", "c"},
+		"DeepMerge(SliceUnion)[tags]",
+	)
+}
+
+func TestDeepMergeTypeMismatch(t *testing.T) {
+	check.DeepEqMsg(
+		t,
+		maps2.DeepMerge(
+			maps2.SliceAppend,
+			map[string]any{"a": map[string]any{"b": 1}, "c": []any{1}},
+			map[string]any{"a": "flat", "c": map[string]any{"d": 2}},
+		),
+		map[string]any{"a": "flat", "c": map[string]any{"d": 2}},
+		"DeepMerge",
+	)
+}
+
+func TestDeepMergeDoesNotAlias(t *testing.T) {
+	a := map[string]any{"nested": map[string]any{"x": 1}, "list": []any{1}}
+	b := map[string]any{"nested": map[string]any{"y": 2}, "list": []any{2}}
+
+	merged := maps2.DeepMerge(maps2.SliceAppend, a, b)
+	merged["nested"].(map[string]any)["z"] = 3
+	merged["list"].([]any)[0] = 100
+
+	check.DeepEqMsg(t, a, map[string]any{"nested": map[string]any{"x": 1}, "list": []any{1}}, "a")
+	check.DeepEqMsg(t, b, map[string]any{"nested": map[string]any{"y": 2}, "list": []any{2}}, "b")
+}
+
+func TestDeepDiff(t *testing.T) {
+	old := map[string]any{
+		"db":      map[string]any{"host": "localhost", "port": 5432},
+		"tags":    []any{"a"},
+		"removed": "x",
+	}
+	new := map[string]any{
+		"db":    map[string]any{"host": "localhost", "port": 5433, "ssl": true},
+		"tags":  []any{"a"},
+		"debug": true,
+	}
+
+	changes := maps2.DeepDiff(old, new)
+	check.DeepEqMsg(
+		t,
+		changes,
+		[]maps2.PathChange{
+			{Path: []string{"db", "port"}, Kind: maps2.ChangeChanged, Old: 5432, New: 5433},
+			{Path: []string{"db", "ssl"}, Kind: maps2.ChangeAdded, New: true},
+			{Path: []string{"debug"}, Kind: maps2.ChangeAdded, New: true},
+			{Path: []string{"removed"}, Kind: maps2.ChangeRemoved, Old: "x"},
+		},
+		"DeepDiff",
+	)
+
+	check.EqMsg(t, changes[0].String(), "changed db.port: 5432 → 5433", "changes[0].String()")
+	check.EqMsg(t, changes[3].String(), "removed removed: x", "changes[3].String()")
+	check.EqMsg(t, len(maps2.DeepDiff(old, old)), 0, "len(DeepDiff(old, old))")
+}
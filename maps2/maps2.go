@@ -0,0 +1,225 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// maps2 is an extension of golang.org/x/exp/maps (https://pkg.go.dev/golang.org/x/exp/maps),
+// adding a few more common map operations.
+//
+// Unless stated otherwise, functions don't modify their arguments and return new maps.
+package maps2
+
+import (
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// Invert returns a map from values to keys of m.
+// If multiple keys map to the same value, it is unspecified which key is kept.
+//
+// Example:
+//
+//	Invert(map[string]int{"a": 1, "b": 2})  // → map[1:a 2:b]
+func Invert[M ~map[K]V, K, V comparable](m M) map[V]K {
+	r := make(map[V]K, len(m))
+	for k, v := range m {
+		r[v] = k
+	}
+	return r
+}
+
+// Merge returns a map with entries from all provided maps.
+//
+// If a key is present in multiple maps, resolve is called with the key,
+// the value merged so far and the value from the next map; its result is kept.
+// If resolve is nil, values from later maps take precedence.
+//
+// Example:
+//
+//	Merge(sum, map[string]int{"a": 1, "b": 2}, map[string]int{"b": 3})  // → map[a:1 b:5]
+func Merge[M ~map[K]V, K comparable, V any](resolve func(key K, a, b V) V, ms ...M) M {
+	size := 0
+	for _, m := range ms {
+		size += len(m)
+	}
+
+	r := make(M, size)
+	for _, m := range ms {
+		for k, v := range m {
+			if existing, ok := r[k]; ok && resolve != nil {
+				r[k] = resolve(k, existing, v)
+			} else {
+				r[k] = v
+			}
+		}
+	}
+	return r
+}
+
+// Filter returns a map with entries of m for which keep(key, value) returns true.
+//
+// To filter a map in place, use [golang.org/x/exp/maps.DeleteFunc].
+func Filter[M ~map[K]V, K comparable, V any](m M, keep func(K, V) bool) M {
+	r := make(M)
+	for k, v := range m {
+		if keep(k, v) {
+			r[k] = v
+		}
+	}
+	return r
+}
+
+// FilterKeys returns a map with entries of m for which keep(key) returns true.
+func FilterKeys[M ~map[K]V, K comparable, V any](m M, keep func(K) bool) M {
+	return Filter(m, func(k K, _ V) bool { return keep(k) })
+}
+
+// MapValues returns a map with the same keys as m, and values transformed by f.
+//
+// Example:
+//
+//	MapValues(map[string]int{"a": 1, "b": 2}, strconv.Itoa)  // → map[a:"1" b:"2"]
+func MapValues[M ~map[K]V, K comparable, V, W any](m M, f func(V) W) map[K]W {
+	r := make(map[K]W, len(m))
+	for k, v := range m {
+		r[k] = f(v)
+	}
+	return r
+}
+
+// MapKeys returns a map with keys of m transformed by f, and the same values.
+// If f returns the same key for multiple keys of m, it is unspecified which value is kept.
+//
+// Example:
+//
+//	MapKeys(map[string]int{"a": 1, "b": 2}, strings.ToUpper)  // → map[A:1 B:2]
+func MapKeys[M ~map[K]V, K, L comparable, V any](m M, f func(K) L) map[L]V {
+	r := make(map[L]V, len(m))
+	for k, v := range m {
+		r[f(k)] = v
+	}
+	return r
+}
+
+// GroupBy returns a map from keys (as returned by key(elem)) to elements of s with that key.
+// Elements in every group keep their relative order from s.
+//
+// Example:
+//
+//	GroupBy([]string{"apple", "avocado", "banana"}, firstLetter)  // → map[a:[apple avocado] b:[banana]]
+func GroupBy[S ~[]E, E any, K comparable](s S, key func(E) K) map[K]S {
+	r := make(map[K]S)
+	for _, x := range s {
+		k := key(x)
+		r[k] = append(r[k], x)
+	}
+	return r
+}
+
+// SortedKeys returns keys of m in ascending order.
+func SortedKeys[M ~map[K]V, K constraints.Ordered, V any](m M) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// SortedKeysFunc returns keys of m ordered by the provided less function.
+func SortedKeysFunc[M ~map[K]V, K comparable, V any](m M, less func(a, b K) bool) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, less)
+	return keys
+}
+
+// Change describes a value which differs between two maps.
+type Change[V any] struct {
+	Old, New V
+}
+
+// Difference describes how a map has changed - see [Diff].
+type Difference[K comparable, V any] struct {
+	// Added contains entries present only in the new map.
+	Added map[K]V
+
+	// Removed contains entries present only in the old map.
+	Removed map[K]V
+
+	// Changed contains keys present in both maps, but with different values.
+	Changed map[K]Change[V]
+}
+
+// IsEmpty returns true if there are no differences.
+func (d Difference[K, V]) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff returns the difference between two maps. Fields of the returned
+// [Difference] are never nil.
+//
+// Example:
+//
+//	Diff(map[string]int{"a": 1, "b": 2}, map[string]int{"b": 3, "c": 4})
+//	// → {Added: map[c:4], Removed: map[a:1], Changed: map[b:{2 3}]}
+func Diff[M ~map[K]V, K, V comparable](old, new M) Difference[K, V] {
+	return DiffFunc(old, new, func(a, b V) bool { return a == b })
+}
+
+// DiffFunc returns the difference between two maps, using eq to compare values.
+// Fields of the returned [Difference] are never nil.
+func DiffFunc[M ~map[K]V, K comparable, V any](old, new M, eq func(a, b V) bool) Difference[K, V] {
+	d := Difference[K, V]{
+		Added:   make(map[K]V),
+		Removed: make(map[K]V),
+		Changed: make(map[K]Change[V]),
+	}
+
+	for k, oldV := range old {
+		if newV, ok := new[k]; !ok {
+			d.Removed[k] = oldV
+		} else if !eq(oldV, newV) {
+			d.Changed[k] = Change[V]{oldV, newV}
+		}
+	}
+
+	for k, newV := range new {
+		if _, ok := old[k]; !ok {
+			d.Added[k] = newV
+		}
+	}
+
+	return d
+}
+
+// FromIter returns a map with all key-value pairs generated by an iterator.
+// If a key is generated multiple times, the last value is kept.
+// Returns nil and the error if the iterator fails.
+//
+// This is the inverse of [iter.OverMap].
+func FromIter[K comparable, V any](i iter.Iterator[iter.Pair[K, V]]) (map[K]V, error) {
+	r := make(map[K]V)
+	for i.Next() {
+		p := i.Get()
+		r[p.First] = p.Second
+	}
+	if err := i.Err(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// IterSorted returns an iterator over key-value pairs of m, in ascending order of keys.
+// As opposed to [iter.OverMap], the order is deterministic.
+//
+// Modifications of m made after IterSorted returns are not visible through the iterator.
+func IterSorted[M ~map[K]V, K constraints.Ordered, V any](m M) iter.Iterator[iter.Pair[K, V]] {
+	keys := SortedKeys(m)
+	pairs := make([]iter.Pair[K, V], len(keys))
+	for i, k := range keys {
+		pairs[i] = iter.Pair[K, V]{First: k, Second: m[k]}
+	}
+	return iter.OverSlice(pairs)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package maps2_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/maps2"
+	"github.com/MKuranowski/go-extra-lib/testing2/assert"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func TestInvert(t *testing.T) {
+	check.DeepEqMsg(
+		t,
+		maps2.Invert(map[string]int{"a": 1, "b": 2}),
+		map[int]string{1: "a", 2: "b"},
+		"Invert",
+	)
+}
+
+func TestMerge(t *testing.T) {
+	a := map[string]int{"a": 1, "b": 2}
+	b := map[string]int{"b": 3, "c": 4}
+	c := map[string]int{"b": 10}
+
+	check.DeepEqMsg(t, maps2.Merge(nil, a, b, c), map[string]int{"a": 1, "b": 10, "c": 4}, "Merge(nil)")
+
+	sum := func(_ string, x, y int) int { return x + y }
+	check.DeepEqMsg(t, maps2.Merge(sum, a, b, c), map[string]int{"a": 1, "b": 15, "c": 4}, "Merge(sum)")
+
+	check.DeepEqMsg(t, a, map[string]int{"a": 1, "b": 2}, "a: after merging")
+	check.DeepEqMsg(t, maps2.Merge[map[string]int](nil), map[string]int{}, "Merge()")
+}
+
+func TestFilter(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+
+	check.DeepEqMsg(
+		t,
+		maps2.Filter(m, func(k string, v int) bool { return k != "a" && v%2 == 1 }),
+		map[string]int{"c": 3},
+		"Filter",
+	)
+	check.DeepEqMsg(
+		t,
+		maps2.FilterKeys(m, func(k string) bool { return k < "c" }),
+		map[string]int{"a": 1, "b": 2},
+		"FilterKeys",
+	)
+	check.EqMsg(t, len(m), 4, "len(m): after filtering")
+}
+
+func TestMapValuesAndKeys(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2}
+	check.DeepEqMsg(t, maps2.MapValues(m, strconv.Itoa), map[string]string{"a": "1", "b": "2"}, "MapValues")
+	check.DeepEqMsg(t, maps2.MapKeys(m, strings.ToUpper), map[string]int{"A": 1, "B": 2}, "MapKeys")
+}
+
+func TestGroupBy(t *testing.T) {
+	firstLetter := func(s string) byte { return s[0] }
+	check.DeepEqMsg(
+		t,
+		maps2.GroupBy([]string{"apple", "banana", "avocado", "blueberry", "cherry"}, firstLetter),
+		map[byte][]string{'a': {"apple", "avocado"}, 'b': {"banana", "blueberry"}, 'c': {"cherry"}},
+		"GroupBy",
+	)
+}
+
+func TestSortedKeys(t *testing.T) {
+	m := map[string]int{"c": 1, "a": 2, "b": 3}
+	check.DeepEqMsg(t, maps2.SortedKeys(m), []string{"a", "b", "c"}, "SortedKeys")
+	check.DeepEqMsg(
+		t,
+		maps2.SortedKeysFunc(m, func(a, b string) bool { return m[a] < m[b] }),
+		[]string{"c", "a", "b"},
+		"SortedKeysFunc(by value)",
+	)
+}
+
+func TestDiff(t *testing.T) {
+	d := maps2.Diff(map[string]int{"a": 1, "b": 2, "c": 3}, map[string]int{"b": 20, "c": 3, "d": 4})
+	check.DeepEqMsg(t, d.Added, map[string]int{"d": 4}, "d.Added")
+	check.DeepEqMsg(t, d.Removed, map[string]int{"a": 1}, "d.Removed")
+	check.DeepEqMsg(t, d.Changed, map[string]maps2.Change[int]{"b": {Old: 2, New: 20}}, "d.Changed")
+	check.FalseMsg(t, d.IsEmpty(), "d.IsEmpty()")
+
+	same := maps2.Diff(map[string]int{"a": 1}, map[string]int{"a": 1})
+	check.TrueMsg(t, same.IsEmpty(), "same.IsEmpty()")
+
+	eqLen := func(a, b []int) bool { return len(a) == len(b) }
+	d2 := maps2.DiffFunc(map[string][]int{"a": {1}, "b": {1}}, map[string][]int{"a": {2}, "b": {1, 2}}, eqLen)
+	check.DeepEqMsg(t, d2.Changed, map[string]maps2.Change[[]int]{"b": {Old: []int{1}, New: []int{1, 2}}}, "DiffFunc")
+}
+
+func TestIterBridges(t *testing.T) {
+	m := map[string]int{"b": 2, "a": 1, "c": 3}
+
+	check.DeepEqMsg(
+		t,
+		iter.IntoSlice(maps2.IterSorted(m)),
+		[]iter.Pair[string, int]{{First: "a", Second: 1}, {First: "b", Second: 2}, {First: "c", Second: 3}},
+		"IterSorted",
+	)
+
+	roundTrip, err := maps2.FromIter(iter.OverMap(m))
+	assert.NoErr(t, err)
+	check.DeepEqMsg(t, roundTrip, m, "FromIter(OverMap(m))")
+
+	testErr := errors.New("test error")
+	_, err = maps2.FromIter(iter.Error[iter.Pair[string, int]](testErr))
+	check.SpecificErrMsg(t, err, testErr, "FromIter(error)")
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package matrix
+
+import (
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Cholesky is the Cholesky decomposition of a symmetric, positive definite matrix A:
+// A = LLᵀ, where L is a lower triangular matrix with positive diagonal elements.
+type Cholesky[T constraints.Float] struct {
+	l *Dense[T]
+}
+
+// NewCholesky computes the Cholesky decomposition of a symmetric, positive definite matrix.
+// Only the lower triangle of A is used - A is assumed to be symmetric.
+//
+// Returns a [NotPositiveDefiniteError] if the matrix is not positive definite.
+// Panics if the matrix is not square.
+//
+// Complexity: O(n³)
+func NewCholesky[T constraints.Float](a *Dense[T]) (*Cholesky[T], error) {
+	checkSquare(a)
+	n := a.rows
+	l := Zero[T](n, n)
+
+	for j := 0; j < n; j++ {
+		rowJ := l.rowSlice(j)
+		var d T
+		for k := 0; k < j; k++ {
+			rowK := l.rowSlice(k)
+			s := a.At(j, k)
+			for i := 0; i < k; i++ {
+				s -= rowK[i] * rowJ[i]
+			}
+			s /= rowK[k]
+			rowJ[k] = s
+			d += s * s
+		}
+
+		d = a.At(j, j) - d
+		if !(d > 0) {
+			return nil, NotPositiveDefiniteError{Index: j}
+		}
+		rowJ[j] = T(math.Sqrt(float64(d)))
+	}
+
+	return &Cholesky[T]{l}, nil
+}
+
+// L returns the lower triangular factor.
+func (d *Cholesky[T]) L() *Dense[T] { return d.l.Clone() }
+
+// Det returns the determinant of the decomposed matrix.
+//
+// Complexity: O(n)
+func (d *Cholesky[T]) Det() T {
+	det := T(1)
+	for i := 0; i < d.l.rows; i++ {
+		det *= d.l.data[i*d.l.stride+i]
+	}
+	return det * det
+}
+
+// solveInPlace solves Ax = b, overwriting b with x.
+func (d *Cholesky[T]) solveInPlace(b []T) {
+	n := d.l.rows
+
+	// Solve Ly = b
+	for i := 0; i < n; i++ {
+		row := d.l.rowSlice(i)
+		for k := 0; k < i; k++ {
+			b[i] -= row[k] * b[k]
+		}
+		b[i] /= row[i]
+	}
+
+	// Solve Lᵀx = y
+	for i := n - 1; i >= 0; i-- {
+		for k := i + 1; k < n; k++ {
+			b[i] -= d.l.data[k*n+i] * b[k]
+		}
+		b[i] /= d.l.data[i*n+i]
+	}
+}
+
+// SolveVec returns x such that Ax = b. Panics if len(b) doesn't match the size of A.
+//
+// Complexity: O(n²)
+func (d *Cholesky[T]) SolveVec(b []T) []T {
+	checkMulDims(d.l.rows, d.l.cols, len(b), 1)
+	x := append([]T(nil), b...)
+	d.solveInPlace(x)
+	return x
+}
+
+// Solve returns X such that AX = B. Panics if B.Rows() doesn't match the size of A.
+//
+// Complexity: O(n² * B.Cols())
+func (d *Cholesky[T]) Solve(b *Dense[T]) *Dense[T] {
+	checkMulDims(d.l.rows, d.l.cols, b.rows, b.cols)
+	x := b.Clone()
+	col := make([]T, b.rows)
+	for j := 0; j < b.cols; j++ {
+		for i := range col {
+			col[i] = x.data[i*x.stride+j]
+		}
+		d.solveInPlace(col)
+		for i, v := range col {
+			x.data[i*x.stride+j] = v
+		}
+	}
+	return x
+}
@@ -0,0 +1,311 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+// matrix contains generic 2D matrices of numbers, in dense and sparse (COO, CSR, CSC) formats.
+//
+// Floating-point matrices can be decomposed ([LU], [QR], [Cholesky]),
+// which allows solving linear systems and least squares problems - see [Solve].
+package matrix
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MKuranowski/go-extra-lib/iter"
+)
+
+// Dense is a matrix of numbers, stored in row-major order.
+//
+// A Dense may be a view into a different matrix - see [Dense.Slice].
+// Views share storage with the original matrix, so modifications
+// of elements in the view are visible in the original matrix, and vice versa.
+//
+// Methods panic if dimensions of their arguments are incompatible,
+// or when indices are out of range.
+type Dense[T iter.Numeric] struct {
+	rows, cols int
+	stride     int
+	data       []T
+}
+
+func checkDims(rows, cols int) {
+	if rows < 0 || cols < 0 {
+		panic(fmt.Sprintf("matrix: negative dimensions %d×%d", rows, cols))
+	}
+}
+
+// New returns a rows×cols matrix backed by the provided data in row-major order.
+// If data is nil, a new zero matrix is allocated. Panics if len(data) != rows*cols.
+func New[T iter.Numeric](rows, cols int, data []T) *Dense[T] {
+	checkDims(rows, cols)
+	if data == nil {
+		data = make([]T, rows*cols)
+	} else if len(data) != rows*cols {
+		panic(fmt.Sprintf("matrix: got %d elements for a %d×%d matrix", len(data), rows, cols))
+	}
+	return &Dense[T]{rows: rows, cols: cols, stride: cols, data: data}
+}
+
+// Zero returns a rows×cols matrix with all elements set to zero.
+func Zero[T iter.Numeric](rows, cols int) *Dense[T] { return New[T](rows, cols, nil) }
+
+// Identity returns an n×n identity matrix.
+func Identity[T iter.Numeric](n int) *Dense[T] {
+	m := Zero[T](n, n)
+	for i := 0; i < n; i++ {
+		m.data[i*m.stride+i] = 1
+	}
+	return m
+}
+
+// FromRows returns a matrix with a copy of the provided rows.
+// Panics if the rows have different lengths.
+//
+//	FromRows([][]int{{1, 2}, {3, 4}}) → [1 2]
+//	                                    [3 4]
+func FromRows[T iter.Numeric](rows [][]T) *Dense[T] {
+	cols := 0
+	if len(rows) > 0 {
+		cols = len(rows[0])
+	}
+
+	m := Zero[T](len(rows), cols)
+	for i, row := range rows {
+		if len(row) != cols {
+			panic(fmt.Sprintf("matrix: row %d has %d elements, expected %d", i, len(row), cols))
+		}
+		copy(m.data[i*m.stride:], row)
+	}
+	return m
+}
+
+// Rows returns the number of rows of the matrix.
+func (m *Dense[T]) Rows() int { return m.rows }
+
+// Cols returns the number of columns of the matrix.
+func (m *Dense[T]) Cols() int { return m.cols }
+
+// Dims returns the number of rows and columns of the matrix.
+func (m *Dense[T]) Dims() (rows, cols int) { return m.rows, m.cols }
+
+// IsSquare returns true if the matrix has the same number of rows and columns.
+func (m *Dense[T]) IsSquare() bool { return m.rows == m.cols }
+
+func (m *Dense[T]) index(i, j int) int {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic(fmt.Sprintf("matrix: index (%d, %d) out of range for a %d×%d matrix", i, j, m.rows, m.cols))
+	}
+	return i*m.stride + j
+}
+
+// checkRow panics if i is not a valid row index. Unlike index, works for matrices without columns.
+func (m *Dense[T]) checkRow(i int) {
+	if i < 0 || i >= m.rows {
+		panic(fmt.Sprintf("matrix: row %d out of range for a %d×%d matrix", i, m.rows, m.cols))
+	}
+}
+
+// checkCol panics if j is not a valid column index. Unlike index, works for matrices without rows.
+func (m *Dense[T]) checkCol(j int) {
+	if j < 0 || j >= m.cols {
+		panic(fmt.Sprintf("matrix: column %d out of range for a %d×%d matrix", j, m.rows, m.cols))
+	}
+}
+
+// rowSlice returns the underlying storage of the i-th row.
+func (m *Dense[T]) rowSlice(i int) []T {
+	if m.cols == 0 {
+		return nil
+	}
+	return m.data[i*m.stride : i*m.stride+m.cols]
+}
+
+// At returns the element at the i-th row and j-th column.
+func (m *Dense[T]) At(i, j int) T { return m.data[m.index(i, j)] }
+
+// Set sets the element at the i-th row and j-th column.
+func (m *Dense[T]) Set(i, j int, v T) { m.data[m.index(i, j)] = v }
+
+// Slice returns a view into rows [i0, i1) and columns [j0, j1) of the matrix.
+// The view shares storage with m.
+func (m *Dense[T]) Slice(i0, i1, j0, j1 int) *Dense[T] {
+	if i0 < 0 || i1 < i0 || i1 > m.rows || j0 < 0 || j1 < j0 || j1 > m.cols {
+		panic(fmt.Sprintf("matrix: slice [%d:%d, %d:%d] out of range for a %d×%d matrix", i0, i1, j0, j1, m.rows, m.cols))
+	}
+
+	v := &Dense[T]{rows: i1 - i0, cols: j1 - j0, stride: m.stride}
+	if v.rows > 0 && v.cols > 0 {
+		v.data = m.data[i0*m.stride+j0 : (i1-1)*m.stride+j1]
+	}
+	return v
+}
+
+// RowView returns a 1×Cols() view of the i-th row. The view shares storage with m.
+func (m *Dense[T]) RowView(i int) *Dense[T] { return m.Slice(i, i+1, 0, m.cols) }
+
+// ColView returns a Rows()×1 view of the j-th column. The view shares storage with m.
+func (m *Dense[T]) ColView(j int) *Dense[T] { return m.Slice(0, m.rows, j, j+1) }
+
+// Row returns a copy of the i-th row.
+func (m *Dense[T]) Row(i int) []T {
+	m.checkRow(i)
+	r := make([]T, m.cols)
+	copy(r, m.rowSlice(i))
+	return r
+}
+
+// Col returns a copy of the j-th column.
+func (m *Dense[T]) Col(j int) []T {
+	m.checkCol(j)
+	c := make([]T, m.rows)
+	for i := range c {
+		c[i] = m.data[i*m.stride+j]
+	}
+	return c
+}
+
+// Clone returns a copy of the matrix, with its own storage.
+func (m *Dense[T]) Clone() *Dense[T] {
+	c := Zero[T](m.rows, m.cols)
+	for i := 0; i < m.rows; i++ {
+		copy(c.rowSlice(i), m.rowSlice(i))
+	}
+	return c
+}
+
+// Equal returns true if both matrices have the same dimensions and elements.
+func (m *Dense[T]) Equal(o *Dense[T]) bool {
+	if m.rows != o.rows || m.cols != o.cols {
+		return false
+	}
+
+	for i := 0; i < m.rows; i++ {
+		a, b := m.rowSlice(i), o.rowSlice(i)
+		for j := range a {
+			if a[j] != b[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Transpose returns a new matrix with rows and columns of m swapped.
+func (m *Dense[T]) Transpose() *Dense[T] {
+	t := Zero[T](m.cols, m.rows)
+	for i := 0; i < m.rows; i++ {
+		for j, v := range m.rowSlice(i) {
+			t.data[j*t.stride+i] = v
+		}
+	}
+	return t
+}
+
+// Add returns a new matrix with the element-wise sum of m and o.
+func (m *Dense[T]) Add(o *Dense[T]) *Dense[T] {
+	checkSameDims(m.rows, m.cols, o.rows, o.cols)
+	r := m.Clone()
+	for i := 0; i < r.rows; i++ {
+		dst, src := r.rowSlice(i), o.rowSlice(i)
+		for j := range dst {
+			dst[j] += src[j]
+		}
+	}
+	return r
+}
+
+// Sub returns a new matrix with the element-wise difference of m and o.
+func (m *Dense[T]) Sub(o *Dense[T]) *Dense[T] {
+	checkSameDims(m.rows, m.cols, o.rows, o.cols)
+	r := m.Clone()
+	for i := 0; i < r.rows; i++ {
+		dst, src := r.rowSlice(i), o.rowSlice(i)
+		for j := range dst {
+			dst[j] -= src[j]
+		}
+	}
+	return r
+}
+
+// Scale returns a new matrix with every element of m multiplied by k.
+func (m *Dense[T]) Scale(k T) *Dense[T] {
+	r := m.Clone()
+	for i := range r.data {
+		r.data[i] *= k
+	}
+	return r
+}
+
+// Mul returns a new matrix with the product of m and o.
+// Panics if m.Cols() != o.Rows().
+//
+// Complexity: O(n * m * p), for a n×m matrix multiplied by a m×p matrix.
+func (m *Dense[T]) Mul(o *Dense[T]) *Dense[T] {
+	checkMulDims(m.rows, m.cols, o.rows, o.cols)
+	r := Zero[T](m.rows, o.cols)
+	for i := 0; i < m.rows; i++ {
+		dst := r.rowSlice(i)
+		// i-k-j loop order accesses both matrices sequentially
+		for k, a := range m.rowSlice(i) {
+			for j, b := range o.rowSlice(k) {
+				dst[j] += a * b
+			}
+		}
+	}
+	return r
+}
+
+// String returns the matrix with each row in a separate line, e.g. "[1 2]\n[3 4]".
+func (m *Dense[T]) String() string {
+	var b strings.Builder
+	for i := 0; i < m.rows; i++ {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprint(&b, m.rowSlice(i))
+	}
+	return b.String()
+}
+
+// IterRow returns an iterator over elements of the i-th row.
+// The iterator reflects modifications of the matrix made while iterating.
+func (m *Dense[T]) IterRow(i int) iter.Iterator[T] {
+	m.checkRow(i)
+	return &strideIterator[T]{data: m.data, pos: i*m.stride - 1, step: 1, left: m.cols}
+}
+
+// IterCol returns an iterator over elements of the j-th column.
+// The iterator reflects modifications of the matrix made while iterating.
+func (m *Dense[T]) IterCol(j int) iter.Iterator[T] {
+	m.checkCol(j)
+	return &strideIterator[T]{data: m.data, pos: j - m.stride, step: m.stride, left: m.rows}
+}
+
+// IterRows returns an iterator over copies of rows of the matrix.
+func (m *Dense[T]) IterRows() iter.Iterator[[]T] {
+	return iter.Map(iter.Range(m.rows), m.Row)
+}
+
+// IterCols returns an iterator over copies of columns of the matrix.
+func (m *Dense[T]) IterCols() iter.Iterator[[]T] {
+	return iter.Map(iter.Range(m.cols), m.Col)
+}
+
+type strideIterator[T any] struct {
+	data []T
+	pos  int
+	step int
+	left int
+}
+
+func (i *strideIterator[T]) Next() bool {
+	if i.left <= 0 {
+		return false
+	}
+	i.pos += i.step
+	i.left--
+	return true
+}
+
+func (i *strideIterator[T]) Get() T     { return i.data[i.pos] }
+func (i *strideIterator[T]) Err() error { return nil }
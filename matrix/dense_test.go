@@ -0,0 +1,110 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package matrix_test
+
+import (
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/matrix"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func TestDenseConstructors(t *testing.T) {
+	m := matrix.New(2, 3, []int{1, 2, 3, 4, 5, 6})
+	rows, cols := m.Dims()
+	check.EqMsg(t, rows, 2, "m.Rows()")
+	check.EqMsg(t, cols, 3, "m.Cols()")
+	check.EqMsg(t, m.At(1, 0), 4, "m.At(1, 0)")
+	check.TrueMsg(t, m.Equal(matrix.FromRows([][]int{{1, 2, 3}, {4, 5, 6}})), "New == FromRows")
+
+	check.TrueMsg(t, matrix.Zero[int](2, 2).Equal(matrix.New(2, 2, []int{0, 0, 0, 0})), "Zero(2, 2)")
+	check.TrueMsg(t, matrix.Identity[float64](3).Equal(matrix.FromRows([][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}})), "Identity(3)")
+	check.EqMsg(t, m.String(), "[1 2 3]\n[4 5 6]", "m.String()")
+}
+
+func TestDenseViews(t *testing.T) {
+	m := matrix.FromRows([][]int{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}})
+
+	v := m.Slice(1, 3, 1, 3)
+	check.TrueMsg(t, v.Equal(matrix.FromRows([][]int{{5, 6}, {8, 9}})), "m.Slice(1, 3, 1, 3)")
+
+	v.Set(0, 0, 50)
+	check.EqMsg(t, m.At(1, 1), 50, "m.At(1, 1): after modifying view")
+
+	check.DeepEqMsg(t, m.Row(2), []int{7, 8, 9}, "m.Row(2)")
+	check.DeepEqMsg(t, m.Col(1), []int{2, 50, 8}, "m.Col(1)")
+	check.DeepEqMsg(t, v.Col(1), []int{6, 9}, "v.Col(1)")
+
+	m.ColView(0).Set(2, 0, 70)
+	check.EqMsg(t, m.At(2, 0), 70, "m.At(2, 0): after modifying column view")
+	check.TrueMsg(t, m.RowView(0).Equal(matrix.FromRows([][]int{{1, 2, 3}})), "m.RowView(0)")
+
+	c := v.Clone()
+	c.Set(1, 1, 0)
+	check.EqMsg(t, m.At(2, 2), 9, "m.At(2, 2): after modifying clone")
+
+	check.EqMsg(t, m.Slice(1, 1, 0, 3).Rows(), 0, "empty slice: Rows()")
+}
+
+func TestDenseIter(t *testing.T) {
+	m := matrix.FromRows([][]int{{1, 2, 3}, {4, 5, 6}})
+
+	check.DeepEqMsg(t, iter.IntoSlice(m.IterRow(1)), []int{4, 5, 6}, "m.IterRow(1)")
+	check.DeepEqMsg(t, iter.IntoSlice(m.IterCol(2)), []int{3, 6}, "m.IterCol(2)")
+	check.DeepEqMsg(t, iter.IntoSlice(m.IterRows()), [][]int{{1, 2, 3}, {4, 5, 6}}, "m.IterRows()")
+	check.DeepEqMsg(t, iter.IntoSlice(m.IterCols()), [][]int{{1, 4}, {2, 5}, {3, 6}}, "m.IterCols()")
+	check.EqMsg(t, iter.Sum(m.IterCol(0)), 5, "Sum(m.IterCol(0))")
+
+	v := m.Slice(0, 2, 1, 3)
+	check.DeepEqMsg(t, iter.IntoSlice(v.IterRow(1)), []int{5, 6}, "v.IterRow(1)")
+	check.DeepEqMsg(t, iter.IntoSlice(v.IterCol(0)), []int{2, 5}, "v.IterCol(0)")
+}
+
+func TestDenseIterEmptyDimension(t *testing.T) {
+	noCols := matrix.Zero[int](3, 0)
+	check.DeepEqMsg(t, noCols.Row(2), []int{}, "noCols.Row(2)")
+	check.DeepEqMsg(t, iter.IntoSlice(noCols.IterRow(2)), []int{}, "noCols.IterRow(2)")
+	check.DeepEqMsg(t, iter.IntoSlice(noCols.IterRows()), [][]int{{}, {}, {}}, "noCols.IterRows()")
+	check.DeepEqMsg(t, iter.IntoSlice(noCols.IterCols()), [][]int{}, "noCols.IterCols()")
+
+	noRows := matrix.Zero[int](0, 3)
+	check.DeepEqMsg(t, noRows.Col(2), []int{}, "noRows.Col(2)")
+	check.DeepEqMsg(t, iter.IntoSlice(noRows.IterCol(2)), []int{}, "noRows.IterCol(2)")
+	check.DeepEqMsg(t, iter.IntoSlice(noRows.IterCols()), [][]int{{}, {}, {}}, "noRows.IterCols()")
+	check.DeepEqMsg(t, iter.IntoSlice(noRows.IterRows()), [][]int{}, "noRows.IterRows()")
+}
+
+func TestDenseRowOutOfRange(t *testing.T) {
+	defer func() {
+		check.TrueMsg(t, recover() != nil, "Row didn't panic")
+	}()
+	matrix.Zero[int](3, 0).Row(3)
+}
+
+func TestDenseArithmetic(t *testing.T) {
+	a := matrix.FromRows([][]int{{1, 2}, {3, 4}})
+	b := matrix.FromRows([][]int{{5, 6}, {7, 8}})
+
+	check.TrueMsg(t, a.Add(b).Equal(matrix.FromRows([][]int{{6, 8}, {10, 12}})), "a + b")
+	check.TrueMsg(t, b.Sub(a).Equal(matrix.FromRows([][]int{{4, 4}, {4, 4}})), "b - a")
+	check.TrueMsg(t, a.Scale(3).Equal(matrix.FromRows([][]int{{3, 6}, {9, 12}})), "a * 3")
+	check.TrueMsg(t, a.Mul(b).Equal(matrix.FromRows([][]int{{19, 22}, {43, 50}})), "a × b")
+	check.TrueMsg(t, a.Mul(matrix.Identity[int](2)).Equal(a), "a × I")
+	check.TrueMsg(t, a.Equal(matrix.FromRows([][]int{{1, 2}, {3, 4}})), "a: after operations")
+
+	c := matrix.FromRows([][]int{{1, 2, 3}, {4, 5, 6}})
+	check.TrueMsg(t, c.Transpose().Equal(matrix.FromRows([][]int{{1, 4}, {2, 5}, {3, 6}})), "cᵀ")
+	check.TrueMsg(t, c.Mul(c.Transpose()).Equal(matrix.FromRows([][]int{{14, 32}, {32, 77}})), "c × cᵀ")
+
+	z := matrix.FromRows([][]complex128{{1i, 0}, {0, 1i}})
+	check.TrueMsg(t, z.Mul(z).Equal(matrix.Identity[complex128](2).Scale(-1)), "complex multiplication")
+}
+
+func TestDenseDimensionMismatch(t *testing.T) {
+	defer func() {
+		check.TrueMsg(t, recover() != nil, "Mul didn't panic")
+	}()
+	matrix.Zero[int](2, 3).Mul(matrix.Zero[int](2, 3))
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package matrix
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+func abs[T constraints.Float](x T) T {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// epsilon returns the machine epsilon of a floating-point type.
+func epsilon[T constraints.Float]() T {
+	e := T(1)
+	// Explicit conversions force rounding to the precision of T
+	for T(1+e/2) != 1 {
+		e /= 2
+	}
+	return e
+}
+
+// tolerance returns the threshold below which a pivot of m is considered to be zero.
+func tolerance[T constraints.Float](m *Dense[T]) T {
+	var maxAbs T
+	for i := 0; i < m.rows; i++ {
+		for _, v := range m.rowSlice(i) {
+			if a := abs(v); a > maxAbs {
+				maxAbs = a
+			}
+		}
+	}
+
+	n := m.rows
+	if m.cols > n {
+		n = m.cols
+	}
+	return T(n) * epsilon[T]() * maxAbs
+}
+
+func checkSquare[T constraints.Float](m *Dense[T]) {
+	if !m.IsSquare() {
+		panic(fmt.Sprintf("matrix: expected a square matrix, got %d×%d", m.rows, m.cols))
+	}
+}
+
+// LU is the LU decomposition of a square matrix A with partial pivoting: PA = LU,
+// where P is a permutation matrix, L is a lower triangular matrix with ones on the diagonal
+// and U is an upper triangular matrix.
+//
+// The decomposition exists for every square matrix, but solving linear systems
+// requires A to be non-singular.
+type LU[T constraints.Float] struct {
+	// lu contains both factors: U on and above the diagonal,
+	// and L (without its unit diagonal) below the diagonal.
+	lu *Dense[T]
+
+	// pivot[i] is the row of A which was moved to the i-th row.
+	pivot []int
+
+	// sign is the determinant of the permutation matrix P, either 1 or -1.
+	sign T
+
+	// singularAt is the index of the first (numerically) zero pivot, or -1.
+	singularAt int
+}
+
+// NewLU computes the LU decomposition of a square matrix. Panics if the matrix is not square.
+//
+// Complexity: O(n³)
+func NewLU[T constraints.Float](a *Dense[T]) *LU[T] {
+	checkSquare(a)
+	n := a.rows
+	tol := tolerance(a)
+	d := &LU[T]{lu: a.Clone(), pivot: make([]int, n), sign: 1, singularAt: -1}
+	for i := range d.pivot {
+		d.pivot[i] = i
+	}
+
+	for k := 0; k < n; k++ {
+		// Select the largest pivot, for numerical stability
+		p := k
+		for i := k + 1; i < n; i++ {
+			if abs(d.lu.data[i*n+k]) > abs(d.lu.data[p*n+k]) {
+				p = i
+			}
+		}
+
+		if p != k {
+			rowP, rowK := d.lu.rowSlice(p), d.lu.rowSlice(k)
+			for j := range rowK {
+				rowP[j], rowK[j] = rowK[j], rowP[j]
+			}
+			d.pivot[p], d.pivot[k] = d.pivot[k], d.pivot[p]
+			d.sign = -d.sign
+		}
+
+		pivot := d.lu.data[k*n+k]
+		if abs(pivot) <= tol {
+			if d.singularAt < 0 {
+				d.singularAt = k
+			}
+			if pivot == 0 {
+				continue
+			}
+		}
+
+		rowK := d.lu.rowSlice(k)
+		for i := k + 1; i < n; i++ {
+			rowI := d.lu.rowSlice(i)
+			rowI[k] /= pivot
+			for j := k + 1; j < n; j++ {
+				rowI[j] -= rowI[k] * rowK[j]
+			}
+		}
+	}
+
+	return d
+}
+
+// IsSingular returns true if the decomposed matrix is (numerically) singular.
+func (d *LU[T]) IsSingular() bool { return d.singularAt >= 0 }
+
+// L returns the lower triangular factor, with ones on the diagonal.
+func (d *LU[T]) L() *Dense[T] {
+	n := d.lu.rows
+	l := Identity[T](n)
+	for i := 0; i < n; i++ {
+		copy(l.rowSlice(i)[:i], d.lu.rowSlice(i)[:i])
+	}
+	return l
+}
+
+// U returns the upper triangular factor.
+func (d *LU[T]) U() *Dense[T] {
+	n := d.lu.rows
+	u := Zero[T](n, n)
+	for i := 0; i < n; i++ {
+		copy(u.rowSlice(i)[i:], d.lu.rowSlice(i)[i:])
+	}
+	return u
+}
+
+// Pivot returns the row permutation: the i-th row of LU is the Pivot()[i]-th row of A.
+func (d *LU[T]) Pivot() []int { return append([]int(nil), d.pivot...) }
+
+// Det returns the determinant of the decomposed matrix.
+//
+// Complexity: O(n)
+func (d *LU[T]) Det() T {
+	det := d.sign
+	for i := 0; i < d.lu.rows; i++ {
+		det *= d.lu.data[i*d.lu.stride+i]
+	}
+	return det
+}
+
+func (d *LU[T]) checkSolvable() error {
+	if d.singularAt >= 0 {
+		return SingularError{Index: d.singularAt}
+	}
+	return nil
+}
+
+// solveInPlace solves Ax = b, overwriting b with x. The decomposition must not be singular.
+func (d *LU[T]) solveInPlace(b []T) {
+	n := d.lu.rows
+
+	// Apply the permutation
+	x := make([]T, n)
+	for i, p := range d.pivot {
+		x[i] = b[p]
+	}
+
+	// Forward substitution with L
+	for i := 0; i < n; i++ {
+		row := d.lu.rowSlice(i)
+		for j := 0; j < i; j++ {
+			x[i] -= row[j] * x[j]
+		}
+	}
+
+	// Back substitution with U
+	for i := n - 1; i >= 0; i-- {
+		row := d.lu.rowSlice(i)
+		for j := i + 1; j < n; j++ {
+			x[i] -= row[j] * x[j]
+		}
+		x[i] /= row[i]
+	}
+
+	copy(b, x)
+}
+
+// SolveVec returns x such that Ax = b. Returns a [SingularError] if A is singular.
+// Panics if len(b) doesn't match the size of A.
+//
+// Complexity: O(n²)
+func (d *LU[T]) SolveVec(b []T) ([]T, error) {
+	checkMulDims(d.lu.rows, d.lu.cols, len(b), 1)
+	if err := d.checkSolvable(); err != nil {
+		return nil, err
+	}
+
+	x := append([]T(nil), b...)
+	d.solveInPlace(x)
+	return x, nil
+}
+
+// Solve returns X such that AX = B. Returns a [SingularError] if A is singular.
+// Panics if B.Rows() doesn't match the size of A.
+//
+// Complexity: O(n² * B.Cols())
+func (d *LU[T]) Solve(b *Dense[T]) (*Dense[T], error) {
+	checkMulDims(d.lu.rows, d.lu.cols, b.rows, b.cols)
+	if err := d.checkSolvable(); err != nil {
+		return nil, err
+	}
+
+	x := b.Clone()
+	col := make([]T, b.rows)
+	for j := 0; j < b.cols; j++ {
+		for i := range col {
+			col[i] = x.data[i*x.stride+j]
+		}
+		d.solveInPlace(col)
+		for i, v := range col {
+			x.data[i*x.stride+j] = v
+		}
+	}
+	return x, nil
+}
+
+// Inverse returns the inverse of the decomposed matrix.
+// Returns a [SingularError] if A is singular.
+//
+// Complexity: O(n³)
+func (d *LU[T]) Inverse() (*Dense[T], error) { return d.Solve(Identity[T](d.lu.rows)) }
+
+// Det returns the determinant of a square matrix, computed using LU decomposition.
+// Panics if the matrix is not square.
+//
+// Complexity: O(n³)
+func Det[T constraints.Float](m *Dense[T]) T { return NewLU(m).Det() }
+
+// Inverse returns the inverse of a square matrix, computed using LU decomposition.
+// Returns a [SingularError] if the matrix is not invertible. Panics if the matrix is not square.
+//
+// Complexity: O(n³)
+func Inverse[T constraints.Float](m *Dense[T]) (*Dense[T], error) { return NewLU(m).Inverse() }
@@ -0,0 +1,54 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package matrix_test
+
+import (
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/matrix"
+	"github.com/MKuranowski/go-extra-lib/testing2/assert"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func checkClose(t *testing.T, got, expected *matrix.Dense[float64], msg string) {
+	t.Helper()
+	if got.Rows() != expected.Rows() || got.Cols() != expected.Cols() {
+		t.Fatalf("%s: got a %d×%d matrix, expected %d×%d", msg, got.Rows(), got.Cols(), expected.Rows(), expected.Cols())
+	}
+	for i := 0; i < got.Rows(); i++ {
+		for j := 0; j < got.Cols(); j++ {
+			check.CloseMsg(t, got.At(i, j), expected.At(i, j), 1e-9, msg)
+		}
+	}
+}
+
+func TestDet(t *testing.T) {
+	check.CloseMsg(t, matrix.Det(matrix.FromRows([][]float64{{1, 2}, {3, 4}})), -2, 1e-12, "det 2×2")
+	check.CloseMsg(
+		t,
+		matrix.Det(matrix.FromRows([][]float64{{0, 2, 1}, {1, 1, 1}, {2, 0, 3}})),
+		-4,
+		1e-12,
+		"det 3×3 requiring pivoting",
+	)
+	check.CloseMsg(t, matrix.Det(matrix.FromRows([][]float64{{1, 2}, {2, 4}})), 0, 0, "det singular")
+	check.CloseMsg(t, matrix.Det(matrix.Identity[float64](5)), 1, 1e-12, "det I")
+	check.CloseMsg(t, float64(matrix.Det(matrix.FromRows([][]float32{{2, 0}, {0, 3}}))), 6, 1e-6, "det float32")
+}
+
+func TestInverse(t *testing.T) {
+	a := matrix.FromRows([][]float64{{4, 7}, {2, 6}})
+	inv, err := matrix.Inverse(a)
+	assert.NoErr(t, err)
+	checkClose(t, inv, matrix.FromRows([][]float64{{0.6, -0.7}, {-0.2, 0.4}}), "inverse 2×2")
+
+	b := matrix.FromRows([][]float64{{0, 2, 1}, {1, 1, 1}, {2, 0, 3}})
+	inv, err = matrix.Inverse(b)
+	assert.NoErr(t, err)
+	checkClose(t, b.Mul(inv), matrix.Identity[float64](3), "b × b⁻¹")
+	checkClose(t, inv.Mul(b), matrix.Identity[float64](3), "b⁻¹ × b")
+
+	_, err = matrix.Inverse(matrix.FromRows([][]float64{{1, 2}, {2, 4}}))
+	check.SpecificErrMsg(t, err, matrix.ErrSingular, "inverse of a singular matrix")
+}
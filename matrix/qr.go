@@ -0,0 +1,184 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package matrix
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+// QR is the QR decomposition of a m×n matrix A, with m >= n, computed using Householder reflections:
+// A = QR, where Q is a m×n matrix with orthonormal columns and R is a n×n upper triangular matrix.
+//
+// The decomposition exists for every such matrix, but solving least squares problems
+// requires A to have full column rank.
+type QR[T constraints.Float] struct {
+	// qr contains the Householder vectors on and below the diagonal,
+	// and R (without its diagonal) above the diagonal.
+	qr *Dense[T]
+
+	// rDiag is the diagonal of R.
+	rDiag []T
+
+	// rankDeficientAt is the index of the first (numerically) zero element of rDiag, or -1.
+	rankDeficientAt int
+}
+
+// NewQR computes the QR decomposition of a m×n matrix. Panics if m < n.
+//
+// Complexity: O(m * n²)
+func NewQR[T constraints.Float](a *Dense[T]) *QR[T] {
+	m, n := a.rows, a.cols
+	if m < n {
+		panic(fmt.Sprintf("matrix: QR decomposition requires at least as many rows as columns, got %d×%d", m, n))
+	}
+
+	tol := tolerance(a)
+	d := &QR[T]{qr: a.Clone(), rDiag: make([]T, n), rankDeficientAt: -1}
+	qr := d.qr.data
+
+	for k := 0; k < n; k++ {
+		// Compute the 2-norm of the k-th column, below the diagonal
+		var norm float64
+		for i := k; i < m; i++ {
+			norm = math.Hypot(norm, float64(qr[i*n+k]))
+		}
+		nrm := T(norm)
+
+		if nrm != 0 {
+			// Compute the k-th Householder vector
+			if qr[k*n+k] < 0 {
+				nrm = -nrm
+			}
+			for i := k; i < m; i++ {
+				qr[i*n+k] /= nrm
+			}
+			qr[k*n+k]++
+
+			// Apply the transformation to the remaining columns
+			for j := k + 1; j < n; j++ {
+				var s T
+				for i := k; i < m; i++ {
+					s += qr[i*n+k] * qr[i*n+j]
+				}
+				s = -s / qr[k*n+k]
+				for i := k; i < m; i++ {
+					qr[i*n+j] += s * qr[i*n+k]
+				}
+			}
+		}
+
+		d.rDiag[k] = -nrm
+		if abs(nrm) <= tol && d.rankDeficientAt < 0 {
+			d.rankDeficientAt = k
+		}
+	}
+
+	return d
+}
+
+// IsFullRank returns true if the decomposed matrix has (numerically) full column rank.
+func (d *QR[T]) IsFullRank() bool { return d.rankDeficientAt < 0 }
+
+// Q returns the m×n factor with orthonormal columns.
+func (d *QR[T]) Q() *Dense[T] {
+	m, n := d.qr.rows, d.qr.cols
+	qr := d.qr.data
+	q := Zero[T](m, n)
+
+	for k := n - 1; k >= 0; k-- {
+		q.data[k*n+k] = 1
+		for j := k; j < n; j++ {
+			if qr[k*n+k] == 0 {
+				continue
+			}
+
+			var s T
+			for i := k; i < m; i++ {
+				s += qr[i*n+k] * q.data[i*n+j]
+			}
+			s = -s / qr[k*n+k]
+			for i := k; i < m; i++ {
+				q.data[i*n+j] += s * qr[i*n+k]
+			}
+		}
+	}
+
+	return q
+}
+
+// R returns the n×n upper triangular factor.
+func (d *QR[T]) R() *Dense[T] {
+	n := d.qr.cols
+	r := Zero[T](n, n)
+	for i := 0; i < n; i++ {
+		r.data[i*n+i] = d.rDiag[i]
+		copy(r.rowSlice(i)[i+1:], d.qr.rowSlice(i)[i+1:])
+	}
+	return r
+}
+
+// Solve returns X which minimizes ‖AX - B‖ (the least squares solution).
+// If A is square, this is the exact solution of AX = B.
+// Returns a [SingularError] if A doesn't have full column rank.
+// Panics if B.Rows() != A.Rows().
+//
+// Complexity: O(m * n * B.Cols())
+func (d *QR[T]) Solve(b *Dense[T]) (*Dense[T], error) {
+	m, n := d.qr.rows, d.qr.cols
+	if b.rows != m {
+		panic(fmt.Sprintf("matrix: dimension mismatch: can't solve a %d×%d system with a %d×%d right-hand side", m, n, b.rows, b.cols))
+	}
+	if d.rankDeficientAt >= 0 {
+		return nil, SingularError{Index: d.rankDeficientAt}
+	}
+
+	qr := d.qr.data
+	x := b.Clone()
+	nx := x.cols
+
+	// Compute Y = Qᵀ B
+	for k := 0; k < n; k++ {
+		for j := 0; j < nx; j++ {
+			var s T
+			for i := k; i < m; i++ {
+				s += qr[i*n+k] * x.data[i*nx+j]
+			}
+			s = -s / qr[k*n+k]
+			for i := k; i < m; i++ {
+				x.data[i*nx+j] += s * qr[i*n+k]
+			}
+		}
+	}
+
+	// Solve R X = Y
+	for k := n - 1; k >= 0; k-- {
+		for j := 0; j < nx; j++ {
+			x.data[k*nx+j] /= d.rDiag[k]
+		}
+		for i := 0; i < k; i++ {
+			for j := 0; j < nx; j++ {
+				x.data[i*nx+j] -= x.data[k*nx+j] * qr[i*n+k]
+			}
+		}
+	}
+
+	return x.Slice(0, n, 0, nx).Clone(), nil
+}
+
+// SolveVec returns x which minimizes ‖Ax - b‖ (the least squares solution).
+// If A is square, this is the exact solution of Ax = b.
+// Returns a [SingularError] if A doesn't have full column rank.
+// Panics if len(b) != A.Rows().
+//
+// Complexity: O(m * n)
+func (d *QR[T]) SolveVec(b []T) ([]T, error) {
+	x, err := d.Solve(New(len(b), 1, append([]T(nil), b...)))
+	if err != nil {
+		return nil, err
+	}
+	return x.data, nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package matrix
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+var (
+	// ErrSingular matches (with errors.Is) every [SingularError].
+	ErrSingular = errors.New("matrix: matrix is singular")
+
+	// ErrNotPositiveDefinite matches (with errors.Is) every [NotPositiveDefiniteError].
+	ErrNotPositiveDefinite = errors.New("matrix: matrix is not positive definite")
+)
+
+// SingularError is returned when solving a system with a singular (or rank-deficient) matrix.
+//
+// Singularity is detected numerically - a pivot is considered to be zero
+// if its absolute value doesn't exceed max(rows, cols) * ε * max(|aᵢⱼ|),
+// where ε is the machine epsilon of the element type.
+type SingularError struct {
+	// Index is the first row (for LU) or column (for QR), whose pivot was zero.
+	Index int
+}
+
+func (e SingularError) Error() string {
+	return fmt.Sprintf("%s (zero pivot at index %d)", ErrSingular.Error(), e.Index)
+}
+
+// Is makes SingularError match ErrSingular.
+func (e SingularError) Is(target error) bool { return target == ErrSingular }
+
+// NotPositiveDefiniteError is returned by [NewCholesky] when the matrix is not positive definite.
+type NotPositiveDefiniteError struct {
+	// Index is the row at which the decomposition failed.
+	Index int
+}
+
+func (e NotPositiveDefiniteError) Error() string {
+	return fmt.Sprintf("%s (non-positive pivot at index %d)", ErrNotPositiveDefinite.Error(), e.Index)
+}
+
+// Is makes NotPositiveDefiniteError match ErrNotPositiveDefinite.
+func (e NotPositiveDefiniteError) Is(target error) bool { return target == ErrNotPositiveDefinite }
+
+// Solve returns X such that AX = B.
+//
+// If A is square, the system is solved exactly using [LU] decomposition.
+// If A has more rows than columns, the least squares solution is computed using [QR] decomposition.
+//
+// Returns a [SingularError] if A is singular (or doesn't have full column rank).
+// Panics if A has fewer rows than columns, or if B.Rows() != A.Rows().
+func Solve[T constraints.Float](a, b *Dense[T]) (*Dense[T], error) {
+	if a.IsSquare() {
+		return NewLU(a).Solve(b)
+	}
+	return NewQR(a).Solve(b)
+}
+
+// SolveVec returns x such that Ax = b. See [Solve] for details.
+func SolveVec[T constraints.Float](a *Dense[T], b []T) ([]T, error) {
+	if a.IsSquare() {
+		return NewLU(a).SolveVec(b)
+	}
+	return NewQR(a).SolveVec(b)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package matrix_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/matrix"
+	"github.com/MKuranowski/go-extra-lib/testing2/assert"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func checkVecClose(t *testing.T, got, expected []float64, delta float64, msg string) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("%s: got %d elements, expected %d", msg, len(got), len(expected))
+	}
+	for i := range got {
+		check.CloseMsg(t, got[i], expected[i], delta, msg)
+	}
+}
+
+// residual returns the largest absolute element of AX - B.
+func residual(a, x, b *matrix.Dense[float64]) float64 {
+	r := a.Mul(x).Sub(b)
+	var max float64
+	for i := 0; i < r.Rows(); i++ {
+		for j := 0; j < r.Cols(); j++ {
+			if v := r.At(i, j); v > max {
+				max = v
+			} else if -v > max {
+				max = -v
+			}
+		}
+	}
+	return max
+}
+
+func randomMatrix(r *rand.Rand, rows, cols int) *matrix.Dense[float64] {
+	m := matrix.Zero[float64](rows, cols)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			m.Set(i, j, r.Float64()*2-1)
+		}
+	}
+	return m
+}
+
+func TestLU(t *testing.T) {
+	a := matrix.FromRows([][]float64{{2, 1, 1}, {4, -6, 0}, {-2, 7, 2}})
+	lu := matrix.NewLU(a)
+	check.FalseMsg(t, lu.IsSingular(), "lu.IsSingular()")
+	check.CloseMsg(t, lu.Det(), -16, 1e-12, "lu.Det()")
+
+	// PA = LU
+	p := matrix.Zero[float64](3, 3)
+	for i, row := range lu.Pivot() {
+		p.Set(i, row, 1)
+	}
+	checkClose(t, lu.L().Mul(lu.U()), p.Mul(a), "L × U")
+
+	x, err := lu.SolveVec([]float64{5, -2, 9})
+	assert.NoErr(t, err)
+	checkVecClose(t, x, []float64{1, 1, 2}, 1e-12, "lu.SolveVec()")
+
+	singular := matrix.NewLU(matrix.FromRows([][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}))
+	check.TrueMsg(t, singular.IsSingular(), "singular.IsSingular()")
+	_, err = singular.SolveVec([]float64{1, 2, 3})
+	check.SpecificErrMsg(t, err, matrix.ErrSingular, "singular.SolveVec()")
+
+	var singularErr matrix.SingularError
+	check.TrueMsg(t, errors.As(err, &singularErr), "errors.As(err, SingularError)")
+	check.EqMsg(t, singularErr.Index, 2, "singularErr.Index")
+}
+
+func TestQR(t *testing.T) {
+	a := matrix.FromRows([][]float64{{12, -51, 4}, {6, 167, -68}, {-4, 24, -41}})
+	qr := matrix.NewQR(a)
+	check.TrueMsg(t, qr.IsFullRank(), "qr.IsFullRank()")
+
+	q, r := qr.Q(), qr.R()
+	checkClose(t, q.Mul(r), a, "Q × R")
+	checkClose(t, q.Transpose().Mul(q), matrix.Identity[float64](3), "Qᵀ × Q")
+	for i := 0; i < 3; i++ {
+		for j := 0; j < i; j++ {
+			check.EqMsg(t, r.At(i, j), 0.0, "R is upper triangular")
+		}
+	}
+}
+
+func TestQRLeastSquares(t *testing.T) {
+	// Fit y = c0 + c1*x to points lying exactly on y = 1 + 2x
+	a := matrix.FromRows([][]float64{{1, 0}, {1, 1}, {1, 2}, {1, 3}})
+	c, err := matrix.SolveVec(a, []float64{1, 3, 5, 7})
+	assert.NoErr(t, err)
+	checkVecClose(t, c, []float64{1, 2}, 1e-12, "exact fit")
+
+	// Noisy points: least squares solution of y = c0 + c1*x
+	// for (0, 6), (1, 0), (2, 0) is c0 = 5, c1 = -3.
+	a = matrix.FromRows([][]float64{{1, 0}, {1, 1}, {1, 2}})
+	c, err = matrix.SolveVec(a, []float64{6, 0, 0})
+	assert.NoErr(t, err)
+	checkVecClose(t, c, []float64{5, -3}, 1e-12, "least squares fit")
+
+	// Rank-deficient matrix
+	_, err = matrix.SolveVec(matrix.FromRows([][]float64{{1, 2}, {2, 4}, {3, 6}}), []float64{1, 2, 3})
+	check.SpecificErrMsg(t, err, matrix.ErrSingular, "rank-deficient least squares")
+}
+
+func TestCholesky(t *testing.T) {
+	a := matrix.FromRows([][]float64{{4, 12, -16}, {12, 37, -43}, {-16, -43, 98}})
+	c, err := matrix.NewCholesky(a)
+	assert.NoErr(t, err)
+
+	checkClose(t, c.L(), matrix.FromRows([][]float64{{2, 0, 0}, {6, 1, 0}, {-8, 5, 3}}), "c.L()")
+	checkClose(t, c.L().Mul(c.L().Transpose()), a, "L × Lᵀ")
+	check.CloseMsg(t, c.Det(), 36, 1e-9, "c.Det()")
+
+	x := c.SolveVec([]float64{1, 2, 3})
+	checkVecClose(t, a.Mul(matrix.New(3, 1, x)).Col(0), []float64{1, 2, 3}, 1e-9, "A × c.SolveVec(b)")
+
+	_, err = matrix.NewCholesky(matrix.FromRows([][]float64{{1, 2}, {2, 1}}))
+	check.SpecificErrMsg(t, err, matrix.ErrNotPositiveDefinite, "indefinite matrix")
+	var npdErr matrix.NotPositiveDefiniteError
+	check.TrueMsg(t, errors.As(err, &npdErr), "errors.As(err, NotPositiveDefiniteError)")
+	check.EqMsg(t, npdErr.Index, 1, "npdErr.Index")
+}
+
+func TestSolveFloat32(t *testing.T) {
+	a := matrix.FromRows([][]float32{{3, 2}, {1, 2}})
+	x, err := matrix.SolveVec(a, []float32{7, 5})
+	assert.NoErr(t, err)
+	check.CloseMsg(t, float64(x[0]), 1, 1e-5, "x[0]")
+	check.CloseMsg(t, float64(x[1]), 2, 1e-5, "x[1]")
+
+	_, err = matrix.Solve(matrix.FromRows([][]float32{{1, 2}, {2, 4}}), matrix.Identity[float32](2))
+	check.SpecificErrMsg(t, err, matrix.ErrSingular, "singular float32")
+}
+
+func TestSolveRandomizedResiduals(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	for n := 1; n <= 30; n += 3 {
+		a := randomMatrix(r, n, n)
+		b := randomMatrix(r, n, 2)
+
+		x, err := matrix.Solve(a, b)
+		assert.NoErr(t, err)
+		check.CloseMsg(t, residual(a, x, b), 0, 1e-9, "LU residual")
+
+		x, err = matrix.NewQR(a).Solve(b)
+		assert.NoErr(t, err)
+		check.CloseMsg(t, residual(a, x, b), 0, 1e-9, "QR residual")
+
+		// AᵀA is symmetric positive definite
+		spd := a.Transpose().Mul(a).Add(matrix.Identity[float64](n))
+		c, err := matrix.NewCholesky(spd)
+		assert.NoErr(t, err)
+		check.CloseMsg(t, residual(spd, c.Solve(b), b), 0, 1e-9, "Cholesky residual")
+
+		// Least squares solutions satisfy the normal equations: Aᵀ(AX - B) = 0
+		tall := randomMatrix(r, n+5, n)
+		bt := randomMatrix(r, n+5, 1)
+		x, err = matrix.Solve(tall, bt)
+		assert.NoErr(t, err)
+		normal := tall.Transpose().Mul(tall.Mul(x).Sub(bt))
+		check.CloseMsg(t, residual(matrix.Identity[float64](n), normal, matrix.Zero[float64](n, 1)), 0, 1e-9, "normal equations")
+	}
+}
@@ -0,0 +1,541 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package matrix
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/MKuranowski/go-extra-lib/iter"
+)
+
+// ErrOutOfRange is returned when a triple read from an iterator
+// lies outside of the matrix, see [COOFromIter].
+var ErrOutOfRange = errors.New("matrix: triple out of range")
+
+// Triple is a single element of a sparse matrix.
+type Triple[T iter.Numeric] struct {
+	Row, Col int
+	Value    T
+}
+
+func checkIndex(i, j, rows, cols int) {
+	if i < 0 || i >= rows || j < 0 || j >= cols {
+		panic(fmt.Sprintf("matrix: index (%d, %d) out of range for a %d×%d matrix", i, j, rows, cols))
+	}
+}
+
+func checkSameDims(rows1, cols1, rows2, cols2 int) {
+	if rows1 != rows2 || cols1 != cols2 {
+		panic(fmt.Sprintf("matrix: dimension mismatch: %d×%d and %d×%d", rows1, cols1, rows2, cols2))
+	}
+}
+
+func checkMulDims(rows1, cols1, rows2, cols2 int) {
+	if cols1 != rows2 {
+		panic(fmt.Sprintf("matrix: dimension mismatch: can't multiply %d×%d by %d×%d", rows1, cols1, rows2, cols2))
+	}
+}
+
+// COO is a sparse matrix in the coordinate format - a list of (row, col, value) triples.
+//
+// COO is meant for constructing sparse matrices; it should be converted to [CSR] or [CSC]
+// for any arithmetic. Entries may be stored in any order, and duplicate entries
+// are summed up when converting to other formats.
+type COO[T iter.Numeric] struct {
+	rows, cols int
+	entries    []Triple[T]
+}
+
+// NewCOO returns an empty rows×cols sparse matrix in the coordinate format.
+func NewCOO[T iter.Numeric](rows, cols int) *COO[T] {
+	checkDims(rows, cols)
+	return &COO[T]{rows: rows, cols: cols}
+}
+
+// COOFromIter returns a rows×cols sparse matrix with all triples generated by an iterator.
+// Returns nil and the error if the iterator fails, or nil and an error wrapping
+// [ErrOutOfRange] if any triple lies outside of the matrix.
+func COOFromIter[T iter.Numeric](rows, cols int, i iter.Iterator[Triple[T]]) (*COO[T], error) {
+	m := NewCOO[T](rows, cols)
+	for i.Next() {
+		t := i.Get()
+		if t.Row < 0 || t.Row >= rows || t.Col < 0 || t.Col >= cols {
+			return nil, fmt.Errorf("%w: (%d, %d) for a %d×%d matrix", ErrOutOfRange, t.Row, t.Col, rows, cols)
+		}
+		m.entries = append(m.entries, t)
+	}
+	if err := i.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// COOFromDense returns a sparse matrix with the non-zero elements of a dense matrix.
+func COOFromDense[T iter.Numeric](d *Dense[T]) *COO[T] {
+	m := NewCOO[T](d.rows, d.cols)
+	for i := 0; i < d.rows; i++ {
+		for j, v := range d.rowSlice(i) {
+			if v != 0 {
+				m.entries = append(m.entries, Triple[T]{i, j, v})
+			}
+		}
+	}
+	return m
+}
+
+// Dims returns the number of rows and columns of the matrix.
+func (m *COO[T]) Dims() (rows, cols int) { return m.rows, m.cols }
+
+// NNZ returns the number of stored entries, including duplicates.
+func (m *COO[T]) NNZ() int { return len(m.entries) }
+
+// Append adds v to the element at the i-th row and j-th column.
+func (m *COO[T]) Append(i, j int, v T) {
+	checkIndex(i, j, m.rows, m.cols)
+	m.entries = append(m.entries, Triple[T]{i, j, v})
+}
+
+// Iter returns an iterator over the stored entries, in the order they were added.
+func (m *COO[T]) Iter() iter.Iterator[Triple[T]] { return iter.OverSlice(m.entries) }
+
+// Transpose returns a new matrix with rows and columns of m swapped.
+func (m *COO[T]) Transpose() *COO[T] {
+	t := &COO[T]{rows: m.cols, cols: m.rows, entries: make([]Triple[T], len(m.entries))}
+	for i, e := range m.entries {
+		t.entries[i] = Triple[T]{e.Col, e.Row, e.Value}
+	}
+	return t
+}
+
+// ToDense returns a dense matrix with the same elements.
+func (m *COO[T]) ToDense() *Dense[T] {
+	d := Zero[T](m.rows, m.cols)
+	for _, e := range m.entries {
+		d.data[e.Row*d.stride+e.Col] += e.Value
+	}
+	return d
+}
+
+// ToCSR converts the matrix into the compressed sparse row format.
+func (m *COO[T]) ToCSR() *CSR[T] {
+	return &CSR[T]{compress(m.rows, m.cols, m.entries, func(e Triple[T]) (int, int) { return e.Row, e.Col })}
+}
+
+// ToCSC converts the matrix into the compressed sparse column format.
+func (m *COO[T]) ToCSC() *CSC[T] {
+	return &CSC[T]{compress(m.cols, m.rows, m.entries, func(e Triple[T]) (int, int) { return e.Col, e.Row })}
+}
+
+// compressed is the representation shared by [CSR] and [CSC].
+//
+// Elements of the major-th row (CSR) or column (CSC) are stored in
+// indices[indptr[major]:indptr[major+1]] (minor indices, in ascending order) and
+// values[indptr[major]:indptr[major+1]]. Explicit zeros are never stored.
+type compressed[T iter.Numeric] struct {
+	major, minor int
+	indptr       []int
+	indices      []int
+	values       []T
+}
+
+// compress builds the compressed representation from a list of entries,
+// summing up duplicates and dropping zeros.
+func compress[T iter.Numeric](major, minor int, entries []Triple[T], key func(Triple[T]) (int, int)) compressed[T] {
+	c := compressed[T]{major: major, minor: minor, indptr: make([]int, major+1)}
+
+	// Counting sort by the major index
+	for _, e := range entries {
+		ma, _ := key(e)
+		c.indptr[ma+1]++
+	}
+	for i := 0; i < major; i++ {
+		c.indptr[i+1] += c.indptr[i]
+	}
+
+	indices := make([]int, len(entries))
+	values := make([]T, len(entries))
+	next := append([]int(nil), c.indptr[:major]...)
+	for _, e := range entries {
+		ma, mi := key(e)
+		indices[next[ma]] = mi
+		values[next[ma]] = e.Value
+		next[ma]++
+	}
+
+	// Sort every line by the minor index, and merge duplicates
+	c.indices = indices[:0]
+	c.values = values[:0]
+	start := 0
+	for ma := 0; ma < major; ma++ {
+		end := c.indptr[ma+1]
+		line := minorSorter[T]{indices[start:end], values[start:end]}
+		sort.Stable(line)
+
+		for k := range line.indices {
+			if k > 0 && line.indices[k] == line.indices[k-1] {
+				c.values[len(c.values)-1] += line.values[k]
+			} else {
+				c.indices = append(c.indices, line.indices[k])
+				c.values = append(c.values, line.values[k])
+			}
+		}
+
+		// Drop zeros produced by summing up (or explicitly stored)
+		lineStart := c.indptr[ma]
+		kept := lineStart
+		for k := lineStart; k < len(c.values); k++ {
+			if c.values[k] != 0 {
+				c.indices[kept] = c.indices[k]
+				c.values[kept] = c.values[k]
+				kept++
+			}
+		}
+		c.indices = c.indices[:kept]
+		c.values = c.values[:kept]
+
+		start = end
+		c.indptr[ma+1] = kept
+	}
+
+	return c
+}
+
+type minorSorter[T any] struct {
+	indices []int
+	values  []T
+}
+
+func (s minorSorter[T]) Len() int           { return len(s.indices) }
+func (s minorSorter[T]) Less(i, j int) bool { return s.indices[i] < s.indices[j] }
+func (s minorSorter[T]) Swap(i, j int) {
+	s.indices[i], s.indices[j] = s.indices[j], s.indices[i]
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+}
+
+func (c *compressed[T]) nnz() int { return len(c.values) }
+
+func (c *compressed[T]) at(ma, mi int) T {
+	line := c.indices[c.indptr[ma]:c.indptr[ma+1]]
+	k := sort.SearchInts(line, mi)
+	if k < len(line) && line[k] == mi {
+		return c.values[c.indptr[ma]+k]
+	}
+	return 0
+}
+
+// flip returns the same matrix, compressed along the other dimension.
+//
+// Complexity: O(major + minor + nnz)
+func (c *compressed[T]) flip() compressed[T] {
+	f := compressed[T]{
+		major:   c.minor,
+		minor:   c.major,
+		indptr:  make([]int, c.minor+1),
+		indices: make([]int, len(c.indices)),
+		values:  make([]T, len(c.values)),
+	}
+
+	for _, mi := range c.indices {
+		f.indptr[mi+1]++
+	}
+	for i := 0; i < f.major; i++ {
+		f.indptr[i+1] += f.indptr[i]
+	}
+
+	// Iterating in major order guarantees the new minor indices are sorted
+	next := append([]int(nil), f.indptr[:f.major]...)
+	for ma := 0; ma < c.major; ma++ {
+		for k := c.indptr[ma]; k < c.indptr[ma+1]; k++ {
+			mi := c.indices[k]
+			f.indices[next[mi]] = ma
+			f.values[next[mi]] = c.values[k]
+			next[mi]++
+		}
+	}
+	return f
+}
+
+// scale returns the matrix with every element multiplied by k.
+// Products which turn out to be zero (e.g. due to underflow or overflow) are dropped.
+func (c *compressed[T]) scale(k T) compressed[T] {
+	r := compressed[T]{major: c.major, minor: c.minor, indptr: make([]int, c.major+1)}
+
+	for ma := 0; ma < c.major; ma++ {
+		for i := c.indptr[ma]; i < c.indptr[ma+1]; i++ {
+			if v := c.values[i] * k; v != 0 {
+				r.indices = append(r.indices, c.indices[i])
+				r.values = append(r.values, v)
+			}
+		}
+		r.indptr[ma+1] = len(r.values)
+	}
+
+	return r
+}
+
+// add returns the element-wise sum of two matrices with the same orientation and dimensions.
+func (c *compressed[T]) add(o *compressed[T]) compressed[T] {
+	r := compressed[T]{major: c.major, minor: c.minor, indptr: make([]int, c.major+1)}
+
+	for ma := 0; ma < c.major; ma++ {
+		a, aEnd := c.indptr[ma], c.indptr[ma+1]
+		b, bEnd := o.indptr[ma], o.indptr[ma+1]
+
+		for a < aEnd || b < bEnd {
+			var mi int
+			var v T
+			switch {
+			case b >= bEnd || (a < aEnd && c.indices[a] < o.indices[b]):
+				mi, v = c.indices[a], c.values[a]
+				a++
+			case a >= aEnd || o.indices[b] < c.indices[a]:
+				mi, v = o.indices[b], o.values[b]
+				b++
+			default:
+				mi, v = c.indices[a], c.values[a]+o.values[b]
+				a++
+				b++
+			}
+
+			if v != 0 {
+				r.indices = append(r.indices, mi)
+				r.values = append(r.values, v)
+			}
+		}
+
+		r.indptr[ma+1] = len(r.values)
+	}
+
+	return r
+}
+
+// triples calls f for every stored element, in major order.
+func (c *compressed[T]) triples(f func(ma, mi int, v T)) {
+	for ma := 0; ma < c.major; ma++ {
+		for k := c.indptr[ma]; k < c.indptr[ma+1]; k++ {
+			f(ma, c.indices[k], c.values[k])
+		}
+	}
+}
+
+// compressedIterator generates (row, col, value) triples of a compressed matrix.
+type compressedIterator[T iter.Numeric] struct {
+	c        *compressed[T]
+	ma, k    int
+	rowMajor bool
+}
+
+func (i *compressedIterator[T]) Next() bool {
+	if i.k+1 >= i.c.nnz() {
+		i.k = i.c.nnz()
+		return false
+	}
+
+	i.k++
+	for i.c.indptr[i.ma+1] <= i.k {
+		i.ma++
+	}
+	return true
+}
+
+func (i *compressedIterator[T]) Get() Triple[T] {
+	if i.rowMajor {
+		return Triple[T]{i.ma, i.c.indices[i.k], i.c.values[i.k]}
+	}
+	return Triple[T]{i.c.indices[i.k], i.ma, i.c.values[i.k]}
+}
+
+func (i *compressedIterator[T]) Err() error { return nil }
+
+// CSR is a sparse matrix in the compressed sparse row format.
+//
+// CSR matrices are immutable - all operations return new matrices.
+// Explicit zeros are never stored.
+type CSR[T iter.Numeric] struct {
+	c compressed[T]
+}
+
+// CSRFromDense returns a sparse matrix with the non-zero elements of a dense matrix.
+func CSRFromDense[T iter.Numeric](d *Dense[T]) *CSR[T] { return COOFromDense(d).ToCSR() }
+
+// Dims returns the number of rows and columns of the matrix.
+func (m *CSR[T]) Dims() (rows, cols int) { return m.c.major, m.c.minor }
+
+// NNZ returns the number of non-zero elements.
+func (m *CSR[T]) NNZ() int { return m.c.nnz() }
+
+// At returns the element at the i-th row and j-th column.
+//
+// Complexity: O(log k), where k is the number of non-zero elements in the i-th row.
+func (m *CSR[T]) At(i, j int) T {
+	checkIndex(i, j, m.c.major, m.c.minor)
+	return m.c.at(i, j)
+}
+
+// Iter returns an iterator over the non-zero elements, ordered by row, then by column.
+func (m *CSR[T]) Iter() iter.Iterator[Triple[T]] {
+	return &compressedIterator[T]{c: &m.c, k: -1, rowMajor: true}
+}
+
+// Transpose returns a new matrix with rows and columns of m swapped.
+//
+// Complexity: O(rows + cols + nnz)
+func (m *CSR[T]) Transpose() *CSR[T] {
+	// CSC representation of m is the CSR representation of mᵀ
+	return &CSR[T]{m.c.flip()}
+}
+
+// Add returns a new matrix with the element-wise sum of m and o.
+func (m *CSR[T]) Add(o *CSR[T]) *CSR[T] {
+	checkSameDims(m.c.major, m.c.minor, o.c.major, o.c.minor)
+	return &CSR[T]{m.c.add(&o.c)}
+}
+
+// Scale returns a new matrix with every element of m multiplied by k.
+func (m *CSR[T]) Scale(k T) *CSR[T] {
+	return &CSR[T]{m.c.scale(k)}
+}
+
+// Mul returns the dense product of m and a dense matrix d.
+// Panics if m.Cols() != d.Rows().
+//
+// Complexity: O(nnz * d.Cols())
+func (m *CSR[T]) Mul(d *Dense[T]) *Dense[T] {
+	checkMulDims(m.c.major, m.c.minor, d.rows, d.cols)
+	r := Zero[T](m.c.major, d.cols)
+	m.c.triples(func(i, k int, v T) {
+		dst, src := r.rowSlice(i), d.rowSlice(k)
+		for j := range dst {
+			dst[j] += v * src[j]
+		}
+	})
+	return r
+}
+
+// MulVec returns the product of m and a column vector x.
+// Panics if len(x) != m.Cols().
+//
+// Complexity: O(nnz)
+func (m *CSR[T]) MulVec(x []T) []T {
+	checkMulDims(m.c.major, m.c.minor, len(x), 1)
+	r := make([]T, m.c.major)
+	m.c.triples(func(i, k int, v T) { r[i] += v * x[k] })
+	return r
+}
+
+// ToCOO converts the matrix into the coordinate format.
+func (m *CSR[T]) ToCOO() *COO[T] {
+	coo := &COO[T]{rows: m.c.major, cols: m.c.minor, entries: make([]Triple[T], 0, m.c.nnz())}
+	m.c.triples(func(i, j int, v T) { coo.entries = append(coo.entries, Triple[T]{i, j, v}) })
+	return coo
+}
+
+// ToCSC converts the matrix into the compressed sparse column format.
+//
+// Complexity: O(rows + cols + nnz)
+func (m *CSR[T]) ToCSC() *CSC[T] { return &CSC[T]{m.c.flip()} }
+
+// ToDense returns a dense matrix with the same elements.
+func (m *CSR[T]) ToDense() *Dense[T] {
+	d := Zero[T](m.c.major, m.c.minor)
+	m.c.triples(func(i, j int, v T) { d.data[i*d.stride+j] = v })
+	return d
+}
+
+// CSC is a sparse matrix in the compressed sparse column format.
+//
+// CSC matrices are immutable - all operations return new matrices.
+// Explicit zeros are never stored.
+type CSC[T iter.Numeric] struct {
+	c compressed[T]
+}
+
+// CSCFromDense returns a sparse matrix with the non-zero elements of a dense matrix.
+func CSCFromDense[T iter.Numeric](d *Dense[T]) *CSC[T] { return COOFromDense(d).ToCSC() }
+
+// Dims returns the number of rows and columns of the matrix.
+func (m *CSC[T]) Dims() (rows, cols int) { return m.c.minor, m.c.major }
+
+// NNZ returns the number of non-zero elements.
+func (m *CSC[T]) NNZ() int { return m.c.nnz() }
+
+// At returns the element at the i-th row and j-th column.
+//
+// Complexity: O(log k), where k is the number of non-zero elements in the j-th column.
+func (m *CSC[T]) At(i, j int) T {
+	checkIndex(i, j, m.c.minor, m.c.major)
+	return m.c.at(j, i)
+}
+
+// Iter returns an iterator over the non-zero elements, ordered by column, then by row.
+func (m *CSC[T]) Iter() iter.Iterator[Triple[T]] {
+	return &compressedIterator[T]{c: &m.c, k: -1, rowMajor: false}
+}
+
+// Transpose returns a new matrix with rows and columns of m swapped.
+//
+// Complexity: O(rows + cols + nnz)
+func (m *CSC[T]) Transpose() *CSC[T] {
+	// CSR representation of m is the CSC representation of mᵀ
+	return &CSC[T]{m.c.flip()}
+}
+
+// Add returns a new matrix with the element-wise sum of m and o.
+func (m *CSC[T]) Add(o *CSC[T]) *CSC[T] {
+	checkSameDims(m.c.minor, m.c.major, o.c.minor, o.c.major)
+	return &CSC[T]{m.c.add(&o.c)}
+}
+
+// Scale returns a new matrix with every element of m multiplied by k.
+func (m *CSC[T]) Scale(k T) *CSC[T] {
+	return &CSC[T]{m.c.scale(k)}
+}
+
+// Mul returns the dense product of m and a dense matrix d.
+// Panics if m.Cols() != d.Rows().
+//
+// Complexity: O(nnz * d.Cols())
+func (m *CSC[T]) Mul(d *Dense[T]) *Dense[T] {
+	checkMulDims(m.c.minor, m.c.major, d.rows, d.cols)
+	r := Zero[T](m.c.minor, d.cols)
+	m.c.triples(func(k, i int, v T) {
+		dst, src := r.rowSlice(i), d.rowSlice(k)
+		for j := range dst {
+			dst[j] += v * src[j]
+		}
+	})
+	return r
+}
+
+// MulVec returns the product of m and a column vector x.
+// Panics if len(x) != m.Cols().
+//
+// Complexity: O(nnz)
+func (m *CSC[T]) MulVec(x []T) []T {
+	checkMulDims(m.c.minor, m.c.major, len(x), 1)
+	r := make([]T, m.c.minor)
+	m.c.triples(func(k, i int, v T) { r[i] += v * x[k] })
+	return r
+}
+
+// ToCOO converts the matrix into the coordinate format.
+func (m *CSC[T]) ToCOO() *COO[T] {
+	coo := &COO[T]{rows: m.c.minor, cols: m.c.major, entries: make([]Triple[T], 0, m.c.nnz())}
+	m.c.triples(func(j, i int, v T) { coo.entries = append(coo.entries, Triple[T]{i, j, v}) })
+	return coo
+}
+
+// ToCSR converts the matrix into the compressed sparse row format.
+//
+// Complexity: O(rows + cols + nnz)
+func (m *CSC[T]) ToCSR() *CSR[T] { return &CSR[T]{m.c.flip()} }
+
+// ToDense returns a dense matrix with the same elements.
+func (m *CSC[T]) ToDense() *Dense[T] {
+	d := Zero[T](m.c.minor, m.c.major)
+	m.c.triples(func(j, i int, v T) { d.data[i*d.stride+j] = v })
+	return d
+}
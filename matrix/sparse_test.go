@@ -0,0 +1,166 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package matrix_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/iter"
+	"github.com/MKuranowski/go-extra-lib/matrix"
+	"github.com/MKuranowski/go-extra-lib/testing2/assert"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func exampleCOO() *matrix.COO[int] {
+	// [0 1 0 2]
+	// [0 0 0 0]
+	// [3 0 4 0]
+	m := matrix.NewCOO[int](3, 4)
+	m.Append(2, 2, 4)
+	m.Append(0, 3, 1)
+	m.Append(0, 1, 1)
+	m.Append(2, 0, 3)
+	m.Append(0, 3, 1) // duplicate, summed up
+	m.Append(1, 1, 5)
+	m.Append(1, 1, -5) // sums up to zero, dropped
+	return m
+}
+
+func exampleDense() *matrix.Dense[int] {
+	return matrix.FromRows([][]int{{0, 1, 0, 2}, {0, 0, 0, 0}, {3, 0, 4, 0}})
+}
+
+func TestSparseConversions(t *testing.T) {
+	coo := exampleCOO()
+	check.EqMsg(t, coo.NNZ(), 7, "coo.NNZ()")
+	check.TrueMsg(t, coo.ToDense().Equal(exampleDense()), "coo.ToDense()")
+
+	csr := coo.ToCSR()
+	rows, cols := csr.Dims()
+	check.EqMsg(t, rows, 3, "csr rows")
+	check.EqMsg(t, cols, 4, "csr cols")
+	check.EqMsg(t, csr.NNZ(), 4, "csr.NNZ()")
+	check.TrueMsg(t, csr.ToDense().Equal(exampleDense()), "csr.ToDense()")
+	check.EqMsg(t, csr.At(0, 3), 2, "csr.At(0, 3)")
+	check.EqMsg(t, csr.At(1, 1), 0, "csr.At(1, 1)")
+
+	csc := coo.ToCSC()
+	check.EqMsg(t, csc.NNZ(), 4, "csc.NNZ()")
+	check.TrueMsg(t, csc.ToDense().Equal(exampleDense()), "csc.ToDense()")
+	check.EqMsg(t, csc.At(2, 0), 3, "csc.At(2, 0)")
+	check.EqMsg(t, csc.At(2, 1), 0, "csc.At(2, 1)")
+
+	check.TrueMsg(t, csr.ToCSC().ToDense().Equal(exampleDense()), "csr.ToCSC()")
+	check.TrueMsg(t, csc.ToCSR().ToDense().Equal(exampleDense()), "csc.ToCSR()")
+	check.TrueMsg(t, csr.ToCOO().ToDense().Equal(exampleDense()), "csr.ToCOO()")
+	check.TrueMsg(t, csc.ToCOO().ToDense().Equal(exampleDense()), "csc.ToCOO()")
+	check.TrueMsg(t, matrix.CSRFromDense(exampleDense()).ToDense().Equal(exampleDense()), "CSRFromDense")
+	check.TrueMsg(t, matrix.CSCFromDense(exampleDense()).ToDense().Equal(exampleDense()), "CSCFromDense")
+}
+
+func TestSparseIter(t *testing.T) {
+	check.DeepEqMsg(
+		t,
+		iter.IntoSlice(exampleCOO().ToCSR().Iter()),
+		[]matrix.Triple[int]{{Row: 0, Col: 1, Value: 1}, {Row: 0, Col: 3, Value: 2}, {Row: 2, Col: 0, Value: 3}, {Row: 2, Col: 2, Value: 4}},
+		"csr.Iter()",
+	)
+
+	check.DeepEqMsg(
+		t,
+		iter.IntoSlice(exampleCOO().ToCSC().Iter()),
+		[]matrix.Triple[int]{{Row: 2, Col: 0, Value: 3}, {Row: 0, Col: 1, Value: 1}, {Row: 2, Col: 2, Value: 4}, {Row: 0, Col: 3, Value: 2}},
+		"csc.Iter()",
+	)
+
+	check.DeepEqMsg(t, iter.IntoSlice(matrix.NewCOO[int](2, 2).ToCSR().Iter()), []matrix.Triple[int]{}, "empty.Iter()")
+}
+
+func TestCOOFromIter(t *testing.T) {
+	triples := []matrix.Triple[float64]{{Row: 0, Col: 0, Value: 1.5}, {Row: 1, Col: 2, Value: -1}}
+	coo, err := matrix.COOFromIter(2, 3, iter.OverSlice(triples))
+	assert.NoErr(t, err)
+	check.TrueMsg(t, coo.ToDense().Equal(matrix.FromRows([][]float64{{1.5, 0, 0}, {0, 0, -1}})), "COOFromIter")
+
+	testErr := errors.New("test error")
+	_, err = matrix.COOFromIter(2, 3, iter.Error[matrix.Triple[float64]](testErr))
+	check.SpecificErrMsg(t, err, testErr, "COOFromIter(error)")
+
+	outOfRange := []matrix.Triple[float64]{{Row: 0, Col: 0, Value: 1}, {Row: 2, Col: 1, Value: 1}}
+	coo, err = matrix.COOFromIter(2, 3, iter.OverSlice(outOfRange))
+	check.SpecificErrMsg(t, err, matrix.ErrOutOfRange, "COOFromIter(out of range)")
+	check.EqMsg(t, err.Error(), "matrix: triple out of range: (2, 1) for a 2×3 matrix", "COOFromIter(out of range).Error()")
+	check.TrueMsg(t, coo == nil, "COOFromIter(out of range) returned a matrix")
+
+	negative := []matrix.Triple[float64]{{Row: 0, Col: -1, Value: 1}}
+	_, err = matrix.COOFromIter(2, 3, iter.OverSlice(negative))
+	check.SpecificErrMsg(t, err, matrix.ErrOutOfRange, "COOFromIter(negative column)")
+}
+
+func TestSparseArithmetic(t *testing.T) {
+	csr := exampleCOO().ToCSR()
+	csc := exampleCOO().ToCSC()
+	d := exampleDense()
+
+	check.TrueMsg(t, csr.Transpose().ToDense().Equal(d.Transpose()), "csrᵀ")
+	check.TrueMsg(t, csc.Transpose().ToDense().Equal(d.Transpose()), "cscᵀ")
+	check.TrueMsg(t, exampleCOO().Transpose().ToDense().Equal(d.Transpose()), "cooᵀ")
+
+	check.TrueMsg(t, csr.Add(csr).ToDense().Equal(d.Scale(2)), "csr + csr")
+	check.TrueMsg(t, csc.Add(csc).ToDense().Equal(d.Scale(2)), "csc + csc")
+	check.EqMsg(t, csr.Add(csr.Scale(-1)).NNZ(), 0, "(csr - csr).NNZ()")
+	check.TrueMsg(t, csr.Scale(3).ToDense().Equal(d.Scale(3)), "csr * 3")
+	check.EqMsg(t, csc.Scale(0).NNZ(), 0, "(csc * 0).NNZ()")
+
+	x := matrix.FromRows([][]int{{1, 2}, {3, 4}, {5, 6}, {7, 8}})
+	check.TrueMsg(t, csr.Mul(x).Equal(d.Mul(x)), "csr × x")
+	check.TrueMsg(t, csc.Mul(x).Equal(d.Mul(x)), "csc × x")
+	check.DeepEqMsg(t, csr.MulVec([]int{1, 2, 3, 4}), []int{10, 0, 15}, "csr × v")
+	check.DeepEqMsg(t, csc.MulVec([]int{1, 2, 3, 4}), []int{10, 0, 15}, "csc × v")
+}
+
+func TestSparseScaleDropsZeros(t *testing.T) {
+	// 16 * 16 overflows int8 to exactly 0
+	ints := matrix.FromRows([][]int8{{16, 1}, {0, 16}})
+	csr := matrix.CSRFromDense(ints).Scale(16)
+	check.EqMsg(t, csr.NNZ(), 1, "(int8 csr * 16).NNZ()")
+	check.EqMsg(t, csr.At(0, 1), int8(16), "(int8 csr * 16)[0, 1]")
+	check.EqMsg(t, matrix.CSCFromDense(ints).Scale(16).NNZ(), 1, "(int8 csc * 16).NNZ()")
+
+	// 1e-200 * 1e-200 underflows to 0
+	floats := matrix.FromRows([][]float64{{1e-200, 0}, {0, 1}})
+	csc := matrix.CSCFromDense(floats).Scale(1e-200)
+	check.EqMsg(t, csc.NNZ(), 1, "(float csc * 1e-200).NNZ()")
+	check.TrueMsg(t, csc.ToDense().Equal(matrix.FromRows([][]float64{{0, 0}, {0, 1e-200}})), "float csc * 1e-200")
+}
+
+func TestSparseRandomized(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	for n := 0; n < 20; n++ {
+		rows, cols := 1+r.Intn(30), 1+r.Intn(30)
+		coo := matrix.NewCOO[int](rows, cols)
+		for k := 0; k < r.Intn(rows*cols); k++ {
+			coo.Append(r.Intn(rows), r.Intn(cols), r.Intn(5)-2)
+		}
+
+		d := coo.ToDense()
+		csr, csc := coo.ToCSR(), coo.ToCSC()
+		check.TrueMsg(t, csr.ToDense().Equal(d), "csr.ToDense()")
+		check.TrueMsg(t, csc.ToDense().Equal(d), "csc.ToDense()")
+		check.TrueMsg(t, csr.Transpose().ToDense().Equal(d.Transpose()), "csrᵀ")
+		check.EqMsg(t, csr.NNZ(), csc.NNZ(), "csr.NNZ() == csc.NNZ()")
+
+		x := matrix.Zero[int](cols, 3)
+		for i := 0; i < cols; i++ {
+			for j := 0; j < 3; j++ {
+				x.Set(i, j, r.Intn(10))
+			}
+		}
+		check.TrueMsg(t, csr.Mul(x).Equal(d.Mul(x)), "csr × x")
+		check.TrueMsg(t, csc.Mul(x).Equal(d.Mul(x)), "csc × x")
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package slices2
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// parallelBlockSize is the number of elements processed by a single task
+// in ParallelMap, ParallelFilter and ParallelReduce.
+//
+// The block size doesn't depend on the number of workers,
+// which makes the results of ParallelReduce deterministic.
+const parallelBlockSize = 4096
+
+// resolveWorkers returns the number of workers to use: workers if positive,
+// runtime.GOMAXPROCS(0) otherwise.
+func resolveWorkers(workers int) int {
+	if workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return workers
+}
+
+// parallelFor calls task(i) for every i in [0, tasks), using at most workers goroutines.
+// Returns once all tasks are completed.
+func parallelFor(tasks, workers int, task func(i int)) {
+	workers = resolveWorkers(workers)
+	if workers > tasks {
+		workers = tasks
+	}
+
+	if workers <= 1 {
+		for i := 0; i < tasks; i++ {
+			task(i)
+		}
+		return
+	}
+
+	next := int64(-1)
+	wg := &sync.WaitGroup{}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= tasks {
+					return
+				}
+				task(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// blockCount returns the number of blocks of parallelBlockSize elements required to cover n elements.
+func blockCount(n int) int {
+	return (n + parallelBlockSize - 1) / parallelBlockSize
+}
+
+// blockRange returns the range of indices [lo, hi) covered by the i-th block out of n elements.
+func blockRange(i, n int) (lo, hi int) {
+	lo = i * parallelBlockSize
+	hi = lo + parallelBlockSize
+	if hi > n {
+		hi = n
+	}
+	return
+}
+
+// ParallelSort sorts a slice in ascending order, using a parallel merge sort
+// with the provided number of worker goroutines. If workers <= 0, runtime.GOMAXPROCS(0) is used.
+//
+// The sort is stable, thus the result doesn't depend on the number of workers.
+//
+// Complexity: O(n log n), uses O(n) additional memory
+func ParallelSort[E constraints.Ordered](s []E, workers int) {
+	ParallelSortFunc(s, less[E], workers)
+}
+
+// ParallelSortFunc sorts a slice ordered by the provided less function, using a parallel merge sort
+// with the provided number of worker goroutines. If workers <= 0, runtime.GOMAXPROCS(0) is used.
+//
+// The sort is stable, thus the result doesn't depend on the number of workers.
+//
+// Complexity: O(n log n), uses O(n) additional memory
+func ParallelSortFunc[E any](s []E, less func(a, b E) bool, workers int) {
+	workers = resolveWorkers(workers)
+
+	// Don't bother with tiny chunks - goroutine overhead would dominate
+	chunks := workers
+	if max := len(s) / parallelBlockSize; chunks > max {
+		chunks = max
+	}
+	if chunks <= 1 {
+		slices.SortStableFunc(s, less)
+		return
+	}
+
+	// Sort every chunk independently
+	bounds := make([]int, chunks+1)
+	for i := range bounds {
+		bounds[i] = i * len(s) / chunks
+	}
+	parallelFor(chunks, workers, func(i int) {
+		slices.SortStableFunc(s[bounds[i]:bounds[i+1]], less)
+	})
+
+	// Merge pairs of adjacent chunks, until only one is left
+	src, dst := s, make([]E, len(s))
+	for len(bounds) > 2 {
+		chunks := len(bounds) - 1
+		parallelFor((chunks+1)/2, workers, func(i int) {
+			lo := bounds[2*i]
+			if 2*i+2 < len(bounds) {
+				mid, hi := bounds[2*i+1], bounds[2*i+2]
+				mergeInto(dst[lo:hi], src[lo:mid], src[mid:hi], less)
+			} else {
+				copy(dst[lo:], src[lo:])
+			}
+		})
+
+		newBounds := make([]int, 0, (chunks+1)/2+1)
+		for i := 0; i < chunks; i += 2 {
+			newBounds = append(newBounds, bounds[i])
+		}
+		bounds = append(newBounds, len(s))
+		src, dst = dst, src
+	}
+
+	if &src[0] != &s[0] {
+		copy(s, src)
+	}
+}
+
+// mergeInto stably merges sorted slices a and b into dst.
+// len(dst) must be equal to len(a) + len(b).
+func mergeInto[E any](dst, a, b []E, less func(a, b E) bool) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if less(b[j], a[i]) {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// ParallelMap returns a new slice with f applied to every element of s,
+// using the provided number of worker goroutines. If workers <= 0, runtime.GOMAXPROCS(0) is used.
+//
+// f must be safe to call concurrently.
+//
+// Example:
+//
+//	ParallelMap([]int{1, 2, 3}, strconv.Itoa, 0)  // → ["1" "2" "3"]
+func ParallelMap[S ~[]E, E, F any](s S, f func(E) F, workers int) []F {
+	r := make([]F, len(s))
+	parallelFor(blockCount(len(s)), workers, func(b int) {
+		lo, hi := blockRange(b, len(s))
+		for i := lo; i < hi; i++ {
+			r[i] = f(s[i])
+		}
+	})
+	return r
+}
+
+// ParallelFilter returns a new slice with elements of s for which keep(x) returns true,
+// using the provided number of worker goroutines. If workers <= 0, runtime.GOMAXPROCS(0) is used.
+// The order of elements is preserved.
+//
+// keep must be safe to call concurrently.
+//
+// As opposed to [Filter], s is not modified.
+func ParallelFilter[S ~[]E, E any](s S, keep func(E) bool, workers int) S {
+	parts := make([]S, blockCount(len(s)))
+	parallelFor(len(parts), workers, func(b int) {
+		lo, hi := blockRange(b, len(s))
+		for _, x := range s[lo:hi] {
+			if keep(x) {
+				parts[b] = append(parts[b], x)
+			}
+		}
+	})
+	return Flatten(parts)
+}
+
+// ParallelReduce applies a function of two arguments cumulatively to the elements of s,
+// using the provided number of worker goroutines. If workers <= 0, runtime.GOMAXPROCS(0) is used.
+// If s is empty, returns the zero value of E and ok is set to false.
+//
+// f must be associative and safe to call concurrently. Elements are split into fixed-size
+// blocks, which are reduced in parallel; and then the partial results are reduced in order.
+// As the blocks don't depend on the number of workers, neither does the result -
+// even for operations which are only approximately associative, like floating-point addition.
+//
+// Example:
+//
+//	ParallelReduce([]int{1, 2, 3, 4, 5}, func(a, b int) int { return a + b }, 0)  // → 15, true
+func ParallelReduce[S ~[]E, E any](s S, f func(E, E) E, workers int) (r E, ok bool) {
+	if len(s) == 0 {
+		return
+	}
+
+	partials := make([]E, blockCount(len(s)))
+	parallelFor(len(partials), workers, func(b int) {
+		lo, hi := blockRange(b, len(s))
+		acc := s[lo]
+		for _, x := range s[lo+1 : hi] {
+			acc = f(acc, x)
+		}
+		partials[b] = acc
+	})
+
+	r = partials[0]
+	for _, x := range partials[1:] {
+		r = f(r, x)
+	}
+	return r, true
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package slices2_test
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/slices2"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+	"golang.org/x/exp/slices"
+)
+
+var workerCounts = []int{0, 1, 2, 3, 8, 32}
+
+func randomFloats(r *rand.Rand, n int) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = r.NormFloat64() * 1e6
+	}
+	return s
+}
+
+func TestParallelSort(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	for _, size := range []int{0, 1, 1000, 100000} {
+		input := randomInts(r, size, 1000)
+		expected := slices.Clone(input)
+		slices.Sort(expected)
+
+		for _, workers := range workerCounts {
+			s := slices.Clone(input)
+			slices2.ParallelSort(s, workers)
+			check.DeepEqMsg(t, s, expected, "ParallelSort(size="+strconv.Itoa(size)+", workers="+strconv.Itoa(workers)+")")
+		}
+	}
+}
+
+func TestParallelSortFuncIsStable(t *testing.T) {
+	type item struct{ key, index int }
+
+	r := rand.New(rand.NewSource(42))
+	input := make([]item, 50000)
+	for i := range input {
+		input[i] = item{key: r.Intn(100), index: i}
+	}
+	byKey := func(a, b item) bool { return a.key < b.key }
+
+	expected := slices.Clone(input)
+	slices.SortStableFunc(expected, byKey)
+
+	for _, workers := range workerCounts {
+		s := slices.Clone(input)
+		slices2.ParallelSortFunc(s, byKey, workers)
+		check.DeepEqMsg(t, s, expected, "ParallelSortFunc(workers="+strconv.Itoa(workers)+")")
+	}
+}
+
+func TestParallelMap(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	input := randomInts(r, 10000, 1000000)
+
+	expected := make([]string, len(input))
+	for i, x := range input {
+		expected[i] = strconv.Itoa(x)
+	}
+
+	for _, workers := range workerCounts {
+		check.DeepEqMsg(t, slices2.ParallelMap(input, strconv.Itoa, workers), expected, "ParallelMap")
+	}
+	check.EqMsg(t, len(slices2.ParallelMap([]int{}, strconv.Itoa, 4)), 0, "len(ParallelMap([]))")
+}
+
+func TestParallelFilter(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	input := randomInts(r, 10000, 1000000)
+	expected := slices2.Filter(slices.Clone(input), isEven)
+
+	for _, workers := range workerCounts {
+		check.DeepEqMsg(t, slices2.ParallelFilter(input, isEven, workers), expected, "ParallelFilter")
+	}
+}
+
+func TestParallelReduce(t *testing.T) {
+	sum := func(a, b int) int { return a + b }
+	total, ok := slices2.ParallelReduce([]int{1, 2, 3, 4, 5}, sum, 0)
+	check.EqMsg(t, total, 15, "ParallelReduce([1 2 3 4 5], sum)")
+	check.TrueMsg(t, ok, "ParallelReduce([1 2 3 4 5], sum): ok")
+
+	total, ok = slices2.ParallelReduce([]int{}, sum, 0)
+	check.EqMsg(t, total, 0, "ParallelReduce([], sum)")
+	check.FalseMsg(t, ok, "ParallelReduce([], sum): ok")
+}
+
+func TestParallelReduceDeterministic(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	input := randomFloats(r, 100000)
+	sum := func(a, b float64) float64 { return a + b }
+
+	expected, _ := slices2.ParallelReduce(input, sum, 1)
+	for _, workers := range workerCounts {
+		got, _ := slices2.ParallelReduce(input, sum, workers)
+		check.EqMsg(t, got, expected, "ParallelReduce(workers="+strconv.Itoa(workers)+")")
+	}
+}
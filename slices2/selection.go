@@ -0,0 +1,252 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package slices2
+
+import (
+	"math/bits"
+	"math/rand"
+
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// Partition reorders s in-place, so that all elements for which pred returns true
+// come before all elements for which pred returns false. Returns the number of elements
+// for which pred returned true - the index of the first element of the second group.
+//
+// Relative order of the elements is not preserved, see [StablePartition].
+//
+// Example:
+//
+//	s := []int{1, 2, 3, 4, 5, 6}
+//	Partition(s, isEven)  // → 3, s is now [6 2 4 3 5 1]
+//
+// Complexity: O(n)
+func Partition[S ~[]E, E any](s S, pred func(E) bool) int {
+	i, j := 0, len(s)-1
+	for {
+		for i <= j && pred(s[i]) {
+			i++
+		}
+		for i <= j && !pred(s[j]) {
+			j--
+		}
+		if i >= j {
+			return i
+		}
+		s[i], s[j] = s[j], s[i]
+		i++
+		j--
+	}
+}
+
+// StablePartition reorders s in-place, so that all elements for which pred returns true
+// come before all elements for which pred returns false, while preserving
+// the relative order of elements in both groups. Returns the number of elements
+// for which pred returned true - the index of the first element of the second group.
+//
+// Example:
+//
+//	s := []int{1, 2, 3, 4, 5, 6}
+//	StablePartition(s, isEven)  // → 3, s is now [2 4 6 1 3 5]
+//
+// Complexity: O(n), uses O(n) additional memory
+func StablePartition[S ~[]E, E any](s S, pred func(E) bool) int {
+	var rejected S
+	n := 0
+	for _, x := range s {
+		if pred(x) {
+			s[n] = x
+			n++
+		} else {
+			rejected = append(rejected, x)
+		}
+	}
+	copy(s[n:], rejected)
+	return n
+}
+
+// NthElement reorders s in-place, so that s[n] is the element which would be
+// at that position if s was sorted; all elements before s[n] are not greater than it,
+// and all elements after s[n] are not less than it.
+//
+// Panics if n is out of range.
+//
+// Example:
+//
+//	s := []int{5, 1, 4, 2, 3}
+//	NthElement(s, 2)  // s[2] is now 3, the median
+//
+// Complexity: O(n) on average, O(n log n) in the worst case
+func NthElement[E constraints.Ordered](s []E, n int) {
+	NthElementFunc(s, n, less[E])
+}
+
+// NthElementFunc reorders s in-place, so that s[n] is the element which would be
+// at that position if s was sorted with the provided less function;
+// no element before s[n] is greater than it, and no element after s[n] is less than it.
+//
+// Uses introselect: quickselect with a median-of-three pivot,
+// falling back to sorting if too many partitioning steps are needed.
+//
+// Panics if n is out of range.
+//
+// Complexity: O(n) on average, O(n log n) in the worst case
+func NthElementFunc[E any](s []E, n int, less func(a, b E) bool) {
+	_ = s[n] // bounds-check
+
+	lo, hi := 0, len(s)
+	depthLimit := 2 * bits.Len(uint(len(s)))
+
+	for hi-lo > 12 {
+		if depthLimit == 0 {
+			slices.SortFunc(s[lo:hi], less)
+			return
+		}
+		depthLimit--
+
+		lt, gt := partition3Way(s[lo:hi], less)
+		lt, gt = lt+lo, gt+lo
+		if n < lt {
+			hi = lt
+		} else if n >= gt {
+			lo = gt
+		} else {
+			return
+		}
+	}
+
+	insertionSort(s[lo:hi], less)
+}
+
+// partition3Way reorders s around a median-of-three pivot into elements
+// less than the pivot (s[:lt]), equal to the pivot (s[lt:gt]) and greater than the pivot (s[gt:]).
+func partition3Way[E any](s []E, less func(a, b E) bool) (lt, gt int) {
+	a, b, c := 0, len(s)/2, len(s)-1
+	if less(s[b], s[a]) {
+		a, b = b, a
+	}
+	if less(s[c], s[b]) {
+		b = c
+		if less(s[b], s[a]) {
+			b = a
+		}
+	}
+	pivot := s[b]
+
+	lt, gt = 0, len(s)
+	for i := 0; i < gt; {
+		if less(s[i], pivot) {
+			s[lt], s[i] = s[i], s[lt]
+			lt++
+			i++
+		} else if less(pivot, s[i]) {
+			gt--
+			s[i], s[gt] = s[gt], s[i]
+		} else {
+			i++
+		}
+	}
+	return
+}
+
+func insertionSort[E any](s []E, less func(a, b E) bool) {
+	for i := 1; i < len(s); i++ {
+		for j := i; j > 0 && less(s[j], s[j-1]); j-- {
+			s[j], s[j-1] = s[j-1], s[j]
+		}
+	}
+}
+
+// PartialSort reorders s in-place, so that s[:k] contains the k smallest elements of s,
+// in ascending order. Order of the remaining elements is unspecified.
+//
+// Panics if k is out of range.
+//
+// Example:
+//
+//	s := []int{5, 1, 4, 2, 3}
+//	PartialSort(s, 2)  // s[:2] is now [1 2]
+//
+// Complexity: O(n + k log k) on average
+func PartialSort[E constraints.Ordered](s []E, k int) {
+	PartialSortFunc(s, k, less[E])
+}
+
+// PartialSortFunc reorders s in-place, so that s[:k] contains the k smallest elements of s,
+// ordered by the provided less function. Order of the remaining elements is unspecified.
+//
+// Panics if k is out of range.
+//
+// Complexity: O(n + k log k) on average
+func PartialSortFunc[E any](s []E, k int, less func(a, b E) bool) {
+	_ = s[:k] // bounds-check
+	if k == 0 {
+		return
+	}
+	if k < len(s) {
+		NthElementFunc(s, k-1, less)
+	}
+	slices.SortFunc(s[:k], less)
+}
+
+// TopK returns a new slice with the k greatest elements of s, in descending order.
+// If k >= len(s), returns all elements of s. s is not modified.
+//
+// Example:
+//
+//	TopK([]int{5, 1, 4, 2, 3}, 2)  // → [5 4]
+//
+// Complexity: O(n + k log k) on average
+func TopK[S ~[]E, E constraints.Ordered](s S, k int) S {
+	return TopKFunc(s, k, less[E])
+}
+
+// TopKFunc returns a new slice with the k greatest elements of s (according to the provided
+// less function), in descending order. If k >= len(s), returns all elements of s.
+// s is not modified.
+//
+// Complexity: O(n + k log k) on average
+func TopKFunc[S ~[]E, E any](s S, k int, less func(a, b E) bool) S {
+	return BottomKFunc(s, k, func(a, b E) bool { return less(b, a) })
+}
+
+// BottomK returns a new slice with the k smallest elements of s, in ascending order.
+// If k >= len(s), returns all elements of s. s is not modified.
+//
+// Example:
+//
+//	BottomK([]int{5, 1, 4, 2, 3}, 2)  // → [1 2]
+//
+// Complexity: O(n + k log k) on average
+func BottomK[S ~[]E, E constraints.Ordered](s S, k int) S {
+	return BottomKFunc(s, k, less[E])
+}
+
+// BottomKFunc returns a new slice with the k smallest elements of s (according to the provided
+// less function), in ascending order. If k >= len(s), returns all elements of s.
+// s is not modified.
+//
+// Complexity: O(n + k log k) on average
+func BottomKFunc[S ~[]E, E any](s S, k int, less func(a, b E) bool) S {
+	if k > len(s) {
+		k = len(s)
+	}
+	c := slices.Clone(s)
+	PartialSortFunc(c, k, less)
+	return slices.Clip(c[:k])
+}
+
+// Shuffle pseudo-randomly reorders elements of s in-place, using the provided
+// random number generator. If r is nil, the default source from math/rand is used.
+//
+// Complexity: O(n)
+func Shuffle[E any](s []E, r *rand.Rand) {
+	swap := func(i, j int) { s[i], s[j] = s[j], s[i] }
+	if r == nil {
+		rand.Shuffle(len(s), swap)
+	} else {
+		r.Shuffle(len(s), swap)
+	}
+}
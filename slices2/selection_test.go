@@ -0,0 +1,122 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package slices2_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/slices2"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+	"golang.org/x/exp/slices"
+)
+
+func isEven(x int) bool { return x%2 == 0 }
+
+func randomInts(r *rand.Rand, n, max int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = r.Intn(max)
+	}
+	return s
+}
+
+func TestPartition(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7}
+	n := slices2.Partition(s, isEven)
+	check.EqMsg(t, n, 3, "Partition")
+	for i, x := range s {
+		check.EqMsg(t, isEven(x), i < n, "isEven(s[i])")
+	}
+
+	check.EqMsg(t, slices2.Partition([]int{}, isEven), 0, "Partition([])")
+	check.EqMsg(t, slices2.Partition([]int{2, 4}, isEven), 2, "Partition([2 4])")
+	check.EqMsg(t, slices2.Partition([]int{1, 3}, isEven), 0, "Partition([1 3])")
+}
+
+func TestStablePartition(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7}
+	n := slices2.StablePartition(s, isEven)
+	check.EqMsg(t, n, 3, "StablePartition")
+	check.DeepEqMsg(t, s, []int{2, 4, 6, 1, 3, 5, 7}, "after StablePartition")
+}
+
+func TestNthElement(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	for _, size := range []int{1, 5, 13, 100, 1000} {
+		for _, max := range []int{3, 1000000} {
+			s := randomInts(r, size, max)
+			sorted := slices.Clone(s)
+			slices.Sort(sorted)
+
+			n := r.Intn(size)
+			slices2.NthElement(s, n)
+
+			check.EqMsg(t, s[n], sorted[n], "s[n]")
+			for i := 0; i < n; i++ {
+				if s[i] > s[n] {
+					t.Errorf("NthElement(size=%d, n=%d): s[%d] = %d > s[n] = %d", size, n, i, s[i], s[n])
+				}
+			}
+			for i := n + 1; i < size; i++ {
+				if s[i] < s[n] {
+					t.Errorf("NthElement(size=%d, n=%d): s[%d] = %d < s[n] = %d", size, n, i, s[i], s[n])
+				}
+			}
+		}
+	}
+}
+
+func TestNthElementSorted(t *testing.T) {
+	// Already sorted and reverse-sorted inputs must not degrade
+	s := make([]int, 10000)
+	for i := range s {
+		s[i] = i
+	}
+	slices2.NthElement(s, 5000)
+	check.EqMsg(t, s[5000], 5000, "NthElement(sorted)")
+
+	slices2.Reverse(s)
+	slices2.NthElement(s, 100)
+	check.EqMsg(t, s[100], 100, "NthElement(reversed)")
+}
+
+func TestPartialSort(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	s := randomInts(r, 500, 100)
+	sorted := slices.Clone(s)
+	slices.Sort(sorted)
+
+	slices2.PartialSort(s, 50)
+	check.DeepEqMsg(t, s[:50], sorted[:50], "s[:50]")
+
+	slices2.PartialSort(s, len(s))
+	check.DeepEqMsg(t, s, sorted, "after PartialSort(s, len(s))")
+}
+
+func TestTopK(t *testing.T) {
+	s := []int{5, 1, 4, 2, 3}
+	check.DeepEqMsg(t, slices2.TopK(s, 2), []int{5, 4}, "TopK(s, 2)")
+	check.DeepEqMsg(t, slices2.BottomK(s, 2), []int{1, 2}, "BottomK(s, 2)")
+	check.DeepEqMsg(t, slices2.BottomK(s, 10), []int{1, 2, 3, 4, 5}, "BottomK(s, 10)")
+	check.EqMsg(t, len(slices2.TopK(s, 0)), 0, "len(TopK(s, 0))")
+	check.DeepEqMsg(t, s, []int{5, 1, 4, 2, 3}, "s: after TopK")
+
+	greaterAbs := func(a, b int) bool { return a*a > b*b }
+	check.DeepEqMsg(t, slices2.TopKFunc([]int{-3, 1, 2, -4}, 2, greaterAbs), []int{1, 2}, "TopKFunc")
+	check.DeepEqMsg(t, slices2.BottomKFunc([]int{-3, 1, 2, -4}, 2, greaterAbs), []int{-4, -3}, "BottomKFunc")
+}
+
+func TestShuffle(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	slices2.Shuffle(s, rand.New(rand.NewSource(42)))
+
+	other := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	slices2.Shuffle(other, rand.New(rand.NewSource(42)))
+	check.DeepEqMsg(t, s, other, "shuffles with the same seed")
+
+	slices.Sort(s)
+	check.DeepEqMsg(t, s, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, "sorted after shuffle")
+}
@@ -5,6 +5,8 @@
 // adding a few more common slice operations, most from https://github.com/golang/go/wiki/SliceTricks.
 package slices2
 
+import "github.com/MKuranowski/go-extra-lib/iter"
+
 // Batches partitions slice S into ceil(s / batchSize) parts,
 // each containing at most batchSize elements.
 //
@@ -22,6 +24,28 @@ func Batches[S ~[]E, E any](s S, batchSize int) []S {
 	return batches
 }
 
+// ChunkBy splits s into consecutive runs of elements, for which
+// sameGroup(previous, current) returns true. Returned chunks are subslices of s,
+// with their capacity limited to their length.
+//
+// Example:
+//
+//	ChunkBy([]int{1, 2, 4, 5, 6, 9}, func(a, b int) bool { return b == a+1 })  // → [[1 2] [4 5 6] [9]]
+func ChunkBy[S ~[]E, E any](s S, sameGroup func(previous, current E) bool) []S {
+	var chunks []S
+	start := 0
+	for i := 1; i < len(s); i++ {
+		if !sameGroup(s[i-1], s[i]) {
+			chunks = append(chunks, s[start:i:i])
+			start = i
+		}
+	}
+	if start < len(s) {
+		chunks = append(chunks, s[start:len(s):len(s)])
+	}
+	return chunks
+}
+
 // DeleteAndSetToZero performs the same operation as slices.Delete (https://pkg.go.dev/golang.org/x/exp/slices#Delete),
 // except that deleted elements are set to the zero-value of type E.
 //
@@ -90,6 +114,113 @@ func FilterAndSetToZero[S ~[]E, E any](s S, keep func(E) bool) S {
 	return s[:n]
 }
 
+// Flatten concatenates all provided slices into a new slice.
+//
+// Example:
+//
+//	Flatten([][]int{{1, 2}, {3}, {}, {4, 5}})  // → [1 2 3 4 5]
+func Flatten[S ~[]E, E any](ss []S) S {
+	n := 0
+	for _, s := range ss {
+		n += len(s)
+	}
+
+	r := make(S, 0, n)
+	for _, s := range ss {
+		r = append(r, s...)
+	}
+	return r
+}
+
+// Interleave returns a new slice with elements taken alternately from the provided slices:
+// first elements of every slice, then second elements of every slice, and so on.
+// Exhausted slices are skipped.
+//
+// Example:
+//
+//	Interleave([]int{1, 2, 3}, []int{10, 20}, []int{100})  // → [1 10 100 2 20 3]
+func Interleave[S ~[]E, E any](ss ...S) S {
+	n, longest := 0, 0
+	for _, s := range ss {
+		n += len(s)
+		if len(s) > longest {
+			longest = len(s)
+		}
+	}
+
+	r := make(S, 0, n)
+	for i := 0; i < longest; i++ {
+		for _, s := range ss {
+			if i < len(s) {
+				r = append(r, s[i])
+			}
+		}
+	}
+	return r
+}
+
+// RemoveDuplicatesFunc modifies in-place a slice by removing elements
+// for which eq returns true when compared with any earlier element.
+// The first occurrence of every element is kept, and the order of elements is preserved.
+//
+// Use RemoveDuplicatesFuncAndSetToZero if elements contain pointers to other elements
+// to avoid memory leaks. See slices.CompactFunc (https://pkg.go.dev/golang.org/x/exp/slices#CompactFunc)
+// for removing only consecutive duplicates.
+//
+// Example:
+//
+//	RemoveDuplicatesFunc([]string{"a", "B", "A", "b", "c"}, strings.EqualFold)  // → [a B c]
+//
+// Complexity: O(n²)
+func RemoveDuplicatesFunc[S ~[]E, E any](s S, eq func(a, b E) bool) S {
+	n := 0
+outer:
+	for _, x := range s {
+		for _, y := range s[:n] {
+			if eq(y, x) {
+				continue outer
+			}
+		}
+		s[n] = x
+		n++
+	}
+	return s[:n]
+}
+
+// RemoveDuplicatesFuncAndSetToZero modifies in-place a slice by removing elements
+// for which eq returns true when compared with any earlier element.
+// Removed elements are set to zero.
+//
+// Complexity: O(n²)
+func RemoveDuplicatesFuncAndSetToZero[S ~[]E, E any](s S, eq func(a, b E) bool) S {
+	n := len(RemoveDuplicatesFunc(s, eq))
+
+	var zeroE E
+	for i, end := n, len(s); i < end; i++ {
+		s[i] = zeroE
+	}
+
+	return s[:n]
+}
+
+// Repeat returns a new slice with the elements of s repeated n times.
+// Panics if n is negative.
+//
+// Example:
+//
+//	Repeat([]int{1, 2}, 3)  // → [1 2 1 2 1 2]
+func Repeat[S ~[]E, E any](s S, n int) S {
+	if n < 0 {
+		panic("slices2: negative Repeat count")
+	}
+
+	r := make(S, 0, len(s)*n)
+	for i := 0; i < n; i++ {
+		r = append(r, s...)
+	}
+	return r
+}
+
 // Reverse reverses the order of a slice, in-place.
 //
 // Based on https://github.com/golang/go/wiki/SliceTricks#reversing
@@ -99,6 +230,29 @@ func Reverse[E any](s []E) {
 	}
 }
 
+// Rotate rotates a slice in-place by k positions to the left,
+// so that s[k] becomes the first element. Negative k rotates to the right.
+//
+// Example:
+//
+//	s := []int{1, 2, 3, 4, 5}
+//	Rotate(s, 2)  // s is now [3 4 5 1 2]
+//	Rotate(s, -2)  // s is now [1 2 3 4 5]
+func Rotate[E any](s []E, k int) {
+	if len(s) == 0 {
+		return
+	}
+
+	k %= len(s)
+	if k < 0 {
+		k += len(s)
+	}
+
+	Reverse(s[:k])
+	Reverse(s[k:])
+	Reverse(s)
+}
+
 // SlidingWindow returns all slices of s of length windowSize.
 //
 // If s is smaller than windowSize, returns a single window.
@@ -115,3 +269,38 @@ func SlidingWindow[S ~[]E, E any](s S, windowSize int) []S {
 	}
 	return r
 }
+
+// Unzip splits a slice of pairs into 2 slices, with the first and second elements of the pairs.
+//
+// Example:
+//
+//	Unzip([]iter.Pair[int, string]{{1, "a"}, {2, "b"}})  // → [1 2], [a b]
+func Unzip[T, U any](pairs []iter.Pair[T, U]) ([]T, []U) {
+	ts := make([]T, len(pairs))
+	us := make([]U, len(pairs))
+	for i, p := range pairs {
+		ts[i], us[i] = p.First, p.Second
+	}
+	return ts, us
+}
+
+// Zip returns a slice of pairs of corresponding elements from ts and us.
+// The result has the length of the shorter slice.
+//
+// Example:
+//
+//	Zip([]int{1, 2, 3}, []string{"a", "b"})  // → [{1 a} {2 b}]
+//
+// See also [iter.Pairwise], which performs the same operation on iterators.
+func Zip[T, U any](ts []T, us []U) []iter.Pair[T, U] {
+	n := len(ts)
+	if len(us) < n {
+		n = len(us)
+	}
+
+	r := make([]iter.Pair[T, U], n)
+	for i := range r {
+		r[i] = iter.Pair[T, U]{First: ts[i], Second: us[i]}
+	}
+	return r
+}
@@ -4,8 +4,10 @@
 package slices2_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/MKuranowski/go-extra-lib/iter"
 	"github.com/MKuranowski/go-extra-lib/slices2"
 	"github.com/MKuranowski/go-extra-lib/testing2/check"
 )
@@ -28,6 +30,13 @@ func TestBatchesUneven(t *testing.T) {
 	)
 }
 
+func TestChunkBy(t *testing.T) {
+	chunks := slices2.ChunkBy([]int{1, 2, 4, 5, 6, 9}, func(a, b int) bool { return b == a+1 })
+	check.DeepEqMsg(t, chunks, [][]int{{1, 2}, {4, 5, 6}, {9}}, "chunks")
+	check.EqMsg(t, cap(chunks[0]), 2, "cap(chunks[0])")
+	check.EqMsg(t, len(slices2.ChunkBy([]int{}, func(a, b int) bool { return true })), 0, "len(ChunkBy([]))")
+}
+
 func TestDeleteAndSetToZero(t *testing.T) {
 	old := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
 	new := slices2.DeleteAndSetToZero(old, 3, 6)
@@ -60,6 +69,37 @@ func TestFilterAndSetToZero(t *testing.T) {
 	check.DeepEqMsg(t, old, []int{2, 4, 6, 8, 0, 0, 0, 0}, "original slice")
 }
 
+func TestFlatten(t *testing.T) {
+	check.DeepEqMsg(t, slices2.Flatten([][]int{{1, 2}, {3}, {}, {4, 5}}), []int{1, 2, 3, 4, 5}, "after flatten")
+}
+
+func TestInterleave(t *testing.T) {
+	check.DeepEqMsg(
+		t,
+		slices2.Interleave([]int{1, 2, 3}, []int{10, 20}, []int{100}),
+		[]int{1, 10, 100, 2, 20, 3},
+		"after interleave",
+	)
+}
+
+func TestRemoveDuplicatesFunc(t *testing.T) {
+	s := []string{"a", "B", "A", "b", "c"}
+	s = slices2.RemoveDuplicatesFunc(s, strings.EqualFold)
+	check.DeepEqMsg(t, s, []string{"a", "B", "c"}, "after removing duplicates")
+}
+
+func TestRemoveDuplicatesFuncAndSetToZero(t *testing.T) {
+	old := []string{"a", "B", "A", "b", "c"}
+	new := slices2.RemoveDuplicatesFuncAndSetToZero(old, strings.EqualFold)
+	check.DeepEqMsg(t, new, []string{"a", "B", "c"}, "new slice")
+	check.DeepEqMsg(t, old, []string{"a", "B", "c", "", ""}, "original slice")
+}
+
+func TestRepeat(t *testing.T) {
+	check.DeepEqMsg(t, slices2.Repeat([]int{1, 2}, 3), []int{1, 2, 1, 2, 1, 2}, "after repeat")
+	check.EqMsg(t, len(slices2.Repeat([]int{1, 2}, 0)), 0, "len(Repeat(s, 0))")
+}
+
 func TestReverseEven(t *testing.T) {
 	s := []int{1, 2, 3, 4}
 	slices2.Reverse(s)
@@ -72,6 +112,17 @@ func TestReverseOdd(t *testing.T) {
 	check.DeepEqMsg(t, s, []int{5, 4, 3, 2, 1}, "after reverse")
 }
 
+func TestRotate(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	slices2.Rotate(s, 2)
+	check.DeepEqMsg(t, s, []int{3, 4, 5, 1, 2}, "after Rotate(s, 2)")
+	slices2.Rotate(s, -2)
+	check.DeepEqMsg(t, s, []int{1, 2, 3, 4, 5}, "after Rotate(s, -2)")
+	slices2.Rotate(s, 11)
+	check.DeepEqMsg(t, s, []int{2, 3, 4, 5, 1}, "after Rotate(s, 11)")
+	slices2.Rotate([]int{}, 3)
+}
+
 func TestSlidingWindow(t *testing.T) {
 	check.DeepEqMsg(
 		t,
@@ -89,3 +140,17 @@ func TestSlidingWindowSmall(t *testing.T) {
 		"windows",
 	)
 }
+
+func TestZip(t *testing.T) {
+	pairs := slices2.Zip([]int{1, 2, 3}, []string{"a", "b"})
+	check.DeepEqMsg(
+		t,
+		pairs,
+		[]iter.Pair[int, string]{{First: 1, Second: "a"}, {First: 2, Second: "b"}},
+		"after zip",
+	)
+
+	ints, strs := slices2.Unzip(pairs)
+	check.DeepEqMsg(t, ints, []int{1, 2}, "ints")
+	check.DeepEqMsg(t, strs, []string{"a", "b"}, "strs")
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package slices2
+
+import (
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+func less[E constraints.Ordered](a, b E) bool { return a < b }
+
+// UniqueSorted sorts s in-place and removes duplicate elements.
+//
+// Example:
+//
+//	UniqueSorted([]int{3, 1, 2, 3, 1})  // → [1 2 3]
+//
+// Complexity: O(n log n)
+func UniqueSorted[S ~[]E, E constraints.Ordered](s S) S {
+	slices.Sort(s)
+	return slices.Compact(s)
+}
+
+// UniqueSortedFunc sorts s in-place using the provided less function and removes duplicate elements.
+// Two elements are considered duplicates if neither is less than the other.
+//
+// Complexity: O(n log n)
+func UniqueSortedFunc[S ~[]E, E any](s S, less func(a, b E) bool) S {
+	slices.SortFunc(s, less)
+	return slices.CompactFunc(s, func(a, b E) bool { return !less(a, b) && !less(b, a) })
+}
+
+// IsStrictlySorted returns true if every element of s is greater than the previous one;
+// that is, s is sorted and doesn't contain duplicates.
+func IsStrictlySorted[E constraints.Ordered](s []E) bool {
+	return IsStrictlySortedFunc(s, less[E])
+}
+
+// IsStrictlySortedFunc returns true if every element of s is greater than the previous one,
+// as reported by the provided less function.
+func IsStrictlySortedFunc[E any](s []E, less func(a, b E) bool) bool {
+	for i := len(s) - 1; i > 0; i-- {
+		if !less(s[i-1], s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// MergeSorted returns a new sorted slice with all elements from sorted slices a and b.
+// On ties, elements from a come before elements from b.
+//
+// Example:
+//
+//	MergeSorted([]int{1, 3, 5}, []int{2, 3, 4})  // → [1 2 3 3 4 5]
+//
+// Complexity: O(len(a) + len(b))
+func MergeSorted[S ~[]E, E constraints.Ordered](a, b S) S {
+	return MergeSortedFunc(a, b, less[E])
+}
+
+// MergeSortedFunc returns a new slice with all elements from slices a and b,
+// both sorted according to the provided less function.
+// On ties, elements from a come before elements from b.
+//
+// Complexity: O(len(a) + len(b))
+func MergeSortedFunc[S ~[]E, E any](a, b S, less func(a, b E) bool) S {
+	r := make(S, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if less(b[0], a[0]) {
+			r, b = append(r, b[0]), b[1:]
+		} else {
+			r, a = append(r, a[0]), a[1:]
+		}
+	}
+	r = append(r, a...)
+	r = append(r, b...)
+	return r
+}
+
+// IntersectSorted returns a new sorted slice with elements present in both sorted slices a and b.
+// If an element is repeated, it's included min(count in a, count in b) times.
+//
+// Example:
+//
+//	IntersectSorted([]int{1, 2, 2, 3, 5}, []int{2, 2, 3, 4})  // → [2 2 3]
+//
+// Complexity: O(len(a) + len(b))
+func IntersectSorted[S ~[]E, E constraints.Ordered](a, b S) S {
+	return IntersectSortedFunc(a, b, less[E])
+}
+
+// IntersectSortedFunc returns a new slice with elements present in both slices a and b,
+// both sorted according to the provided less function.
+// If an element is repeated, it's included min(count in a, count in b) times,
+// with the elements taken from a.
+//
+// Complexity: O(len(a) + len(b))
+func IntersectSortedFunc[S ~[]E, E any](a, b S, less func(a, b E) bool) S {
+	var r S
+	for len(a) > 0 && len(b) > 0 {
+		if less(a[0], b[0]) {
+			a = a[1:]
+		} else if less(b[0], a[0]) {
+			b = b[1:]
+		} else {
+			r, a, b = append(r, a[0]), a[1:], b[1:]
+		}
+	}
+	return r
+}
+
+// DifferenceSorted returns a new sorted slice with elements of sorted slice a,
+// which are not present in sorted slice b.
+// If an element is repeated, it's included max(0, count in a - count in b) times.
+//
+// Example:
+//
+//	DifferenceSorted([]int{1, 2, 2, 3, 5}, []int{2, 3, 4})  // → [1 2 5]
+//
+// Complexity: O(len(a) + len(b))
+func DifferenceSorted[S ~[]E, E constraints.Ordered](a, b S) S {
+	return DifferenceSortedFunc(a, b, less[E])
+}
+
+// DifferenceSortedFunc returns a new slice with elements of a, which are not present in b;
+// both sorted according to the provided less function.
+// If an element is repeated, it's included max(0, count in a - count in b) times.
+//
+// Complexity: O(len(a) + len(b))
+func DifferenceSortedFunc[S ~[]E, E any](a, b S, less func(a, b E) bool) S {
+	var r S
+	for len(a) > 0 && len(b) > 0 {
+		if less(a[0], b[0]) {
+			r, a = append(r, a[0]), a[1:]
+		} else if less(b[0], a[0]) {
+			b = b[1:]
+		} else {
+			a, b = a[1:], b[1:]
+		}
+	}
+	return append(r, a...)
+}
+
+// LowerBound returns the index of the first element of sorted slice s
+// which is not less than x, or len(s) if there's no such element.
+//
+// Example:
+//
+//	LowerBound([]int{1, 2, 2, 2, 3}, 2)  // → 1
+//
+// Complexity: O(log n)
+func LowerBound[E constraints.Ordered](s []E, x E) int {
+	return LowerBoundFunc(s, x, less[E])
+}
+
+// LowerBoundFunc returns the index of the first element of s which is not less than x,
+// or len(s) if there's no such element. s must be sorted according to the provided less function.
+//
+// Complexity: O(log n)
+func LowerBoundFunc[E any](s []E, x E, less func(a, b E) bool) int {
+	lo, hi := 0, len(s)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if less(s[mid], x) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// UpperBound returns the index of the first element of sorted slice s
+// which is greater than x, or len(s) if there's no such element.
+//
+// Example:
+//
+//	UpperBound([]int{1, 2, 2, 2, 3}, 2)  // → 4
+//
+// Complexity: O(log n)
+func UpperBound[E constraints.Ordered](s []E, x E) int {
+	return UpperBoundFunc(s, x, less[E])
+}
+
+// UpperBoundFunc returns the index of the first element of s which is greater than x,
+// or len(s) if there's no such element. s must be sorted according to the provided less function.
+//
+// Complexity: O(log n)
+func UpperBoundFunc[E any](s []E, x E, less func(a, b E) bool) int {
+	lo, hi := 0, len(s)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if less(x, s[mid]) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
+// EqualRange returns the range of indices s[lo:hi] containing elements equal to x.
+// If there are no such elements, lo == hi == the index at which x would be inserted.
+//
+// Example:
+//
+//	EqualRange([]int{1, 2, 2, 2, 3}, 2)  // → 1, 4
+//
+// Complexity: O(log n)
+func EqualRange[E constraints.Ordered](s []E, x E) (lo, hi int) {
+	return EqualRangeFunc(s, x, less[E])
+}
+
+// EqualRangeFunc returns the range of indices s[lo:hi] containing elements equivalent to x
+// (neither less nor greater than x). s must be sorted according to the provided less function.
+//
+// Complexity: O(log n)
+func EqualRangeFunc[E any](s []E, x E, less func(a, b E) bool) (lo, hi int) {
+	lo = LowerBoundFunc(s, x, less)
+	hi = lo + UpperBoundFunc(s[lo:], x, less)
+	return
+}
+
+// InsertSorted inserts x into sorted slice s, keeping it sorted.
+// x is inserted after all elements equal to it.
+//
+// Example:
+//
+//	InsertSorted([]int{1, 2, 4}, 3)  // → [1 2 3 4]
+//
+// Complexity: O(n)
+func InsertSorted[S ~[]E, E constraints.Ordered](s S, x E) S {
+	return InsertSortedFunc(s, x, less[E])
+}
+
+// InsertSortedFunc inserts x into s, keeping it sorted according to the provided less function.
+// x is inserted after all elements equivalent to it.
+//
+// Complexity: O(n)
+func InsertSortedFunc[S ~[]E, E any](s S, x E, less func(a, b E) bool) S {
+	return slices.Insert(s, UpperBoundFunc(s, x, less), x)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2023 Mikołaj Kuranowski
+// SPDX-License-Identifier: MIT
+
+package slices2_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MKuranowski/go-extra-lib/slices2"
+	"github.com/MKuranowski/go-extra-lib/testing2/check"
+)
+
+func lessFold(a, b string) bool { return strings.ToLower(a) < strings.ToLower(b) }
+
+func TestUniqueSorted(t *testing.T) {
+	check.DeepEqMsg(t, slices2.UniqueSorted([]int{3, 1, 2, 3, 1}), []int{1, 2, 3}, "UniqueSorted")
+	check.DeepEqMsg(t, slices2.UniqueSorted([]int{}), []int{}, "UniqueSorted(empty)")
+	check.EqMsg(
+		t,
+		len(slices2.UniqueSortedFunc([]string{"b", "A", "a", "B", "c"}, lessFold)),
+		3,
+		"len(UniqueSortedFunc)",
+	)
+}
+
+func TestIsStrictlySorted(t *testing.T) {
+	check.TrueMsg(t, slices2.IsStrictlySorted([]int{}), "IsStrictlySorted([])")
+	check.TrueMsg(t, slices2.IsStrictlySorted([]int{1, 2, 5}), "IsStrictlySorted([1 2 5])")
+	check.FalseMsg(t, slices2.IsStrictlySorted([]int{1, 2, 2, 5}), "IsStrictlySorted([1 2 2 5])")
+	check.FalseMsg(t, slices2.IsStrictlySorted([]int{2, 1}), "IsStrictlySorted([2 1])")
+	check.FalseMsg(t, slices2.IsStrictlySortedFunc([]string{"a", "A"}, lessFold), "IsStrictlySortedFunc([a A])")
+}
+
+func TestMergeSorted(t *testing.T) {
+	check.DeepEqMsg(t, slices2.MergeSorted([]int{1, 3, 5}, []int{2, 3, 4}), []int{1, 2, 3, 3, 4, 5}, "MergeSorted")
+	check.DeepEqMsg(t, slices2.MergeSorted(nil, []int{1, 2}), []int{1, 2}, "MergeSorted(nil, ...)")
+	check.DeepEqMsg(
+		t,
+		slices2.MergeSortedFunc([]string{"a", "B"}, []string{"A", "b"}, lessFold),
+		[]string{"a", "A", "B", "b"},
+		"MergeSortedFunc (stability)",
+	)
+}
+
+func TestIntersectSorted(t *testing.T) {
+	check.DeepEqMsg(t, slices2.IntersectSorted([]int{1, 2, 2, 3, 5}, []int{2, 2, 3, 4}), []int{2, 2, 3}, "IntersectSorted")
+	check.EqMsg(t, len(slices2.IntersectSorted([]int{1, 3}, []int{2, 4})), 0, "len(IntersectSorted(disjoint))")
+}
+
+func TestDifferenceSorted(t *testing.T) {
+	check.DeepEqMsg(t, slices2.DifferenceSorted([]int{1, 2, 2, 3, 5}, []int{2, 3, 4}), []int{1, 2, 5}, "DifferenceSorted")
+	check.DeepEqMsg(t, slices2.DifferenceSorted([]int{1, 2}, nil), []int{1, 2}, "DifferenceSorted(..., nil)")
+}
+
+func TestBounds(t *testing.T) {
+	s := []int{1, 2, 2, 2, 3, 5}
+
+	for _, tc := range []struct {
+		x, lo, hi int
+	}{
+		{0, 0, 0},
+		{1, 0, 1},
+		{2, 1, 4},
+		{4, 5, 5},
+		{5, 5, 6},
+		{6, 6, 6},
+	} {
+		check.EqMsg(t, slices2.LowerBound(s, tc.x), tc.lo, "LowerBound")
+		check.EqMsg(t, slices2.UpperBound(s, tc.x), tc.hi, "UpperBound")
+		lo, hi := slices2.EqualRange(s, tc.x)
+		check.EqMsg(t, lo, tc.lo, "EqualRange lo")
+		check.EqMsg(t, hi, tc.hi, "EqualRange hi")
+	}
+
+	lo, hi := slices2.EqualRangeFunc([]string{"a", "b", "B", "c"}, "b", lessFold)
+	check.EqMsg(t, lo, 1, "EqualRangeFunc lo")
+	check.EqMsg(t, hi, 3, "EqualRangeFunc hi")
+}
+
+func TestInsertSorted(t *testing.T) {
+	var s []int
+	for _, x := range []int{3, 1, 4, 1, 5, 9, 2, 6} {
+		s = slices2.InsertSorted(s, x)
+	}
+	check.DeepEqMsg(t, s, []int{1, 1, 2, 3, 4, 5, 6, 9}, "after inserting")
+
+	check.DeepEqMsg(
+		t,
+		slices2.InsertSortedFunc([]string{"a", "b", "c"}, "B", lessFold),
+		[]string{"a", "b", "B", "c"},
+		"InsertSortedFunc",
+	)
+}